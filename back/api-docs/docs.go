@@ -18,20 +18,43 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/config/balance": {
+        "/admin/line/groups": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "config"
+                    "admin"
                 ],
-                "summary": "Get balance config",
+                "summary": "Get uploaded and published line game level groups",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/config.Balance"
+                            "$ref": "#/definitions/handler.GetLineGameGroupsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
                         }
                     },
                     "500": {
@@ -42,33 +65,40 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Validates every level against its answer. The draft is saved only if all levels are valid.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "config"
+                    "admin"
                 ],
-                "summary": "Update balance config",
+                "summary": "Upload line game level group as a draft",
                 "parameters": [
                     {
-                        "description": "Update balance config data",
+                        "description": "Level group in the levels file format",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/config.Balance"
+                            "$ref": "#/definitions/handler.LineGameGroup"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.UploadLineGameGroupResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -82,6 +112,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/http_errors.ResponseError"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -91,20 +133,60 @@ const docTemplate = `{
                 }
             }
         },
-        "/config/line": {
+        "/admin/line/groups/{group-code}": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the draft of the group or the published group if there is no draft.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "config"
+                    "admin"
+                ],
+                "summary": "Preview line game level group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "3_0_0",
+                        "description": "Group code",
+                        "name": "group-code",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get line game config",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/config.LineGame"
+                            "$ref": "#/definitions/handler.GetLineGameGroupResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
                         }
                     },
                     "500": {
@@ -114,43 +196,54 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/line/groups/{group-code}/publish": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Replaces published levels of the group with the draft.",
                 "tags": [
-                    "config"
+                    "admin"
                 ],
-                "summary": "Update line game config",
+                "summary": "Publish draft of line game level group",
                 "parameters": [
                     {
-                        "description": "Update line game config data",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/config.LineGame"
-                        }
+                        "type": "string",
+                        "example": "3_0_0",
+                        "description": "Group code",
+                        "name": "group-code",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created"
+                    "200": {
+                        "description": "OK"
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/http_errors.ResponseError"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/http_errors.ResponseError"
                         }
@@ -164,20 +257,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/config/price": {
+        "/admin/quiz/review": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns quizzes submitted for review, the oldest quiz is first. Quizzes are approved or rejected\nvia PUT /game/quiz/{quiz-id}/status.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "config"
+                    "admin"
+                ],
+                "summary": "Get quizzes for review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Count of quizzes, 20 by default and 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get price config",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/config.ItemsPrice"
+                            "$ref": "#/definitions/handler.GetQuizListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
                         }
                     },
                     "500": {
@@ -187,8 +312,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/users/{user-id}/boosters": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -197,24 +324,37 @@ const docTemplate = `{
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "config"
+                    "admin"
                 ],
-                "summary": "Update price config",
+                "summary": "Gift boosters to user",
                 "parameters": [
                     {
-                        "description": "Update price config data",
+                        "type": "string",
+                        "description": "User id",
+                        "name": "user-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Boosters",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/config.ItemsPrice"
+                            "$ref": "#/definitions/handler.GiftBoostersRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetInventoryResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -228,6 +368,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/http_errors.ResponseError"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -237,7 +389,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/config/quiz": {
+        "/config/balance": {
             "get": {
                 "produces": [
                     "application/json"
@@ -245,12 +397,12 @@ const docTemplate = `{
                 "tags": [
                     "config"
                 ],
-                "summary": "Get quiz config",
+                "summary": "Get balance config",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/config.Quiz"
+                            "$ref": "#/definitions/config.Balance"
                         }
                     },
                     "500": {
@@ -273,15 +425,15 @@ const docTemplate = `{
                 "tags": [
                     "config"
                 ],
-                "summary": "Update quiz config",
+                "summary": "Update balance config",
                 "parameters": [
                     {
-                        "description": "Update quiz config data",
+                        "description": "Update balance config data",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/config.Quiz"
+                            "$ref": "#/definitions/config.Balance"
                         }
                     }
                 ],
@@ -310,44 +462,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/game/balance": {
+        "/config/boosters": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Returns balance of the user.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "balance"
+                    "config"
                 ],
-                "summary": "Get current user balance",
+                "summary": "Get boosters config",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.GetUserBalanceResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/http_errors.ResponseError"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/http_errors.ResponseError"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/http_errors.ResponseError"
+                            "$ref": "#/definitions/config.Boosters"
                         }
                     },
                     "500": {
@@ -357,28 +485,34 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/game/line/hint": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "produces": [
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "line-game"
+                    "config"
                 ],
-                "summary": "Get current user level's hint",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                "summary": "Update boosters config",
+                "parameters": [
+                    {
+                        "description": "Update boosters config data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.GetLevelHintResponse"
+                            "$ref": "#/definitions/config.Boosters"
                         }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -401,48 +535,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/game/line/level": {
+        "/config/energy": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "line-game"
+                    "config"
                 ],
-                "summary": "Get current user level",
+                "summary": "Get energy config",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.GetUserLevelResponse"
+                            "$ref": "#/definitions/config.Energy"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/http_errors.ResponseError"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/http_errors.ResponseError"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/http_errors.ResponseError"
                         }
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -451,30 +568,24 @@ const docTemplate = `{
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "line-game"
+                    "config"
                 ],
-                "summary": "Complete current user level",
+                "summary": "Update energy config",
                 "parameters": [
                     {
-                        "description": "Complete level data",
+                        "description": "Update energy config data",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.CompleteLevelRequest"
+                            "$ref": "#/definitions/config.Energy"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handler.CompleteLevelResponse"
-                        }
+                    "201": {
+                        "description": "Created"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -497,20 +608,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/game/line/time-stop-booster": {
+        "/config/line": {
             "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Get line game config",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/config.LineGame"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Rewards of completed levels are taken from group_rewards by the group code, then by the first\nmatching range of field size, orders and blockers, the global rewards_conditions are the fallback.",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "line-game"
+                    "config"
+                ],
+                "summary": "Update line game config",
+                "parameters": [
+                    {
+                        "description": "Update line game config data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/config.LineGame"
+                        }
+                    }
                 ],
-                "summary": "Spend money on stop time booster",
                 "responses": {
-                    "200": {
-                        "description": "OK"
+                    "201": {
+                        "description": "Created"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -533,20 +682,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/game/quiz": {
+        "/config/price": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "quiz"
+                    "config"
                 ],
-                "summary": "Get quiz",
+                "summary": "Get price config",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.GetQuizResponse"
+                            "$ref": "#/definitions/config.ItemsPrice"
                         }
                     },
                     "500": {
@@ -567,17 +716,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "quiz"
+                    "config"
                 ],
-                "summary": "Update quiz",
+                "summary": "Update price config",
                 "parameters": [
                     {
-                        "description": "Update quiz data",
+                        "description": "Update price config data",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.UpdateQuizRequest"
+                            "$ref": "#/definitions/config.ItemsPrice"
                         }
                     }
                 ],
@@ -604,8 +753,33 @@ const docTemplate = `{
                         }
                     }
                 }
+            }
+        },
+        "/config/quiz": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Get quiz config",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/config.Quiz"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -615,17 +789,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "quiz"
+                    "config"
                 ],
-                "summary": "Add quiz",
+                "summary": "Update quiz config",
                 "parameters": [
                     {
-                        "description": "Add quiz data",
+                        "description": "Update quiz config data",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.AddQuizRequest"
+                            "$ref": "#/definitions/config.Quiz"
                         }
                     }
                 ],
@@ -654,39 +828,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/game/quiz/answer": {
-            "post": {
+        "/game/balance": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns balance of the user.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "quiz"
-                ],
-                "summary": "Complete current user quiz",
-                "parameters": [
-                    {
-                        "description": "Complete quiz data",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.AnswerQuizRequest"
-                        }
-                    }
+                    "balance"
                 ],
+                "summary": "Get current user balance",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.AnswerQuizResponse"
+                            "$ref": "#/definitions/handler.GetUserBalanceResponse"
                         }
                     },
                     "400": {
@@ -701,6 +862,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/http_errors.ResponseError"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -710,35 +877,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/user": {
+        "/game/energy": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns info of the user.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Starting a line game level costs energy, it regenerates over time up to the max.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "energy"
                 ],
-                "summary": "Get current user info",
+                "summary": "Get energy",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.GetUserInfoResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/http_errors.ResponseError"
+                            "$ref": "#/definitions/handler.GetEnergyResponse"
                         }
                     },
                     "401": {
@@ -756,33 +914,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/register/email": {
+        "/game/energy/refill": {
             "post": {
-                "description": "Registers a new user by email and password.",
-                "consumes": [
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fills the energy up to the max for soft currency.",
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "energy"
                 ],
-                "summary": "Register by email and password",
-                "parameters": [
-                    {
-                        "description": "Registration data",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
+                "summary": "Buy energy refill",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.RegisterUserRequest"
+                            "$ref": "#/definitions/handler.GetEnergyResponse"
                         }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "created"
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/http_errors.ResponseError"
                         }
@@ -796,21 +963,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/token/anonymous": {
+        "/game/inventory": {
             "get": {
-                "description": "Creates a new anonymous user and returns an auth token.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns counts of every booster of the catalog, boosters are used via /game/line/booster/{booster-id}.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "inventory"
                 ],
-                "summary": "Get anonymous user token",
+                "summary": "Get booster inventory",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.RegisterAnonymousResponse"
+                            "$ref": "#/definitions/handler.GetInventoryResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
                         }
                     },
                     "500": {
@@ -822,39 +1000,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/token/email": {
+        "/game/inventory/bundles/{bundle-id}": {
             "post": {
-                "description": "Authenticates a registered user and returns an auth token.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Spends soft currency and puts boosters of the bundle into the inventory in one transaction.\nBundles are configured in /config/boosters.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "inventory"
                 ],
-                "summary": "Authenticate by email and password",
+                "summary": "Buy booster bundle",
                 "parameters": [
                     {
-                        "description": "Credentials",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.AuthenticateUserRequest"
-                        }
+                        "type": "string",
+                        "description": "Bundle id",
+                        "name": "bundle-id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.AuthenticateUserResponse"
+                            "$ref": "#/definitions/handler.GetInventoryResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/http_errors.ResponseError"
                         }
@@ -867,302 +1057,3751 @@ const docTemplate = `{
                     }
                 }
             }
-        }
+        },
+        "/game/line/booster/{booster-id}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Boosters are configured in /config/boosters and bought in bundles via /game/inventory.\nEffects \"time_stop\" and \"undo\" are applied by the client, \"skip_level\" moves to the next level\nwithout a reward and \"reveal_checkpoint\" continues the path up to the next order cell or the end cell\nlike the checkpoint hint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "line-game"
+                ],
+                "summary": "Use a booster from the inventory for the current level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booster id",
+                        "name": "booster-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User path for reveal_checkpoint",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.UseBoosterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.UseBoosterResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/line/daily": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The level is the same for every user on a day.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "line-game"
+                ],
+                "summary": "Get daily level",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetDailyLevelResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The answer is always checked. The level can be completed once a day.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "line-game"
+                ],
+                "summary": "Complete daily level",
+                "parameters": [
+                    {
+                        "description": "Complete level data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CompleteDailyLevelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CompleteDailyLevelResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/line/hint": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The whole answer is paid as the full hint once for the level attempt.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "line-game"
+                ],
+                "summary": "Get current user level's hint",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetLevelHintResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Hint \"steps\" reveals the next few cells, \"checkpoint\" reveals cells up to the next order cell\nor the end cell and \"full\" reveals the whole way. When the path can not be finished,\nthe hint continues its longest part which can be. Every hint type is paid once for the level\nattempt, the bought hint is returned again for free.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "line-game"
+                ],
+                "summary": "Buy a hint continuing the user path on the current level",
+                "parameters": [
+                    {
+                        "description": "Hint type and user path",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.BuyLevelHintRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.LevelHint"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/line/level": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The first request of the level attempt costs energy when the energy is enabled\nand starts the time of the attempt.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "line-game"
+                ],
+                "summary": "Get current user level",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetUserLevelResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The completion time is counted from the first request of the level, the level must be requested before.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "line-game"
+                ],
+                "summary": "Complete current user level",
+                "parameters": [
+                    {
+                        "description": "Complete level data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CompleteLevelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CompleteLevelResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/line/stars": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the best rating of every completed level for the level map.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "line-game"
+                ],
+                "summary": "Get star ratings of completed levels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group code, all groups when empty",
+                        "name": "group_code",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetLevelStarsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/quiz": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Quizzes which the user has not answered yet are returned first.\nCategory and difficulty limit the choice, 404 is returned when no quiz matches them.\nThe answer description is returned only with the answer.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Get quiz",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Quiz category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "easy",
+                            "medium",
+                            "hard"
+                        ],
+                        "type": "string",
+                        "description": "Quiz difficulty",
+                        "name": "difficulty",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetQuizResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Quiz writers can edit only their drafts, admins can edit any quiz.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Update quiz",
+                "parameters": [
+                    {
+                        "description": "Update quiz data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateQuizRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The quiz is saved as a draft of the user, it is shown to players after it is submitted for review\nvia PUT /game/quiz/{quiz-id}/status and approved by an admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Add quiz",
+                "parameters": [
+                    {
+                        "description": "Add quiz data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.AddQuizRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.AddQuizResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/quiz/answer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Every answer gets the explanation and the info link, retries are rewarded less.\nThe correct answer is returned for wrong answers only when no rewarded retry is possible.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Complete current user quiz",
+                "parameters": [
+                    {
+                        "description": "Complete quiz data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.AnswerQuizRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.AnswerQuizResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/quiz/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns quizzes of any status found like in GET /game/quiz/list as a file, the newest quiz is first.\nCSV has the header and the same columns as the import with id and status, JSON is an array.\nText values of CSV starting with \"=\", \"+\", \"-\" or \"@\" have a leading quote.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Export quizzes",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "File format, json by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text in the question or the answer description",
+                        "name": "text",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quiz category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "draft",
+                            "in_review",
+                            "published",
+                            "archived"
+                        ],
+                        "type": "string",
+                        "description": "Quiz status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Author id",
+                        "name": "author_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.QuizDetails"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/quiz/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Get quiz answer history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Count of the last answers, 20 by default and 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetQuizHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/quiz/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Saves a batch of quizzes as drafts of the user. The batch is a JSON array of quizzes like in\nPOST /game/quiz or a CSV file with the header, answers and tags are separated by \"|\" in CSV,\n\"|\" and \"\\\" in them are escaped by \"\\\". A quote before \"=\", \"+\", \"-\" or \"@\" at the start\nof a value is removed, exported values are protected from spreadsheet formulas by it.\nCSV columns are type, question, answers, correct_answer, correct_answers, numeric_answer,\nnumeric_tolerance, info_link, answer_description, category, difficulty and tags, options\nin correct_answers are separated by \"|\" too. Only question and info_link columns are required,\nother columns are ignored, so exported files can be imported back.\nEvery row is reported: invalid quizzes and quizzes which questions are already saved or\nare earlier in the batch are skipped. Questions are compared ignoring the case, spaces and\nthe final punctuation. Nothing is saved in the dry run.",
+                "consumes": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Import quizzes",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the batch",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Quizzes, at most 1000",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.AddQuizRequest"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ImportQuizzesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/quiz/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns quizzes of any status to quiz writers and admins, the newest quiz is first.\nThe text is searched in questions and answer descriptions ignoring the case.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Search quizzes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Text in the question or the answer description",
+                        "name": "text",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quiz category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "draft",
+                            "in_review",
+                            "published",
+                            "archived"
+                        ],
+                        "type": "string",
+                        "description": "Quiz status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Author id",
+                        "name": "author_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Count of quizzes on the page, 20 by default and 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Count of skipped quizzes",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SearchQuizzesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/quiz/round": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Resumes the round after a reconnect, questions which time is over are counted as wrong answers.\nWhen the time of the last question is over the finished round is returned once with the summary.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Get quiz round in progress",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.QuizRoundResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Starts a round of random quizzes answered one by one with a time limit per question.\nThe round has fewer questions when not enough quizzes match the category and the difficulty.\nOne round can be in progress, it is resumed via GET /game/quiz/round.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Start quiz round",
+                "parameters": [
+                    {
+                        "description": "Round params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.StartQuizRoundRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.QuizRoundResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/quiz/round/answer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the next question or the summary with the reward after the last question.\nThe reward is a sum of rewards of correct answers by difficulty with a bonus for the speed.\nLike single quizzes, only the first correct answer to a quiz is rewarded with the retry policy.\n409 is returned when the time of the question is over, the round is reloaded via GET then.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Answer current question of quiz round",
+                "parameters": [
+                    {
+                        "description": "Answer data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.AnswerQuizRoundRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.QuizRoundResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/quiz/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns stats of answers to quizzes for quiz writers and admins to find questions which are\ntoo hard or misleading. By default quizzes with the lowest correct rate are first, quizzes\nwithout answers are the last ones when sorted by the correct rate or the average time.\nStats are sorted by correct_rate in asc order by default.\nOnly answers to single quizzes are counted, answers in rounds are not.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Get quiz stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Quiz category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "draft",
+                            "in_review",
+                            "published",
+                            "archived"
+                        ],
+                        "type": "string",
+                        "description": "Quiz status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Min count of answers to the quiz",
+                        "name": "min_answers",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "correct_rate",
+                            "average_time",
+                            "answers",
+                            "shown"
+                        ],
+                        "type": "string",
+                        "description": "Sort field",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Count of quizzes on the page, 20 by default and 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Count of skipped quizzes",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetQuizStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/quiz/{quiz-id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft deletes the quiz: it is hidden from players and the review, but its history is kept and\nadmins can move it back to drafts. Quiz writers can archive only their drafts.",
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Archive quiz",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Quiz id",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/quiz/{quiz-id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Quizzes move from draft to in_review, then to published or back to draft, published ones are\narchived and archived ones go to draft. Quiz writers can only submit their drafts for review,\nadmins approve or reject quizzes in review, the rejection requires a comment.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Change quiz status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Quiz id",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ChangeQuizStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/quiz/{quiz-id}/transitions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns status changes of the quiz with comments of admins, the oldest change is first.\nIt is available to the author of the quiz and admins.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Get quiz moderation history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Quiz id",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetQuizStatusTransitionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/quiz/{quiz-id}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns every saved version of the quiz contents with its editor, the newest version is first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Get quiz edit history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Quiz id",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetQuizVersionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/leaderboard": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns best entries of the board and the entry of the user. Time boards are sorted ascending.\nThe daily_time board is always the board of the current daily level, the period is ignored for it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leaderboard"
+                ],
+                "summary": "Get leaderboard",
+                "parameters": [
+                    {
+                        "enum": [
+                            "level_time",
+                            "daily_time",
+                            "stars",
+                            "quiz"
+                        ],
+                        "type": "string",
+                        "description": "Board",
+                        "name": "board",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "global",
+                            "weekly"
+                        ],
+                        "type": "string",
+                        "description": "Period, global by default",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Level group code, required for level_time",
+                        "name": "group_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Level number for level_time",
+                        "name": "level_num",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entries count, 10 by default, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetLeaderboardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns info of the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get current user info",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetUserInfoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/register/email": {
+            "post": {
+                "description": "Registers a new user by email and password.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Register by email and password",
+                "parameters": [
+                    {
+                        "description": "Registration data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RegisterUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "created"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/token/anonymous": {
+            "get": {
+                "description": "Creates a new anonymous user and returns an auth token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get anonymous user token",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.RegisterAnonymousResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/token/email": {
+            "post": {
+                "description": "Authenticates a registered user and returns an auth token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Authenticate by email and password",
+                "parameters": [
+                    {
+                        "description": "Credentials",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.AuthenticateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.AuthenticateUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_errors.ResponseError"
+                        }
+                    }
+                }
+            }
+        }
     },
     "definitions": {
         "config.Balance": {
             "type": "object",
-            "required": [
-                "start_soft_currency"
-            ],
+            "required": [
+                "start_soft_currency"
+            ],
+            "properties": {
+                "start_soft_currency": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "config.Booster": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "effect": {
+                    "type": "string",
+                    "enum": [
+                        "time_stop",
+                        "undo",
+                        "skip_level",
+                        "reveal_checkpoint"
+                    ],
+                    "example": "skip_level"
+                },
+                "id": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "skip_level"
+                },
+                "limit_per_level": {
+                    "description": "LimitPerLevel is a max count of uses during one level attempt, zero is unlimited",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                }
+            }
+        },
+        "config.BoosterBundle": {
+            "type": "object",
+            "required": [
+                "booster_id",
+                "count",
+                "id"
+            ],
+            "properties": {
+                "booster_id": {
+                    "type": "string",
+                    "example": "skip_level"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "id": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "skip_level_3"
+                },
+                "price": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1000
+                }
+            }
+        },
+        "config.Boosters": {
+            "type": "object",
+            "properties": {
+                "bundles": {
+                    "description": "Bundles are sold for soft currency and put boosters into the user inventory",
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/config.BoosterBundle"
+                    }
+                },
+                "items": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/config.Booster"
+                    }
+                }
+            }
+        },
+        "config.Energy": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "level_cost": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "max": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 5
+                },
+                "regen_seconds": {
+                    "description": "RegenSeconds is a time in seconds of regeneration of one energy unit",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 600
+                }
+            }
+        },
+        "config.ItemsPrice": {
+            "type": "object",
+            "required": [
+                "energy_refill_price",
+                "line_game_checkpoint_hint_price",
+                "line_game_hint_price",
+                "line_game_steps_hint_price"
+            ],
+            "properties": {
+                "energy_refill_price": {
+                    "description": "EnergyRefillPrice is a price of filling the energy up to the max",
+                    "type": "integer",
+                    "example": 150
+                },
+                "line_game_checkpoint_hint_price": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "line_game_hint_price": {
+                    "description": "LineGameHintPrice is a price of the full answer hint",
+                    "type": "integer",
+                    "example": 40
+                },
+                "line_game_steps_hint_price": {
+                    "type": "integer",
+                    "example": 20
+                }
+            }
+        },
+        "config.LineGame": {
+            "type": "object",
+            "properties": {
+                "check_answer": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "daily": {
+                    "$ref": "#/definitions/config.LineGameDaily"
+                },
+                "endless": {
+                    "$ref": "#/definitions/config.LineGameEndless"
+                },
+                "group_rewards": {
+                    "description": "GroupRewards replace the global rewards conditions for matching level groups",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.LineGameGroupRewards"
+                    }
+                },
+                "hint_steps": {
+                    "description": "HintSteps is a count of cells revealed by the steps hint, zero means 3 cells",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3
+                },
+                "progression": {
+                    "$ref": "#/definitions/config.LineGameProgression"
+                },
+                "rewards_conditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.LineGameRewardCondition"
+                    }
+                },
+                "stars": {
+                    "$ref": "#/definitions/config.LineGameStars"
+                }
+            }
+        },
+        "config.LineGameDaily": {
+            "type": "object",
+            "properties": {
+                "blockers": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "field_size": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 6
+                },
+                "max_streak_bonus": {
+                    "description": "MaxStreakBonus limits the sum of streak bonuses",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 100
+                },
+                "orders": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3
+                },
+                "rewards_conditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.LineGameRewardCondition"
+                    }
+                },
+                "streak_bonus": {
+                    "description": "StreakBonus is added to the reward for every day of the streak after the first one",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10
+                },
+                "utc_offset": {
+                    "description": "UTCOffset is an offset in hours of the time zone where a new day starts",
+                    "type": "integer",
+                    "maximum": 14,
+                    "minimum": -12,
+                    "example": 3
+                }
+            }
+        },
+        "config.LineGameEndless": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "levels_per_step": {
+                    "description": "LevelsPerStep is a count of levels before moving to the next step of difficulty",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.LineGameEndlessStep"
+                    }
+                }
+            }
+        },
+        "config.LineGameEndlessStep": {
+            "type": "object",
+            "required": [
+                "field_size"
+            ],
+            "properties": {
+                "blockers": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "field_size": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "orders": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2
+                }
+            }
+        },
+        "config.LineGameGroupRewards": {
+            "type": "object",
+            "required": [
+                "rewards_conditions"
+            ],
+            "properties": {
+                "group_code": {
+                    "type": "string",
+                    "example": "5_6_0"
+                },
+                "max_blockers": {
+                    "type": "integer"
+                },
+                "max_field_size": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "max_orders": {
+                    "type": "integer"
+                },
+                "min_blockers": {
+                    "type": "integer"
+                },
+                "min_field_size": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "min_orders": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "rewards_conditions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/config.LineGameRewardCondition"
+                    }
+                }
+            }
+        },
+        "config.LineGameProgression": {
+            "type": "object",
+            "properties": {
+                "fast_cell_time": {
+                    "description": "FastCellTime is max seconds per cell of the way for a fast completion",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 0.5
+                },
+                "policy": {
+                    "description": "Policy is linear when empty",
+                    "type": "string",
+                    "enum": [
+                        "linear",
+                        "adaptive"
+                    ],
+                    "example": "adaptive"
+                },
+                "recent_levels": {
+                    "description": "RecentLevels is a count of the last completed levels of the group used by the adaptive policy",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3
+                },
+                "slow_cell_time": {
+                    "description": "SlowCellTime is min seconds per cell of the way for a slow completion",
+                    "type": "number",
+                    "example": 3
+                }
+            }
+        },
+        "config.LineGameReward": {
+            "type": "object",
+            "required": [
+                "soft_currency"
+            ],
+            "properties": {
+                "boosters": {
+                    "description": "Boosters are counts of boosters by their ids put into the user inventory",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "soft_currency": {
+                    "type": "integer",
+                    "example": 40
+                }
+            }
+        },
+        "config.LineGameRewardCondition": {
+            "type": "object",
+            "required": [
+                "max_time",
+                "reward"
+            ],
+            "properties": {
+                "max_time": {
+                    "description": "MaxTime is max time in seconds",
+                    "type": "number",
+                    "example": 10
+                },
+                "reward": {
+                    "$ref": "#/definitions/config.LineGameReward"
+                }
+            }
+        },
+        "config.LineGameStars": {
+            "type": "object",
+            "properties": {
+                "by_reward_tier": {
+                    "description": "ByRewardTier is a count of stars for every index of rewards conditions, the last count is used for the rest\nof tiers. The first tier has 3 stars and every next one has one star less when it is empty.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        2,
+                        1
+                    ]
+                }
+            }
+        },
+        "config.Quiz": {
+            "type": "object",
+            "required": [
+                "soft_currency_reward"
+            ],
+            "properties": {
+                "difficulty_rewards": {
+                    "description": "DifficultyRewards replace the soft currency reward for quizzes of the difficulty",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/config.QuizDifficultyRewards"
+                        }
+                    ]
+                },
+                "retry_reward_percents": {
+                    "description": "RetryRewardPercents are percents of the reward for a correct answer after wrong ones, the first percent\nis for the second attempt and later attempts get the last one. Every attempt gets the full reward without them.",
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        50,
+                        0
+                    ]
+                },
+                "round": {
+                    "$ref": "#/definitions/config.QuizRound"
+                },
+                "soft_currency_reward": {
+                    "type": "integer",
+                    "example": 40
+                }
+            }
+        },
+        "config.QuizDifficultyRewards": {
+            "type": "object",
+            "properties": {
+                "easy": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 30
+                },
+                "hard": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 80
+                },
+                "medium": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 50
+                }
+            }
+        },
+        "config.QuizRound": {
+            "type": "object",
+            "required": [
+                "question_seconds",
+                "questions"
+            ],
+            "properties": {
+                "max_questions": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "question_seconds": {
+                    "description": "QuestionSeconds is a time limit in seconds to answer one question",
+                    "type": "integer",
+                    "example": 20
+                },
+                "questions": {
+                    "description": "Questions is a count of questions in a round when the user does not choose it",
+                    "type": "integer",
+                    "example": 5
+                },
+                "speed_bonus_percent": {
+                    "description": "SpeedBonusPercent is an extra reward percent for an instant answer, it falls to zero at the time limit",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 50
+                }
+            }
+        },
+        "handler.AddQuizRequest": {
+            "type": "object",
+            "required": [
+                "info_link",
+                "question"
+            ],
+            "properties": {
+                "answer_description": {
+                    "type": "string"
+                },
+                "answers": {
+                    "description": "Answers are options of the quiz, numeric quizzes have no options and true/false quizzes have 2 options",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "category": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "deposits"
+                },
+                "correct_answer": {
+                    "description": "CorrectAnswer is the correct option of single choice and true/false quizzes starting from 1",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2
+                },
+                "correct_answers": {
+                    "description": "CorrectAnswers are correct options of multiple choice quizzes or all options in the correct order\nfor ordering quizzes, the correct order must differ from the order of answers",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2
+                    ]
+                },
+                "difficulty": {
+                    "description": "Difficulty is medium by default",
+                    "type": "string",
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ],
+                    "example": "medium"
+                },
+                "info_link": {
+                    "type": "string"
+                },
+                "numeric_answer": {
+                    "description": "NumericAnswer is the answer to numeric quizzes, answers differing by NumericTolerance at most are correct",
+                    "type": "number",
+                    "example": 12.5
+                },
+                "numeric_tolerance": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 0.5
+                },
+                "question": {
+                    "type": "string",
+                    "maxLength": 130
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "savings",
+                        "interest"
+                    ]
+                },
+                "type": {
+                    "description": "Type is single_choice by default",
+                    "type": "string",
+                    "enum": [
+                        "single_choice",
+                        "multiple_choice",
+                        "true_false",
+                        "numeric",
+                        "ordering"
+                    ]
+                }
+            }
+        },
+        "handler.AddQuizResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.AnswerQuizRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "answer": {
+                    "description": "Answer is the chosen option of single choice and true/false quizzes",
+                    "type": "integer",
+                    "example": 2
+                },
+                "id": {
+                    "type": "string"
+                },
+                "number": {
+                    "description": "Number is the answer to numeric quizzes",
+                    "type": "number",
+                    "example": 12.5
+                },
+                "options": {
+                    "description": "Options are chosen options of multiple choice quizzes or all options in the order for ordering quizzes",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2
+                    ]
+                }
+            }
+        },
+        "handler.AnswerQuizResponse": {
+            "type": "object",
+            "properties": {
+                "answer_description": {
+                    "type": "string"
+                },
+                "attempt": {
+                    "description": "Attempt is a number of the answer to the quiz starting from 1, retries are rewarded less",
+                    "type": "integer",
+                    "example": 2
+                },
+                "correct": {
+                    "type": "boolean"
+                },
+                "correct_answer": {
+                    "description": "CorrectAnswer is the correct option of single choice and true/false quizzes",
+                    "type": "integer",
+                    "example": 2
+                },
+                "correct_answers": {
+                    "description": "CorrectAnswers are correct options of multiple choice quizzes or the correct order of ordering quizzes",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2
+                    ]
+                },
+                "info_link": {
+                    "type": "string"
+                },
+                "key_revealed": {
+                    "description": "KeyRevealed is false for wrong answers while a rewarded retry is possible, the correct answer fields\nare empty then",
+                    "type": "boolean"
+                },
+                "numeric_answer": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "numeric_tolerance": {
+                    "type": "number",
+                    "example": 0.5
+                },
+                "soft_currency": {
+                    "description": "SoftCurrency is zero for wrong answers, repeated correct answers and retries without a reward",
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "single_choice"
+                }
+            }
+        },
+        "handler.AnswerQuizRoundRequest": {
+            "type": "object",
+            "required": [
+                "quiz_id"
+            ],
+            "properties": {
+                "answer": {
+                    "description": "Answer is the chosen option of single choice and true/false quizzes",
+                    "type": "integer",
+                    "example": 2
+                },
+                "number": {
+                    "description": "Number is the answer to numeric quizzes",
+                    "type": "number",
+                    "example": 12.5
+                },
+                "options": {
+                    "description": "Options are chosen options of multiple choice quizzes or all options in the order for ordering quizzes",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2
+                    ]
+                },
+                "quiz_id": {
+                    "description": "QuizID is an id of the current question",
+                    "type": "string"
+                }
+            }
+        },
+        "handler.AuthenticateUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "test@test.ru"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8,
+                    "example": "TestTest123"
+                }
+            }
+        },
+        "handler.AuthenticateUserResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.BuyLevelHintRequest": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "path": {
+                    "description": "Path is the user path from the start cell, it is not used for the full hint",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.Cell"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "steps",
+                        "checkpoint",
+                        "full"
+                    ],
+                    "example": "steps"
+                }
+            }
+        },
+        "handler.Cell": {
+            "type": "object",
+            "properties": {
+                "x": {
+                    "type": "integer"
+                },
+                "y": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.ChangeQuizStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "comment": {
+                    "description": "Comment is required to reject a quiz in review by moving it back to the draft",
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "The link does not work"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "in_review",
+                        "published",
+                        "archived"
+                    ],
+                    "example": "in_review"
+                }
+            }
+        },
+        "handler.CompleteDailyLevelRequest": {
+            "type": "object",
+            "required": [
+                "time_since_start"
+            ],
+            "properties": {
+                "answer": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        }
+                    }
+                },
+                "time_since_start": {
+                    "description": "TimeSinceStart is the completion time in seconds with a fractional part for milliseconds",
+                    "type": "number",
+                    "example": 1.25
+                }
+            }
+        },
+        "handler.CompleteDailyLevelResponse": {
+            "type": "object",
+            "properties": {
+                "boosters": {
+                    "description": "Boosters are counts of rewarded boosters by their ids put into the inventory",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "soft_currency": {
+                    "type": "integer",
+                    "example": 220
+                },
+                "streak": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "handler.CompleteLevelRequest": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        }
+                    }
+                }
+            }
+        },
+        "handler.CompleteLevelResponse": {
+            "type": "object",
+            "properties": {
+                "boosters": {
+                    "description": "Boosters are counts of rewarded boosters by their ids put into the inventory",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "next_star": {
+                    "description": "NextStar is null when the completion has the best rating",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.StarThreshold"
+                        }
+                    ]
+                },
+                "soft_currency": {
+                    "type": "integer"
+                },
+                "stars": {
+                    "description": "Stars is a rating of the completion from 1 to 3 by the reward tier",
+                    "type": "integer",
+                    "example": 2
+                },
+                "time_since_start": {
+                    "description": "TimeSinceStart is the completion time in seconds counted by the server from the first level request",
+                    "type": "number",
+                    "example": 25.4
+                }
+            }
+        },
+        "handler.GetDailyLevelResponse": {
+            "type": "object",
+            "properties": {
+                "blockers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.Cell"
+                    }
+                },
+                "completed": {
+                    "type": "boolean"
+                },
+                "day": {
+                    "type": "string",
+                    "example": "2025-10-19"
+                },
+                "end_cell": {
+                    "$ref": "#/definitions/handler.Cell"
+                },
+                "field_size": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "order": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.Cell"
+                    }
+                },
+                "start_cell": {
+                    "$ref": "#/definitions/handler.Cell"
+                },
+                "streak": {
+                    "description": "Streak is a count of consecutive days with completed daily level",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "handler.GetEnergyResponse": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "Enabled is false when levels are played without energy",
+                    "type": "boolean",
+                    "example": true
+                },
+                "energy": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "level_cost": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "max_energy": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "next_refill_at": {
+                    "description": "NextRefillAt is null when the energy is full",
+                    "type": "string",
+                    "example": "2025-10-19T12:10:00Z"
+                },
+                "seconds_to_next_refill": {
+                    "description": "SecondsToNextRefill is zero when the energy is full",
+                    "type": "integer",
+                    "example": 540
+                }
+            }
+        },
+        "handler.GetInventoryResponse": {
+            "type": "object",
+            "properties": {
+                "boosters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.InventoryBooster"
+                    }
+                }
+            }
+        },
+        "handler.GetLeaderboardResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.LeaderboardEntry"
+                    }
+                },
+                "user": {
+                    "description": "User is null when the user has no score on the board",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.LeaderboardEntry"
+                        }
+                    ]
+                }
+            }
+        },
+        "handler.GetLevelHintResponse": {
+            "type": "object",
+            "required": [
+                "answer"
+            ],
+            "properties": {
+                "answer": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        }
+                    }
+                }
+            }
+        },
+        "handler.GetLevelStarsResponse": {
+            "type": "object",
+            "properties": {
+                "levels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.LevelStars"
+                    }
+                }
+            }
+        },
+        "handler.GetLineGameGroupResponse": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "$ref": "#/definitions/handler.LineGameGroup"
+                },
+                "group_code": {
+                    "type": "string",
+                    "example": "3_0_0"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "draft"
+                }
+            }
+        },
+        "handler.GetLineGameGroupsResponse": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.LineGameGroupInfo"
+                    }
+                }
+            }
+        },
+        "handler.GetQuizHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.QuizHistoryAnswer"
+                    }
+                }
+            }
+        },
+        "handler.GetQuizListResponse": {
+            "type": "object",
+            "properties": {
+                "quizzes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.QuizDetails"
+                    }
+                }
+            }
+        },
+        "handler.GetQuizResponse": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "description": "Answers are options of the quiz, numeric quizzes have no options",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "category": {
+                    "type": "string",
+                    "example": "deposits"
+                },
+                "difficulty": {
+                    "type": "string",
+                    "example": "medium"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "info_link": {
+                    "type": "string"
+                },
+                "question": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "savings",
+                        "interest"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "example": "single_choice"
+                }
+            }
+        },
+        "handler.GetQuizStatsResponse": {
+            "type": "object",
+            "properties": {
+                "stats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.QuizStatsResponse"
+                    }
+                },
+                "total": {
+                    "description": "Total is a count of all found quizzes for the pagination",
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "handler.GetQuizStatusTransitionsResponse": {
+            "type": "object",
+            "properties": {
+                "transitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.QuizStatusTransitionResponse"
+                    }
+                }
+            }
+        },
+        "handler.GetQuizVersionsResponse": {
+            "type": "object",
+            "properties": {
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.QuizVersionResponse"
+                    }
+                }
+            }
+        },
+        "handler.GetUserBalanceResponse": {
+            "type": "object",
+            "properties": {
+                "soft_currency": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.GetUserInfoResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.GetUserLevelResponse": {
+            "type": "object",
+            "properties": {
+                "blockers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.Cell"
+                    }
+                },
+                "end_cell": {
+                    "$ref": "#/definitions/handler.Cell"
+                },
+                "endless": {
+                    "description": "Endless is true for generated levels after the last level group",
+                    "type": "boolean"
+                },
+                "field_size": {
+                    "type": "integer"
+                },
+                "hints": {
+                    "description": "Hints are bought for the current attempt of the level",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.LevelHint"
+                    }
+                },
+                "level_num": {
+                    "type": "integer"
+                },
+                "order": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.Cell"
+                    }
+                },
+                "start_cell": {
+                    "$ref": "#/definitions/handler.Cell"
+                }
+            }
+        },
+        "handler.GiftBoostersRequest": {
+            "type": "object",
+            "required": [
+                "booster_id",
+                "count"
+            ],
+            "properties": {
+                "booster_id": {
+                    "type": "string",
+                    "example": "skip_level"
+                },
+                "count": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "example": 3
+                }
+            }
+        },
+        "handler.ImportQuizzesResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "Created is a count of created quizzes or quizzes which would be created in the dry run",
+                    "type": "integer",
+                    "example": 40
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "duplicates": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "invalid": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.QuizImportRowResult"
+                    }
+                }
+            }
+        },
+        "handler.InventoryBooster": {
+            "type": "object",
             "properties": {
-                "start_soft_currency": {
+                "booster_id": {
+                    "type": "string",
+                    "example": "skip_level"
+                },
+                "count": {
                     "type": "integer",
-                    "example": 1
+                    "example": 2
+                },
+                "effect": {
+                    "type": "string",
+                    "example": "skip_level"
                 }
             }
         },
-        "config.ItemsPrice": {
+        "handler.LeaderboardEntry": {
             "type": "object",
-            "required": [
-                "line_game_hint_price",
-                "line_game_stop_time_booster_price"
-            ],
             "properties": {
-                "line_game_hint_price": {
+                "display_name": {
+                    "type": "string",
+                    "example": "ann***"
+                },
+                "rank": {
                     "type": "integer",
-                    "example": 40
+                    "example": 1
                 },
-                "line_game_stop_time_booster_price": {
+                "score": {
+                    "description": "Score is milliseconds for time boards, stars or correct answers count for others",
                     "type": "integer",
-                    "example": 40
+                    "example": 42
+                },
+                "time": {
+                    "description": "Time is the score of time boards in seconds, it is absent for other boards",
+                    "type": "number",
+                    "example": 4.25
                 }
             }
         },
-        "config.LineGame": {
+        "handler.LevelHint": {
             "type": "object",
-            "required": [
-                "check_answer",
-                "rewards_conditions"
-            ],
             "properties": {
-                "check_answer": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "rewards_conditions": {
+                "cells": {
+                    "description": "Cells continue the kept part of the path, the full hint has the whole way from the start cell",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/config.LineGameRewardCondition"
+                        "$ref": "#/definitions/handler.Cell"
                     }
+                },
+                "kept_cells": {
+                    "description": "KeptCells is a count of cells of the user path continued by the hint",
+                    "type": "integer",
+                    "example": 4
+                },
+                "price": {
+                    "description": "Price is paid for the hint once for the attempt of the level",
+                    "type": "integer",
+                    "example": 100
+                },
+                "type": {
+                    "type": "string",
+                    "example": "steps"
                 }
             }
         },
-        "config.LineGameReward": {
+        "handler.LevelStars": {
             "type": "object",
-            "required": [
-                "soft_currency"
-            ],
             "properties": {
-                "soft_currency": {
+                "best_time": {
+                    "description": "BestTime is the best completion time in seconds",
                     "type": "integer",
-                    "example": 40
+                    "example": 8
+                },
+                "group_code": {
+                    "type": "string",
+                    "example": "3_0_0"
+                },
+                "level_num": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "stars": {
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
-        "config.LineGameRewardCondition": {
+        "handler.LevelValidation": {
             "type": "object",
-            "required": [
-                "max_time",
-                "reward"
-            ],
             "properties": {
-                "max_time": {
-                    "description": "MaxTime is max time in seconds",
-                    "type": "number",
-                    "example": 10
+                "error": {
+                    "type": "string"
                 },
-                "reward": {
-                    "$ref": "#/definitions/config.LineGameReward"
+                "level_num": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "boolean"
                 }
             }
         },
-        "config.Quiz": {
+        "handler.LineGameGroup": {
             "type": "object",
             "required": [
-                "soft_currency_reward"
+                "field_size",
+                "levels"
             ],
             "properties": {
-                "soft_currency_reward": {
+                "blockers": {
                     "type": "integer",
-                    "example": 40
+                    "minimum": 0,
+                    "example": 0
+                },
+                "field_size": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "levels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.LineGameLevel"
+                    }
+                },
+                "orders": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
                 }
             }
         },
-        "handler.AddQuizRequest": {
+        "handler.LineGameGroupInfo": {
             "type": "object",
-            "required": [
-                "answers",
-                "correct_answer",
-                "info_link",
-                "question"
-            ],
             "properties": {
-                "answer_description": {
-                    "type": "string"
+                "group_code": {
+                    "type": "string",
+                    "example": "3_0_0"
                 },
-                "answers": {
-                    "type": "array",
-                    "maxItems": 50,
-                    "items": {
-                        "type": "string"
-                    }
+                "levels_count": {
+                    "type": "integer",
+                    "example": 10
                 },
-                "correct_answer": {
-                    "type": "integer"
+                "status": {
+                    "type": "string",
+                    "example": "published"
                 },
-                "info_link": {
+                "updated_at": {
                     "type": "string"
-                },
-                "question": {
-                    "type": "string",
-                    "maxLength": 130
                 }
             }
         },
-        "handler.AnswerQuizRequest": {
+        "handler.LineGameLevel": {
             "type": "object",
-            "required": [
-                "id"
-            ],
             "properties": {
                 "answer": {
-                    "type": "integer"
+                    "description": "Answer is answers double slice with number-side quality:\n0 - up, 1 - right, 2 - down, 3 - left, 4 - finish, 5 - block",
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        }
+                    }
                 },
-                "id": {
-                    "type": "string"
+                "blockers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.Cell"
+                    }
+                },
+                "end_cell": {
+                    "$ref": "#/definitions/handler.Cell"
+                },
+                "field_size": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "order": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.Cell"
+                    }
+                },
+                "start_cell": {
+                    "$ref": "#/definitions/handler.Cell"
                 }
             }
         },
-        "handler.AnswerQuizResponse": {
+        "handler.QuizAnswerOptionStats": {
             "type": "object",
             "properties": {
-                "soft_currency": {
-                    "type": "integer"
+                "answer": {
+                    "type": "string"
+                },
+                "correct": {
+                    "type": "boolean"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 37
+                },
+                "option": {
+                    "description": "Option is a number of the answer starting from 1",
+                    "type": "integer",
+                    "example": 2
+                },
+                "rate": {
+                    "description": "Rate is a share of answers with the option from 0 to 1",
+                    "type": "number",
+                    "example": 0.25
                 }
             }
         },
-        "handler.AuthenticateUserRequest": {
+        "handler.QuizContent": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "answer_description": {
+                    "type": "string"
+                },
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "category": {
                     "type": "string",
-                    "example": "test@test.ru"
+                    "example": "deposits"
                 },
-                "password": {
+                "correct_answer": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "correct_answers": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2
+                    ]
+                },
+                "difficulty": {
                     "type": "string",
-                    "minLength": 8,
-                    "example": "TestTest123"
-                }
-            }
-        },
-        "handler.AuthenticateUserResponse": {
-            "type": "object",
-            "properties": {
-                "token": {
+                    "example": "medium"
+                },
+                "info_link": {
+                    "type": "string"
+                },
+                "numeric_answer": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "numeric_tolerance": {
+                    "type": "number",
+                    "example": 0.5
+                },
+                "question": {
                     "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "savings",
+                        "interest"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "example": "single_choice"
                 }
             }
         },
-        "handler.Cell": {
+        "handler.QuizDetails": {
             "type": "object",
             "properties": {
-                "x": {
-                    "type": "integer"
+                "answer_description": {
+                    "type": "string"
                 },
-                "y": {
-                    "type": "integer"
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "author_id": {
+                    "description": "AuthorID is absent for quizzes added before the moderation",
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string",
+                    "example": "deposits"
+                },
+                "correct_answer": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "correct_answers": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2
+                    ]
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-10-19T12:00:00Z"
+                },
+                "difficulty": {
+                    "type": "string",
+                    "example": "medium"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "info_link": {
+                    "type": "string"
+                },
+                "numeric_answer": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "numeric_tolerance": {
+                    "type": "number",
+                    "example": 0.5
+                },
+                "question": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "in_review"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "savings",
+                        "interest"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "example": "single_choice"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
-        "handler.CompleteLevelRequest": {
+        "handler.QuizHistoryAnswer": {
             "type": "object",
-            "required": [
-                "time_since_start"
-            ],
             "properties": {
                 "answer": {
+                    "description": "Answer is the chosen option of single choice and true/false quizzes",
+                    "type": "integer",
+                    "example": 2
+                },
+                "answered_at": {
+                    "type": "string",
+                    "example": "2025-10-19T12:00:00Z"
+                },
+                "correct": {
+                    "type": "boolean"
+                },
+                "number": {
+                    "description": "Number is the answer to numeric quizzes",
+                    "type": "number",
+                    "example": 12.5
+                },
+                "options": {
+                    "description": "Options are chosen options of multiple choice quizzes or all options in the order for ordering quizzes",
                     "type": "array",
+                    "maxItems": 50,
                     "items": {
-                        "type": "array",
-                        "items": {
-                            "type": "integer"
-                        }
-                    }
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2
+                    ]
                 },
-                "time_since_start": {
-                    "type": "integer",
-                    "example": 1
+                "quiz_id": {
+                    "type": "string"
+                },
+                "rewarded": {
+                    "type": "boolean"
                 }
             }
         },
-        "handler.CompleteLevelResponse": {
+        "handler.QuizImportRowResult": {
             "type": "object",
             "properties": {
-                "soft_currency": {
-                    "type": "integer"
+                "duplicate_of_quiz_id": {
+                    "description": "DuplicateOfQuizID is an id of the saved quiz with the same question",
+                    "type": "string"
+                },
+                "duplicate_of_row": {
+                    "description": "DuplicateOfRow is a number of the earlier row of the batch with the same question",
+                    "type": "integer",
+                    "example": 1
+                },
+                "error": {
+                    "type": "string"
+                },
+                "quiz_id": {
+                    "description": "QuizID is an id of the created quiz",
+                    "type": "string"
+                },
+                "row": {
+                    "description": "Row is a number of the quiz in the batch starting from 1, the CSV header is not counted",
+                    "type": "integer",
+                    "example": 3
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "created",
+                        "valid",
+                        "duplicate",
+                        "invalid"
+                    ],
+                    "example": "duplicate"
                 }
             }
         },
-        "handler.GetLevelHintResponse": {
+        "handler.QuizRoundAnswerResult": {
             "type": "object",
-            "required": [
-                "answer"
-            ],
             "properties": {
                 "answer": {
+                    "description": "Answer is the chosen option of single choice and true/false quizzes",
+                    "type": "integer",
+                    "example": 2
+                },
+                "correct": {
+                    "type": "boolean"
+                },
+                "number": {
+                    "description": "Number is the answer to numeric quizzes",
+                    "type": "number",
+                    "example": 12.5
+                },
+                "options": {
+                    "description": "Options are chosen options of multiple choice quizzes or all options in the order for ordering quizzes",
                     "type": "array",
+                    "maxItems": 50,
                     "items": {
-                        "type": "array",
-                        "items": {
-                            "type": "integer"
-                        }
-                    }
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2
+                    ]
+                },
+                "quiz_id": {
+                    "type": "string"
+                },
+                "reward": {
+                    "type": "integer",
+                    "example": 65
+                },
+                "time": {
+                    "description": "Time is a time in seconds spent on the answer",
+                    "type": "number",
+                    "example": 4.2
+                },
+                "timed_out": {
+                    "type": "boolean"
                 }
             }
         },
-        "handler.GetQuizResponse": {
+        "handler.QuizRoundQuestion": {
             "type": "object",
             "properties": {
-                "answer": {
+                "answers": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "answer_description": {
-                    "type": "string"
+                "category": {
+                    "type": "string",
+                    "example": "fraud"
+                },
+                "difficulty": {
+                    "type": "string",
+                    "example": "medium"
                 },
                 "id": {
                     "type": "string"
                 },
-                "info_link": {
-                    "type": "string"
+                "number": {
+                    "description": "Number is a number of the question in the round starting from 1",
+                    "type": "integer",
+                    "example": 2
                 },
                 "question": {
                     "type": "string"
+                },
+                "time_left": {
+                    "description": "TimeLeft is a time in seconds to answer the question",
+                    "type": "number",
+                    "example": 17.5
+                },
+                "type": {
+                    "type": "string",
+                    "example": "single_choice"
                 }
             }
         },
-        "handler.GetUserBalanceResponse": {
+        "handler.QuizRoundResponse": {
             "type": "object",
             "properties": {
-                "soft_currency": {
-                    "type": "integer"
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.QuizRoundAnswerResult"
+                    }
+                },
+                "category": {
+                    "type": "string",
+                    "example": "fraud"
+                },
+                "difficulty": {
+                    "type": "string",
+                    "example": "medium"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "question": {
+                    "description": "Question is the current question, it is absent when the round is finished",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.QuizRoundQuestion"
+                        }
+                    ]
+                },
+                "questions": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "summary": {
+                    "description": "Summary is present only when the round is finished",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.QuizRoundSummary"
+                        }
+                    ]
                 }
             }
         },
-        "handler.GetUserInfoResponse": {
+        "handler.QuizRoundSummary": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
+                "questions": {
+                    "type": "integer",
+                    "example": 5
                 },
-                "id": {
-                    "type": "string"
+                "score": {
+                    "description": "Score is a count of correct answers",
+                    "type": "integer",
+                    "example": 4
+                },
+                "soft_currency": {
+                    "type": "integer",
+                    "example": 260
+                },
+                "time": {
+                    "description": "Time is a time in seconds spent on all questions",
+                    "type": "number",
+                    "example": 42.7
                 }
             }
         },
-        "handler.GetUserLevelResponse": {
+        "handler.QuizStatsResponse": {
             "type": "object",
             "properties": {
-                "blockers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handler.Cell"
-                    }
+                "answers": {
+                    "type": "integer",
+                    "example": 148
                 },
-                "end_cell": {
-                    "$ref": "#/definitions/handler.Cell"
+                "average_time": {
+                    "description": "AverageTime is an average time in seconds from showing the quiz to the answer",
+                    "type": "number",
+                    "example": 12.4
                 },
-                "field_size": {
-                    "type": "integer"
+                "category": {
+                    "type": "string",
+                    "example": "deposits"
                 },
-                "level_num": {
-                    "type": "integer"
+                "correct": {
+                    "type": "integer",
+                    "example": 37
                 },
-                "order": {
+                "correct_rate": {
+                    "description": "CorrectRate is a share of correct answers from 0 to 1, it is absent for quizzes without answers",
+                    "type": "number",
+                    "example": 0.25
+                },
+                "difficulty": {
+                    "type": "string",
+                    "example": "medium"
+                },
+                "options": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handler.Cell"
+                        "$ref": "#/definitions/handler.QuizAnswerOptionStats"
                     }
                 },
-                "start_cell": {
-                    "$ref": "#/definitions/handler.Cell"
+                "question": {
+                    "type": "string"
+                },
+                "quiz_id": {
+                    "type": "string"
+                },
+                "shown": {
+                    "description": "Shown is a count of times the quiz was given to players",
+                    "type": "integer",
+                    "example": 180
+                },
+                "status": {
+                    "type": "string",
+                    "example": "published"
+                }
+            }
+        },
+        "handler.QuizStatusTransitionResponse": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "The link does not work"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-10-19T12:00:00Z"
+                },
+                "from": {
+                    "description": "From is empty for the creation of the quiz",
+                    "type": "string",
+                    "example": "in_review"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "draft"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.QuizVersionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-10-19T12:00:00Z"
+                },
+                "editor_id": {
+                    "type": "string"
+                },
+                "quiz": {
+                    "$ref": "#/definitions/handler.QuizContent"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
                 }
             }
         },
@@ -1192,6 +4831,61 @@ const docTemplate = `{
                 }
             }
         },
+        "handler.SearchQuizzesResponse": {
+            "type": "object",
+            "properties": {
+                "quizzes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.QuizDetails"
+                    }
+                },
+                "total": {
+                    "description": "Total is a count of all found quizzes for the pagination",
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "handler.StarThreshold": {
+            "type": "object",
+            "properties": {
+                "max_time": {
+                    "description": "MaxTime is seconds to complete the level faster than",
+                    "type": "number",
+                    "example": 10
+                },
+                "stars": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "handler.StartQuizRoundRequest": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "fraud"
+                },
+                "difficulty": {
+                    "type": "string",
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ],
+                    "example": "medium"
+                },
+                "questions": {
+                    "description": "Questions is a count of questions, the count from the config is used when it is zero",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 5
+                }
+            }
+        },
         "handler.UpdateQuizRequest": {
             "type": "object",
             "required": [
@@ -1202,13 +4896,44 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "answers": {
+                    "description": "Answers replace the options when they are present, an empty list removes them for numeric quizzes",
                     "type": "array",
+                    "maxItems": 50,
                     "items": {
                         "type": "string"
                     }
                 },
+                "category": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "deposits"
+                },
                 "correct_answer": {
-                    "type": "integer"
+                    "description": "CorrectAnswer is changed when it is present, zero is set when the type is changed to one without it",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2
+                },
+                "correct_answers": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2
+                    ]
+                },
+                "difficulty": {
+                    "type": "string",
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ],
+                    "example": "hard"
                 },
                 "id": {
                     "type": "string"
@@ -1216,8 +4941,99 @@ const docTemplate = `{
                 "info_link": {
                     "type": "string"
                 },
+                "numeric_answer": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "numeric_tolerance": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 0.5
+                },
                 "question": {
                     "type": "string"
+                },
+                "tags": {
+                    "description": "Tags replace the tags of the quiz when they are present, an empty list removes them",
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "fraud"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "single_choice",
+                        "multiple_choice",
+                        "true_false",
+                        "numeric",
+                        "ordering"
+                    ]
+                }
+            }
+        },
+        "handler.UploadLineGameGroupResponse": {
+            "type": "object",
+            "properties": {
+                "group_code": {
+                    "type": "string",
+                    "example": "3_0_0"
+                },
+                "levels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.LevelValidation"
+                    }
+                },
+                "saved": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handler.UseBoosterRequest": {
+            "type": "object",
+            "properties": {
+                "path": {
+                    "description": "Path is the user path from the start cell, it is used by the reveal checkpoint booster",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.Cell"
+                    }
+                }
+            }
+        },
+        "handler.UseBoosterResponse": {
+            "type": "object",
+            "properties": {
+                "booster_id": {
+                    "type": "string",
+                    "example": "skip_level"
+                },
+                "effect": {
+                    "type": "string",
+                    "example": "skip_level"
+                },
+                "hint": {
+                    "description": "Hint is revealed by the reveal_checkpoint booster",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.LevelHint"
+                        }
+                    ]
+                },
+                "inventory_left": {
+                    "description": "InventoryLeft is a count of boosters of the kind left in the inventory",
+                    "type": "integer",
+                    "example": 2
+                },
+                "uses_left": {
+                    "description": "UsesLeft is a count of uses left for the level attempt, it is -1 when the booster is unlimited",
+                    "type": "integer",
+                    "example": 0
                 }
             }
         },
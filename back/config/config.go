@@ -16,7 +16,7 @@ type App struct {
 
 type Authorization struct {
 	PrivateKeyPath string        `yaml:"private_key_path" env:"PRIVATE_KEY_PATH"`
-	PublicKeyPath  string        `yaml:"public_key_path" env:"PUBLIC_KEY_PATH""`
+	PublicKeyPath  string        `yaml:"public_key_path" env:"PUBLIC_KEY_PATH"`
 	TokenTTL       time.Duration `yaml:"token_ttl" env:"TOKEN_TTL"`
 }
 
@@ -24,6 +24,12 @@ type Database struct {
 	PostgresURL string `yaml:"host" env:"DB_URL"`
 }
 
+// Redis is used as a leaderboard cache, empty url disables it
+type Redis struct {
+	URL            string        `yaml:"url" env:"REDIS_URL"`
+	LeaderboardTTL time.Duration `yaml:"leaderboard_ttl" env-default:"10m"`
+}
+
 type Router struct {
 	RequestTimeout time.Duration `yaml:"request_timeout" default:"5s" env:"REQUEST_TIMEOUT"`
 }
@@ -33,6 +39,7 @@ type Config struct {
 	App           App           `yaml:"app"`
 	Authorization Authorization `yaml:"authorization"`
 	Postgres      Database      `yaml:"postgres"`
+	Redis         Redis         `yaml:"redis"`
 	Game          Game          `yaml:"game"`
 	Router        Router        `yaml:"router"`
 }
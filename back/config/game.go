@@ -1,20 +1,151 @@
 package config
 
+import (
+	"time"
+)
+
 type Game struct {
 	Balance           Balance    `yaml:"balance"`
 	LineGame          LineGame   `yaml:"line_game"`
 	ItemsPrice        ItemsPrice `yaml:"items_price"`
 	Quiz              Quiz       `yaml:"quiz"`
+	Energy            Energy     `yaml:"energy"`
+	Boosters          Boosters   `yaml:"boosters"`
 	LineGameLevelsDir string     `yaml:"levels_dir"`
+	// LineGameLevelsReloadInterval is a period of checking levels dir for changes, zero disables it
+	LineGameLevelsReloadInterval time.Duration `yaml:"levels_reload_interval"`
+	// QuizIndexReloadInterval is a period of reloading ids of quizzes for the random selection,
+	// a negative value disables it
+	QuizIndexReloadInterval time.Duration `yaml:"quiz_index_reload_interval" env-default:"1m"`
+	// LineGameLevelsStorage is a source of line game levels: "file" or "postgres"
+	LineGameLevelsStorage string `yaml:"levels_storage" env-default:"file"`
 }
 
+const (
+	LineGameLevelsStorageFile     = "file"
+	LineGameLevelsStoragePostgres = "postgres"
+)
+
 type Balance struct {
 	StartSoftCurrency int `yaml:"start_soft_currency" json:"start_soft_currency" validate:"required,gt=0" example:"1"`
 }
 
 type LineGame struct {
-	CheckAnswer       bool                      `yaml:"check_answer" json:"check_answer" example:"false""`
+	CheckAnswer       bool                      `yaml:"check_answer" json:"check_answer" example:"false"`
 	RewardsConditions []LineGameRewardCondition `yaml:"rewards_conditions" json:"rewards_conditions"`
+	// GroupRewards replace the global rewards conditions for matching level groups
+	GroupRewards []LineGameGroupRewards `yaml:"group_rewards" json:"group_rewards" validate:"dive"`
+	Endless      LineGameEndless        `yaml:"endless" json:"endless"`
+	// HintSteps is a count of cells revealed by the steps hint, zero means 3 cells
+	HintSteps   int                 `yaml:"hint_steps" json:"hint_steps" env-default:"3" validate:"gte=0" example:"3"`
+	Daily       LineGameDaily       `yaml:"daily" json:"daily"`
+	Progression LineGameProgression `yaml:"progression" json:"progression"`
+	Stars       LineGameStars       `yaml:"stars" json:"stars"`
+}
+
+// LineGameGroupRewards are rewards conditions of level groups with the code or with params in the ranges.
+// Bounds of the ranges are inclusive, a nil bound is not checked.
+type LineGameGroupRewards struct {
+	GroupCode         string                    `yaml:"group_code" json:"group_code,omitempty" example:"5_6_0"`
+	MinFieldSize      *int                      `yaml:"min_field_size" json:"min_field_size,omitempty" example:"5"`
+	MaxFieldSize      *int                      `yaml:"max_field_size" json:"max_field_size,omitempty" example:"6"`
+	MinOrders         *int                      `yaml:"min_orders" json:"min_orders,omitempty" example:"4"`
+	MaxOrders         *int                      `yaml:"max_orders" json:"max_orders,omitempty"`
+	MinBlockers       *int                      `yaml:"min_blockers" json:"min_blockers,omitempty"`
+	MaxBlockers       *int                      `yaml:"max_blockers" json:"max_blockers,omitempty"`
+	RewardsConditions []LineGameRewardCondition `yaml:"rewards_conditions" json:"rewards_conditions" validate:"required,min=1,dive"`
+}
+
+// Matches reports whether the group params are in the ranges, the group code is not compared.
+func (g LineGameGroupRewards) Matches(fieldSize, orders, blockers int) bool {
+	return inRange(fieldSize, g.MinFieldSize, g.MaxFieldSize) &&
+		inRange(orders, g.MinOrders, g.MaxOrders) &&
+		inRange(blockers, g.MinBlockers, g.MaxBlockers)
+}
+
+func inRange(value int, minValue, maxValue *int) bool {
+	return (minValue == nil || value >= *minValue) && (maxValue == nil || value <= *maxValue)
+}
+
+// GroupRewardsConditions returns rewards conditions of the group with the code, then of the first group
+// with params in the ranges and the global ones when no group rewards match.
+func (l LineGame) GroupRewardsConditions(groupCode string, fieldSize, orders, blockers int) []LineGameRewardCondition {
+	for _, rewards := range l.GroupRewards {
+		if rewards.GroupCode != "" && rewards.GroupCode == groupCode {
+			return rewards.RewardsConditions
+		}
+	}
+	for _, rewards := range l.GroupRewards {
+		if rewards.GroupCode == "" && rewards.Matches(fieldSize, orders, blockers) {
+			return rewards.RewardsConditions
+		}
+	}
+	return l.RewardsConditions
+}
+
+// LineGameStars turns the reward tier of a completed level into a star rating from 1 to 3
+type LineGameStars struct {
+	// ByRewardTier is a count of stars for every index of rewards conditions, the last count is used for the rest
+	// of tiers. The first tier has 3 stars and every next one has one star less when it is empty.
+	ByRewardTier []int `yaml:"by_reward_tier" json:"by_reward_tier" validate:"dive,min=1,max=3" example:"3,2,1"`
+}
+
+// StarsByTier returns the count of stars for the index of rewards conditions.
+func (s LineGameStars) StarsByTier(tier int) int {
+	if tier < 0 {
+		return 0
+	}
+	if len(s.ByRewardTier) == 0 {
+		return max(3-tier, 1)
+	}
+	return s.ByRewardTier[min(tier, len(s.ByRewardTier)-1)]
+}
+
+const (
+	LineGameProgressionLinear   = "linear"
+	LineGameProgressionAdaptive = "adaptive"
+)
+
+// LineGameProgression chooses the level after the completed one. The linear policy plays every level,
+// the adaptive one skips the rest of the group for fast players and repeats the group for slow ones.
+type LineGameProgression struct {
+	// Policy is linear when empty
+	Policy string `yaml:"policy" json:"policy" validate:"omitempty,oneof=linear adaptive" example:"adaptive"`
+	// RecentLevels is a count of the last completed levels of the group used by the adaptive policy
+	RecentLevels int `yaml:"recent_levels" json:"recent_levels" validate:"required_if=Policy adaptive,gte=0" example:"3"`
+	// FastCellTime is max seconds per cell of the way for a fast completion
+	FastCellTime float64 `yaml:"fast_cell_time" json:"fast_cell_time" validate:"gte=0" example:"0.5"`
+	// SlowCellTime is min seconds per cell of the way for a slow completion
+	SlowCellTime float64 `yaml:"slow_cell_time" json:"slow_cell_time" validate:"gtefield=FastCellTime" example:"3"`
+}
+
+// LineGameDaily is a generated level which is the same for every user on a day
+type LineGameDaily struct {
+	Enabled   bool `yaml:"enabled" json:"enabled" example:"true"`
+	FieldSize int  `yaml:"field_size" json:"field_size" validate:"required_if=Enabled true,gte=0" example:"6"`
+	Orders    int  `yaml:"orders" json:"orders" validate:"gte=0" example:"3"`
+	Blockers  int  `yaml:"blockers" json:"blockers" validate:"gte=0" example:"2"`
+	// UTCOffset is an offset in hours of the time zone where a new day starts
+	UTCOffset         int                       `yaml:"utc_offset" json:"utc_offset" validate:"gte=-12,lte=14" example:"3"`
+	RewardsConditions []LineGameRewardCondition `yaml:"rewards_conditions" json:"rewards_conditions" validate:"required_if=Enabled true,dive"`
+	// StreakBonus is added to the reward for every day of the streak after the first one
+	StreakBonus int `yaml:"streak_bonus" json:"streak_bonus" validate:"gte=0" example:"10"`
+	// MaxStreakBonus limits the sum of streak bonuses
+	MaxStreakBonus int `yaml:"max_streak_bonus" json:"max_streak_bonus" validate:"gte=0" example:"100"`
+}
+
+// LineGameEndless is a mode with generated levels after the last level group
+type LineGameEndless struct {
+	Enabled bool `yaml:"enabled" json:"enabled" example:"true"`
+	// LevelsPerStep is a count of levels before moving to the next step of difficulty
+	LevelsPerStep int                   `yaml:"levels_per_step" json:"levels_per_step" validate:"required_if=Enabled true,gte=0" example:"10"`
+	Steps         []LineGameEndlessStep `yaml:"steps" json:"steps" validate:"required_if=Enabled true,dive"`
+}
+
+type LineGameEndlessStep struct {
+	FieldSize int `yaml:"field_size" json:"field_size" validate:"required,gt=1" example:"5"`
+	Orders    int `yaml:"orders" json:"orders" validate:"gte=0" example:"2"`
+	Blockers  int `yaml:"blockers" json:"blockers" validate:"gte=0" example:"1"`
 }
 
 type LineGameRewardCondition struct {
@@ -25,13 +156,142 @@ type LineGameRewardCondition struct {
 
 type LineGameReward struct {
 	SoftCurrency int `yaml:"soft_currency" json:"soft_currency" validate:"required,gt=0" example:"40"`
+	// Boosters are counts of boosters by their ids put into the user inventory
+	Boosters map[string]int `yaml:"boosters" json:"boosters,omitempty" validate:"dive,gt=0"`
 }
 
 type ItemsPrice struct {
-	LineGameHintPrice            int `yaml:"line_game_hint_price" json:"line_game_hint_price" validate:"required,gt=0" example:"40"`
-	LineGameStopTimeBoosterPrice int `yaml:"line_game_stop_time_booster_price" json:"line_game_stop_time_booster_price" validate:"required,gt=0" example:"40"`
+	// LineGameHintPrice is a price of the full answer hint
+	LineGameHintPrice           int `yaml:"line_game_hint_price" json:"line_game_hint_price" validate:"required,gt=0" example:"40"`
+	LineGameStepsHintPrice      int `yaml:"line_game_steps_hint_price" json:"line_game_steps_hint_price" validate:"required,gt=0" example:"20"`
+	LineGameCheckpointHintPrice int `yaml:"line_game_checkpoint_hint_price" json:"line_game_checkpoint_hint_price" validate:"required,gt=0" example:"30"`
+	// EnergyRefillPrice is a price of filling the energy up to the max
+	EnergyRefillPrice int `yaml:"energy_refill_price" json:"energy_refill_price" validate:"required,gt=0" example:"150"`
+}
+
+const (
+	// BoosterTimeStop and BoosterUndo are applied by the client, the server only takes the booster
+	BoosterTimeStop         = "time_stop"
+	BoosterUndo             = "undo"
+	BoosterSkipLevel        = "skip_level"
+	BoosterRevealCheckpoint = "reveal_checkpoint"
+)
+
+// Boosters is a catalog of boosters bought during a line game level
+type Boosters struct {
+	Items []Booster `yaml:"items" json:"items" validate:"unique=ID,dive"`
+	// Bundles are sold for soft currency and put boosters into the user inventory
+	Bundles []BoosterBundle `yaml:"bundles" json:"bundles" validate:"unique=ID,dive"`
+}
+
+type Booster struct {
+	ID     string `yaml:"id" json:"id" validate:"required,max=32" example:"skip_level"`
+	Effect string `yaml:"effect" json:"effect" validate:"oneof=time_stop undo skip_level reveal_checkpoint" example:"skip_level"`
+	// LimitPerLevel is a max count of uses during one level attempt, zero is unlimited
+	LimitPerLevel int `yaml:"limit_per_level" json:"limit_per_level" validate:"gte=0" example:"1"`
+}
+
+type BoosterBundle struct {
+	ID        string `yaml:"id" json:"id" validate:"required,max=32" example:"skip_level_3"`
+	BoosterID string `yaml:"booster_id" json:"booster_id" validate:"required" example:"skip_level"`
+	Count     int    `yaml:"count" json:"count" validate:"required,gt=0" example:"3"`
+	Price     int    `yaml:"price" json:"price" validate:"gte=0" example:"1000"`
+}
+
+// Booster returns the booster with the id.
+func (b Boosters) Booster(id string) (Booster, bool) {
+	for _, booster := range b.Items {
+		if booster.ID == id {
+			return booster, true
+		}
+	}
+	return Booster{}, false
+}
+
+// Bundle returns the bundle with the id.
+func (b Boosters) Bundle(id string) (BoosterBundle, bool) {
+	for _, bundle := range b.Bundles {
+		if bundle.ID == id {
+			return bundle, true
+		}
+	}
+	return BoosterBundle{}, false
+}
+
+// Energy is spent on starting line game levels and regenerates over time up to the max
+type Energy struct {
+	Enabled   bool `yaml:"enabled" json:"enabled" example:"true"`
+	Max       int  `yaml:"max" json:"max" validate:"required_if=Enabled true,gte=0" example:"5"`
+	LevelCost int  `yaml:"level_cost" json:"level_cost" validate:"gte=0" example:"1"`
+	// RegenSeconds is a time in seconds of regeneration of one energy unit
+	RegenSeconds int `yaml:"regen_seconds" json:"regen_seconds" validate:"required_if=Enabled true,gte=0" example:"600"`
+}
+
+func (e Energy) RegenInterval() time.Duration {
+	return time.Duration(e.RegenSeconds) * time.Second
 }
 
 type Quiz struct {
 	SoftCurrencyReward int `yaml:"soft_currency_reward" json:"soft_currency_reward" validate:"required,gt=0" example:"40"`
+	// DifficultyRewards replace the soft currency reward for quizzes of the difficulty
+	DifficultyRewards QuizDifficultyRewards `yaml:"difficulty_rewards" json:"difficulty_rewards"`
+	// RetryRewardPercents are percents of the reward for a correct answer after wrong ones, the first percent
+	// is for the second attempt and later attempts get the last one. Every attempt gets the full reward without them.
+	RetryRewardPercents []int     `yaml:"retry_reward_percents" json:"retry_reward_percents" validate:"lte=10,dive,gte=0,lte=100" example:"50,0"`
+	Round               QuizRound `yaml:"round" json:"round"`
+}
+
+// QuizRound is a series of quizzes answered one by one with a time limit per question.
+// Only the first correct answer to a quiz is rewarded like single quizzes, with a bonus for the speed.
+type QuizRound struct {
+	// Questions is a count of questions in a round when the user does not choose it
+	Questions    int `yaml:"questions" json:"questions" validate:"required,gt=0" example:"5"`
+	MaxQuestions int `yaml:"max_questions" json:"max_questions" validate:"gtefield=Questions" example:"10"`
+	// QuestionSeconds is a time limit in seconds to answer one question
+	QuestionSeconds int `yaml:"question_seconds" json:"question_seconds" validate:"required,gt=0" example:"20"`
+	// SpeedBonusPercent is an extra reward percent for an instant answer, it falls to zero at the time limit
+	SpeedBonusPercent int `yaml:"speed_bonus_percent" json:"speed_bonus_percent" validate:"gte=0" example:"50"`
+}
+
+func (r QuizRound) QuestionTimeLimit() time.Duration {
+	return time.Duration(r.QuestionSeconds) * time.Second
+}
+
+// QuizDifficultyRewards are soft currency rewards by quiz difficulty, zero is replaced by the common reward
+type QuizDifficultyRewards struct {
+	Easy   int `yaml:"easy" json:"easy" validate:"gte=0" example:"30"`
+	Medium int `yaml:"medium" json:"medium" validate:"gte=0" example:"50"`
+	Hard   int `yaml:"hard" json:"hard" validate:"gte=0" example:"80"`
+}
+
+// Reward returns the soft currency reward for a correct answer to a quiz of the difficulty.
+func (q Quiz) Reward(difficulty string) int {
+	var reward int
+	switch difficulty {
+	case QuizDifficultyEasy:
+		reward = q.DifficultyRewards.Easy
+	case QuizDifficultyMedium:
+		reward = q.DifficultyRewards.Medium
+	case QuizDifficultyHard:
+		reward = q.DifficultyRewards.Hard
+	}
+	if reward == 0 {
+		return q.SoftCurrencyReward
+	}
+	return reward
 }
+
+// AttemptReward returns the reward for a correct answer on the attempt starting from 1 by the retry policy.
+func (q Quiz) AttemptReward(reward, attempt int) int {
+	if attempt <= 1 || len(q.RetryRewardPercents) == 0 {
+		return reward
+	}
+	percent := q.RetryRewardPercents[min(attempt-2, len(q.RetryRewardPercents)-1)]
+	return reward * percent / 100
+}
+
+const (
+	QuizDifficultyEasy   = "easy"
+	QuizDifficultyMedium = "medium"
+	QuizDifficultyHard   = "hard"
+)
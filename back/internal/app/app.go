@@ -9,6 +9,7 @@ import (
 	"github.com/4units/mos-hack-game/back/internal/router"
 	file_storage "github.com/4units/mos-hack-game/back/internal/storage/file-storage"
 	"github.com/4units/mos-hack-game/back/internal/storage/postgres"
+	redis_cache "github.com/4units/mos-hack-game/back/internal/storage/redis-cache"
 	"github.com/4units/mos-hack-game/back/internal/usecase"
 	logs "github.com/4units/mos-hack-game/back/pkg/logging"
 	"github.com/gorilla/mux"
@@ -18,6 +19,7 @@ import (
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 )
 
 func Run(cfg *config.Config) error {
@@ -63,6 +65,8 @@ func Run(cfg *config.Config) error {
 			LineGameConifgProcessor: configUsecase,
 			QuizConfigProcessor:     configUsecase,
 			PriceConifgProcessor:    configUsecase,
+			EnergyConfigProcessor:   configUsecase,
+			BoostersConfigProcessor: configUsecase,
 			BalanceConfigProcessor:  configUsecase,
 			UserIDExtractor:         tokenUsecase,
 		},
@@ -76,9 +80,74 @@ func Run(cfg *config.Config) error {
 		},
 	)
 
-	lineGameLevelStorage := file_storage.NewLineGameLevelStorage(configUsecase, cfg.Game.LineGameLevelsDir)
+	var (
+		lineGameLevelStorage usecase.LineLevelStorage
+		lineGameLevelEditor  usecase.LineLevelEditor
+		watchLevels          func(ctx context.Context, interval time.Duration)
+	)
+	switch cfg.Game.LineGameLevelsStorage {
+	case config.LineGameLevelsStorageFile:
+		fileLevelStorage, err := file_storage.NewLineGameLevelStorage(configUsecase, cfg.Game.LineGameLevelsDir)
+		if err != nil {
+			return err
+		}
+		lineGameLevelStorage, watchLevels = fileLevelStorage, fileLevelStorage.Watch
+	case config.LineGameLevelsStoragePostgres:
+		postgresLevelStorage, err := postgres.NewLineGameLevelStorage(ctx, pool)
+		if err != nil {
+			return err
+		}
+		lineGameLevelStorage, watchLevels = postgresLevelStorage, postgresLevelStorage.Watch
+		lineGameLevelEditor = postgresLevelStorage
+	default:
+		return fmt.Errorf("unknown line game levels storage %q", cfg.Game.LineGameLevelsStorage)
+	}
+	log.Info("line game levels are loaded", slog.String("storage", cfg.Game.LineGameLevelsStorage))
+	if cfg.Game.LineGameLevelsReloadInterval > 0 {
+		go watchLevels(ctx, cfg.Game.LineGameLevelsReloadInterval)
+	}
+
+	lineGameAdminUsecase := usecase.NewLineGameAdminUsecase(
+		usecase.LineGameAdminUsecaseDeps{
+			LineLevelEditor: lineGameLevelEditor,
+			UserUsecase:     userUsecase,
+		},
+	)
+	lineGameAdminHandler := handler.NewLineGameAdminHandler(
+		handler.LineGameAdminHandlerDeps{
+			LineGameLevelEditor: lineGameAdminUsecase,
+			UserIDExtractor:     tokenUsecase,
+		},
+	)
+
+	// leaderboards are read from postgres when redis is not configured
+	var leaderboardCache usecase.LeaderboardCache
+	if cfg.Redis.URL != "" {
+		redisClient, err := redis_cache.NewClient(ctx, cfg.Redis)
+		if err != nil {
+			return err
+		}
+		defer redisClient.Close()
+		log.Info("connected to redis")
+		leaderboardCache = redis_cache.NewLeaderboardCache(redisClient, cfg.Redis.LeaderboardTTL)
+	}
+	leaderboardUsecase := usecase.NewLeaderboardUsecase(
+		usecase.LeaderboardUsecaseDeps{
+			LeaderboardStorage:     postgres.NewLeaderboardStorage(pool),
+			LeaderboardCache:       leaderboardCache,
+			LineGameConifgProvider: configUsecase,
+		},
+	)
+	leaderboardHandler := handler.NewLeaderboardHandler(
+		handler.LeaderboardHandlerDeps{
+			LeaderboardProvider: leaderboardUsecase,
+			UserIDExtractor:     tokenUsecase,
+		},
+	)
 
 	progressStorage := postgres.NewLineGameProgressStorage(pool)
+	hintStorage := postgres.NewLineGameHintStorage(pool)
+	levelResultStorage := postgres.NewLineGameLevelResultStorage(pool)
 	balanceStorage := postgres.NewBalanceStorage(pool)
 
 	var balanceUsecase = usecase.NewBalanceUsecase(
@@ -94,13 +163,52 @@ func Run(cfg *config.Config) error {
 			BalanceProvider: balanceUsecase,
 		},
 	)
+	energyUsecase := usecase.NewEnergyUsecase(
+		usecase.EnergyUsecaseDeps{
+			EnergyStorage:        postgres.NewEnergyStorage(pool),
+			BalanceUsecase:       balanceUsecase,
+			EnergyConfigProvider: configUsecase,
+			PriceConifgProvider:  configUsecase,
+		},
+	)
+	energyHandler := handler.NewEnergyHandler(
+		handler.EnergyHandlerDeps{
+			EnergyProvider:  energyUsecase,
+			UserIDExtractor: tokenUsecase,
+		},
+	)
+
+	boosterInventoryUsecase := usecase.NewBoosterInventoryUsecase(
+		usecase.BoosterInventoryUsecaseDeps{
+			BoosterInventoryStorage: postgres.NewBoosterInventoryStorage(pool),
+			BalanceUsecase:          balanceUsecase,
+			UserUsecase:             userUsecase,
+			BoostersConfigProvider:  configUsecase,
+		},
+	)
+	inventoryHandler := handler.NewInventoryHandler(
+		handler.InventoryHandlerDeps{
+			BoosterInventoryProvider: boosterInventoryUsecase,
+			UserIDExtractor:          tokenUsecase,
+		},
+	)
+
 	var lineGameUsecase = usecase.NewLineGameUsecase(
 		usecase.LineGameUsecaseDeps{
-			LineGameLevelStorage:    lineGameLevelStorage,
-			LineGameProgressStorage: progressStorage,
-			BalanceUsecase:          balanceUsecase,
-			LineGameConifgProvider:  configUsecase,
-			PriceConifgProvider:     configUsecase,
+			LineGameLevelStorage:       lineGameLevelStorage,
+			LineGameProgressStorage:    progressStorage,
+			LineGameHintStorage:        hintStorage,
+			LineGameLevelResultStorage: levelResultStorage,
+			LineGameAttemptStorage:     postgres.NewLineGameLevelAttemptStorage(pool),
+			LineGameBoosterStorage:     postgres.NewLineGameBoosterStorage(pool),
+			LineGameStarStorage:        postgres.NewLineGameStarStorage(pool),
+			BalanceUsecase:             balanceUsecase,
+			LeaderboardUsecase:         leaderboardUsecase,
+			EnergyUsecase:              energyUsecase,
+			BoosterInventoryUsecase:    boosterInventoryUsecase,
+			LineGameConifgProvider:     configUsecase,
+			PriceConifgProvider:        configUsecase,
+			BoostersConfigProvider:     configUsecase,
 		},
 	)
 	lineGameHandler := handler.NewLineGameHandler(
@@ -109,41 +217,92 @@ func Run(cfg *config.Config) error {
 			LineGameLevelProvider:     lineGameUsecase,
 			UserIDExtractor:           tokenUsecase,
 			LineGameBoosterProvider:   lineGameUsecase,
+			LineGameStarsProvider:     lineGameUsecase,
 			LineGameConifgProvider:    configUsecase,
 		},
 	)
 
+	dailyStorage := postgres.NewLineGameDailyStorage(pool)
+	dailyUsecase := usecase.NewLineGameDailyUsecase(
+		usecase.LineGameDailyUsecaseDeps{
+			LineGameDailyStorage:    dailyStorage,
+			BalanceUsecase:          balanceUsecase,
+			LeaderboardUsecase:      leaderboardUsecase,
+			BoosterInventoryUsecase: boosterInventoryUsecase,
+			LineGameConifgProvider:  configUsecase,
+		},
+	)
+	dailyHandler := handler.NewLineGameDailyHandler(
+		handler.LineGameDailyHandlerDeps{
+			LineGameDailyProvider: dailyUsecase,
+			UserIDExtractor:       tokenUsecase,
+		},
+	)
+
 	quizStorage := postgres.NewQuizStorage(pool)
 
 	quizUsecase := usecase.NewQuizUsecase(
 		usecase.QuizUsecaseDeps{
 			QuizStorage:        quizStorage,
+			QuizAnswerStorage:  postgres.NewQuizAnswerStorage(pool),
 			UserUsecase:        userUsecase,
 			BalanceUsecase:     balanceUsecase,
+			LeaderboardUsecase: leaderboardUsecase,
 			QuizConfigProvider: configUsecase,
 		},
 	)
+	if err = quizUsecase.ReloadQuizIndex(ctx); err != nil {
+		return err
+	}
+	if cfg.Game.QuizIndexReloadInterval > 0 {
+		go quizUsecase.WatchQuizIndex(ctx, cfg.Game.QuizIndexReloadInterval)
+	}
 
 	quizHandler := handler.NewQuizHandler(
 		handler.QuizHandlerDeps{
 			QuizCompleteProcessor: quizUsecase,
 			QuizProvider:          quizUsecase,
 			NewQuizConsumer:       quizUsecase,
+			QuizModerator:         quizUsecase,
+			QuizCatalogProvider:   quizUsecase,
+			QuizImporter:          quizUsecase,
 			UserIDExtractor:       tokenUsecase,
 		},
 	)
 
+	quizRoundUsecase := usecase.NewQuizRoundUsecase(
+		usecase.QuizRoundUsecaseDeps{
+			QuizRoundStorage:   postgres.NewQuizRoundStorage(pool),
+			QuizUsecase:        quizUsecase,
+			BalanceUsecase:     balanceUsecase,
+			LeaderboardUsecase: leaderboardUsecase,
+			QuizConfigProvider: configUsecase,
+		},
+	)
+	quizRoundHandler := handler.NewQuizRoundHandler(
+		handler.QuizRoundHandlerDeps{
+			QuizRoundProcessor: quizRoundUsecase,
+			UserIDExtractor:    tokenUsecase,
+		},
+	)
+
 	rt := mux.NewRouter()
 
 	port := fmt.Sprintf(":%v", cfg.Host.HttpPort)
 
 	handler, err := router.Setup(
 		rt, router.Deps{
-			UserHandler:     userHandler,
-			LineGameHandler: lineGameHandler,
-			BalanceHandler:  balanceHandler,
-			QuizHandler:     quizHandler,
-			ConfigHandler:   configHandler,
+			UserHandler:        userHandler,
+			LineGameHandler:    lineGameHandler,
+			DailyHandler:       dailyHandler,
+			BalanceHandler:     balanceHandler,
+			EnergyHandler:      energyHandler,
+			InventoryHandler:   inventoryHandler,
+			QuizHandler:        quizHandler,
+			QuizRoundHandler:   quizRoundHandler,
+			ConfigHandler:      configHandler,
+			AdminHandler:       lineGameAdminHandler,
+			LeaderboardHandler: leaderboardHandler,
 		}, cfg.Router,
 	)
 	if err != nil {
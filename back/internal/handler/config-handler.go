@@ -35,11 +35,23 @@ type PriceConifgProcessor interface {
 	UpdatePriceConifg(ctx context.Context, userID uuid.UUID, cfg config.ItemsPrice) error
 }
 
+type EnergyConfigProcessor interface {
+	EnergyConfig() *config.Energy
+	UpdateEnergyConfig(ctx context.Context, userID uuid.UUID, cfg config.Energy) error
+}
+
+type BoostersConfigProcessor interface {
+	BoostersConfig() *config.Boosters
+	UpdateBoostersConfig(ctx context.Context, userID uuid.UUID, cfg config.Boosters) error
+}
+
 type ConfigHandlerDeps struct {
 	LineGameConifgProcessor LineGameConifgProcessor
 	BalanceConfigProcessor  BalanceConfigProcessor
 	QuizConfigProcessor     QuizConfigProcessor
 	PriceConifgProcessor    PriceConifgProcessor
+	EnergyConfigProcessor   EnergyConfigProcessor
+	BoostersConfigProcessor BoostersConfigProcessor
 	UserIDExtractor         UserIDExtractor
 }
 
@@ -122,6 +134,8 @@ func (h *ConfigHandler) GetLineGameConfig(w http.ResponseWriter, _ *http.Request
 
 // UpdateLineGameConfig godoc
 // @Summary      Update line game config
+// @Description  Rewards of completed levels are taken from group_rewards by the group code, then by the first
+// @Description  matching range of field size, orders and blockers, the global rewards_conditions are the fallback.
 // @Tags         config
 // @Accept       json
 // @Security     BearerAuth
@@ -258,3 +272,105 @@ func (h *ConfigHandler) UpdatePriceGameConfig(w http.ResponseWriter, r *http.Req
 	}
 	w.WriteHeader(http.StatusOK)
 }
+
+// GetEnergyGameConfig godoc
+// @Summary      Get energy config
+// @Tags         config
+// @Produce      json
+// @Success      200  {object}  config.Energy
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /config/energy [get]
+func (h *ConfigHandler) GetEnergyGameConfig(w http.ResponseWriter, _ *http.Request) {
+	if err := json.NewEncoder(w).Encode(h.EnergyConfigProcessor.EnergyConfig()); err != nil {
+		http_errors.NewInternal("failed to encode config")
+		logs.Error("failed to encode config", err)
+	}
+}
+
+// UpdateEnergyGameConfig godoc
+// @Summary      Update energy config
+// @Tags         config
+// @Accept       json
+// @Security     BearerAuth
+// @Param        body  body  config.Energy  true  "Update energy config data"
+// @Success      201
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /config/energy [put]
+func (h *ConfigHandler) UpdateEnergyGameConfig(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	var req config.Energy
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http_errors.SendBadRequest(w, "request body is invalid")
+		logs.Error("failed to decode the request", err)
+		return
+	}
+	if validationErr(w, h.validate, req) {
+		return
+	}
+	if err = h.EnergyConfigProcessor.UpdateEnergyConfig(
+		r.Context(), userID, req,
+	); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to update energy config", err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// GetBoostersGameConfig godoc
+// @Summary      Get boosters config
+// @Tags         config
+// @Produce      json
+// @Success      200  {object}  config.Boosters
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /config/boosters [get]
+func (h *ConfigHandler) GetBoostersGameConfig(w http.ResponseWriter, _ *http.Request) {
+	if err := json.NewEncoder(w).Encode(h.BoostersConfigProcessor.BoostersConfig()); err != nil {
+		http_errors.NewInternal("failed to encode config")
+		logs.Error("failed to encode config", err)
+	}
+}
+
+// UpdateBoostersGameConfig godoc
+// @Summary      Update boosters config
+// @Tags         config
+// @Accept       json
+// @Security     BearerAuth
+// @Param        body  body  config.Boosters  true  "Update boosters config data"
+// @Success      201
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /config/boosters [put]
+func (h *ConfigHandler) UpdateBoostersGameConfig(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	var req config.Boosters
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http_errors.SendBadRequest(w, "request body is invalid")
+		logs.Error("failed to decode the request", err)
+		return
+	}
+	if validationErr(w, h.validate, req) {
+		return
+	}
+	if err = h.BoostersConfigProcessor.UpdateBoostersConfig(
+		r.Context(), userID, req,
+	); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to update boosters config", err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
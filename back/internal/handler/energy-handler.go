@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	http_errors "github.com/4units/mos-hack-game/back/pkg/http-errors"
+	logs "github.com/4units/mos-hack-game/back/pkg/logging"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+type EnergyProvider interface {
+	GetEnergy(ctx context.Context, userID uuid.UUID) (model.EnergyStatus, error)
+	BuyRefill(ctx context.Context, userID uuid.UUID) (model.EnergyStatus, error)
+}
+
+type EnergyHandlerDeps struct {
+	EnergyProvider  EnergyProvider
+	UserIDExtractor UserIDExtractor
+}
+
+type EnergyHandler struct {
+	EnergyHandlerDeps
+}
+
+func NewEnergyHandler(deps EnergyHandlerDeps) *EnergyHandler {
+	return &EnergyHandler{
+		EnergyHandlerDeps: deps,
+	}
+}
+
+type GetEnergyResponse struct {
+	// Enabled is false when levels are played without energy
+	Enabled   bool `json:"enabled" example:"true"`
+	Energy    int  `json:"energy" example:"3"`
+	MaxEnergy int  `json:"max_energy" example:"5"`
+	LevelCost int  `json:"level_cost" example:"1"`
+	// NextRefillAt is null when the energy is full
+	NextRefillAt *time.Time `json:"next_refill_at" example:"2025-10-19T12:10:00Z"`
+	// SecondsToNextRefill is zero when the energy is full
+	SecondsToNextRefill int `json:"seconds_to_next_refill" example:"540"`
+}
+
+// GetEnergy godoc
+// @Summary      Get energy
+// @Description  Starting a line game level costs energy, it regenerates over time up to the max.
+// @Tags         energy
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  GetEnergyResponse
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/energy [get]
+func (h *EnergyHandler) GetEnergy(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	energy, err := h.EnergyProvider.GetEnergy(r.Context(), userID)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to get energy", err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(newGetEnergyResponse(energy)); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+// BuyEnergyRefill godoc
+// @Summary      Buy energy refill
+// @Description  Fills the energy up to the max for soft currency.
+// @Tags         energy
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  GetEnergyResponse
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      409  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/energy/refill [post]
+func (h *EnergyHandler) BuyEnergyRefill(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	energy, err := h.EnergyProvider.BuyRefill(r.Context(), userID)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to buy energy refill", err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(newGetEnergyResponse(energy)); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+func newGetEnergyResponse(energy model.EnergyStatus) GetEnergyResponse {
+	resp := GetEnergyResponse{
+		Enabled:   energy.Enabled,
+		Energy:    energy.Value,
+		MaxEnergy: energy.Max,
+		LevelCost: energy.LevelCost,
+	}
+	if !energy.NextRefillAt.IsZero() {
+		resp.NextRefillAt = &energy.NextRefillAt
+		resp.SecondsToNextRefill = max(int(time.Until(energy.NextRefillAt).Seconds()), 0)
+	}
+	return resp
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/4units/mos-hack-game/back/internal/model/constantce"
+	http_errors "github.com/4units/mos-hack-game/back/pkg/http-errors"
+	logs "github.com/4units/mos-hack-game/back/pkg/logging"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+type BoosterInventoryProvider interface {
+	GetInventory(ctx context.Context, userID uuid.UUID) ([]model.BoosterStack, error)
+	BuyBundle(ctx context.Context, userID uuid.UUID, bundleID string) ([]model.BoosterStack, error)
+	GiftBoosters(
+		ctx context.Context,
+		adminID uuid.UUID,
+		userID uuid.UUID,
+		boosterID string,
+		count int,
+	) ([]model.BoosterStack, error)
+}
+
+type InventoryHandlerDeps struct {
+	BoosterInventoryProvider BoosterInventoryProvider
+	UserIDExtractor          UserIDExtractor
+}
+
+type InventoryHandler struct {
+	InventoryHandlerDeps
+	validate *validator.Validate
+}
+
+func NewInventoryHandler(deps InventoryHandlerDeps) *InventoryHandler {
+	return &InventoryHandler{
+		InventoryHandlerDeps: deps,
+		validate:             validator.New(),
+	}
+}
+
+type InventoryBooster struct {
+	BoosterID string `json:"booster_id" example:"skip_level"`
+	Effect    string `json:"effect" example:"skip_level"`
+	Count     int    `json:"count" example:"2"`
+}
+
+type GetInventoryResponse struct {
+	Boosters []InventoryBooster `json:"boosters"`
+}
+
+type GiftBoostersRequest struct {
+	BoosterID string `json:"booster_id" validate:"required" example:"skip_level"`
+	Count     int    `json:"count" validate:"required,gt=0,lte=1000" example:"3"`
+}
+
+// GetInventory godoc
+// @Summary      Get booster inventory
+// @Description  Returns counts of every booster of the catalog, boosters are used via /game/line/booster/{booster-id}.
+// @Tags         inventory
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  GetInventoryResponse
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/inventory [get]
+func (h *InventoryHandler) GetInventory(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	inventory, err := h.BoosterInventoryProvider.GetInventory(r.Context(), userID)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to get inventory", err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(newGetInventoryResponse(inventory)); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+// BuyBoosterBundle godoc
+// @Summary      Buy booster bundle
+// @Description  Spends soft currency and puts boosters of the bundle into the inventory in one transaction.
+// @Description  Bundles are configured in /config/boosters.
+// @Tags         inventory
+// @Produce      json
+// @Security     BearerAuth
+// @Param        bundle-id  path  string  true  "Bundle id"
+// @Success      200  {object}  GetInventoryResponse
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      404  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/inventory/bundles/{bundle-id} [post]
+func (h *InventoryHandler) BuyBoosterBundle(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	inventory, err := h.BoosterInventoryProvider.BuyBundle(
+		r.Context(), userID, mux.Vars(r)[constantce.RequestVariableBundleID],
+	)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to buy booster bundle", err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(newGetInventoryResponse(inventory)); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+// GiftBoosters godoc
+// @Summary      Gift boosters to user
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user-id  path  string  true  "User id"
+// @Param        body  body  GiftBoostersRequest  true  "Boosters"
+// @Success      200  {object}  GetInventoryResponse
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      404  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /admin/users/{user-id}/boosters [post]
+func (h *InventoryHandler) GiftBoosters(w http.ResponseWriter, r *http.Request) {
+	adminID, err := h.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	userID, err := uuid.Parse(mux.Vars(r)[constantce.RequestVariableUserID])
+	if err != nil {
+		http_errors.SendBadRequest(w, "user id is invalid")
+		logs.Error("failed to parse user id", err)
+		return
+	}
+	var req GiftBoostersRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http_errors.SendBadRequest(w, "request body is invalid")
+		logs.Error("failed to decode the request", err)
+		return
+	}
+	if validationErr(w, h.validate, req) {
+		return
+	}
+	inventory, err := h.BoosterInventoryProvider.GiftBoosters(r.Context(), adminID, userID, req.BoosterID, req.Count)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to gift boosters", err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(newGetInventoryResponse(inventory)); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+func newGetInventoryResponse(inventory []model.BoosterStack) GetInventoryResponse {
+	resp := GetInventoryResponse{
+		Boosters: make([]InventoryBooster, 0, len(inventory)),
+	}
+	for _, stack := range inventory {
+		resp.Boosters = append(
+			resp.Boosters, InventoryBooster{
+				BoosterID: stack.BoosterID,
+				Effect:    stack.Effect,
+				Count:     stack.Count,
+			},
+		)
+	}
+	return resp
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	http_errors "github.com/4units/mos-hack-game/back/pkg/http-errors"
+	logs "github.com/4units/mos-hack-game/back/pkg/logging"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const defaultLeaderboardLimit = 10
+
+type LeaderboardProvider interface {
+	GetLeaderboard(ctx context.Context, userID uuid.UUID, query model.LeaderboardQuery) (model.Leaderboard, error)
+}
+
+type LeaderboardHandlerDeps struct {
+	LeaderboardProvider LeaderboardProvider
+	UserIDExtractor     UserIDExtractor
+}
+
+type LeaderboardHandler struct {
+	LeaderboardHandlerDeps
+	validate *validator.Validate
+}
+
+func NewLeaderboardHandler(deps LeaderboardHandlerDeps) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		LeaderboardHandlerDeps: deps,
+		validate:               validator.New(),
+	}
+}
+
+type GetLeaderboardRequest struct {
+	Board     string `validate:"required,oneof=level_time daily_time stars quiz"`
+	Period    string `validate:"required,oneof=global weekly"`
+	GroupCode string `validate:"required_if=Board level_time"`
+	LevelNum  int    `validate:"min=0"`
+	Limit     int    `validate:"min=1,max=100"`
+}
+
+type LeaderboardEntry struct {
+	Rank        int    `json:"rank" example:"1"`
+	DisplayName string `json:"display_name" example:"ann***"`
+	// Score is milliseconds for time boards, stars or correct answers count for others
+	Score int `json:"score" example:"42"`
+	// Time is the score of time boards in seconds, it is absent for other boards
+	Time *float64 `json:"time,omitempty" example:"4.25"`
+}
+
+type GetLeaderboardResponse struct {
+	Entries []LeaderboardEntry `json:"entries"`
+	// User is null when the user has no score on the board
+	User *LeaderboardEntry `json:"user"`
+}
+
+// GetLeaderboard godoc
+// @Summary      Get leaderboard
+// @Description  Returns best entries of the board and the entry of the user. Time boards are sorted ascending.
+// @Description  The daily_time board is always the board of the current daily level, the period is ignored for it.
+// @Tags         leaderboard
+// @Produce      json
+// @Security     BearerAuth
+// @Param        board       query  string  true   "Board"  Enums(level_time, daily_time, stars, quiz)
+// @Param        period      query  string  false  "Period, global by default"  Enums(global, weekly)
+// @Param        group_code  query  string  false  "Level group code, required for level_time"
+// @Param        level_num   query  int     false  "Level number for level_time"
+// @Param        limit       query  int     false  "Entries count, 10 by default, max 100"
+// @Success      200  {object}  GetLeaderboardResponse
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /leaderboard [get]
+func (h *LeaderboardHandler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	values := r.URL.Query()
+	req := GetLeaderboardRequest{
+		Board:     values.Get("board"),
+		Period:    values.Get("period"),
+		GroupCode: values.Get("group_code"),
+		Limit:     defaultLeaderboardLimit,
+	}
+	if req.Period == "" {
+		req.Period = string(model.LeaderboardGlobal)
+	}
+	if req.LevelNum, err = intQueryParam(values.Get("level_num"), 0); err != nil {
+		http_errors.SendBadRequest(w, "level_num is invalid")
+		return
+	}
+	if req.Limit, err = intQueryParam(values.Get("limit"), defaultLeaderboardLimit); err != nil {
+		http_errors.SendBadRequest(w, "limit is invalid")
+		return
+	}
+	if validationErr(w, h.validate, req) {
+		return
+	}
+	board, err := h.LeaderboardProvider.GetLeaderboard(
+		r.Context(), userID, model.LeaderboardQuery{
+			Metric:    model.LeaderboardMetric(req.Board),
+			Period:    model.LeaderboardPeriod(req.Period),
+			GroupCode: model.LineGameLevelGroupCode(req.GroupCode),
+			LevelNum:  req.LevelNum,
+			Limit:     req.Limit,
+		},
+	)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to get leaderboard", err)
+		return
+	}
+	resp := GetLeaderboardResponse{
+		Entries: make([]LeaderboardEntry, 0, len(board.Entries)),
+	}
+	for _, entry := range board.Entries {
+		resp.Entries = append(resp.Entries, newLeaderboardEntry(board.Key, entry))
+	}
+	if board.User != nil {
+		userEntry := newLeaderboardEntry(board.Key, *board.User)
+		resp.User = &userEntry
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+func newLeaderboardEntry(key model.LeaderboardKey, entry model.LeaderboardEntry) LeaderboardEntry {
+	resp := LeaderboardEntry{
+		Rank:        entry.Rank,
+		DisplayName: entry.DisplayName,
+		Score:       entry.Score,
+	}
+	if key.LowerIsBetter {
+		seconds := (time.Duration(entry.Score) * time.Millisecond).Seconds()
+		resp.Time = &seconds
+	}
+	return resp
+}
+
+func intQueryParam(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(value)
+}
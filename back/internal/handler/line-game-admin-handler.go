@@ -0,0 +1,319 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/4units/mos-hack-game/back/internal/model/constantce"
+	http_errors "github.com/4units/mos-hack-game/back/pkg/http-errors"
+	logs "github.com/4units/mos-hack-game/back/pkg/logging"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+	"time"
+)
+
+type LineGameLevelEditor interface {
+	UploadGroup(
+		ctx context.Context,
+		userID uuid.UUID,
+		group model.LineGameLevelGroup,
+	) ([]model.LineGameLevelValidation, bool, error)
+	GetGroupPreview(
+		ctx context.Context,
+		userID uuid.UUID,
+		groupCode model.LineGameLevelGroupCode,
+	) (model.LineGameLevelGroup, model.LineGameLevelGroupStatus, error)
+	PublishGroup(ctx context.Context, userID uuid.UUID, groupCode model.LineGameLevelGroupCode) error
+	GetGroupsInfo(ctx context.Context, userID uuid.UUID) ([]model.LineGameLevelGroupInfo, error)
+}
+
+type LineGameAdminHandlerDeps struct {
+	LineGameLevelEditor LineGameLevelEditor
+	UserIDExtractor     UserIDExtractor
+}
+
+type LineGameAdminHandler struct {
+	LineGameAdminHandlerDeps
+	validate *validator.Validate
+}
+
+func NewLineGameAdminHandler(deps LineGameAdminHandlerDeps) *LineGameAdminHandler {
+	return &LineGameAdminHandler{
+		LineGameAdminHandlerDeps: deps,
+		validate:                 validator.New(),
+	}
+}
+
+// LineGameGroup has the same format as files in the levels dir
+type LineGameGroup struct {
+	FieldSize int             `json:"field_size" validate:"required,gt=1" example:"3"`
+	Orders    int             `json:"orders" validate:"gte=0" example:"0"`
+	Blockers  int             `json:"blockers" validate:"gte=0" example:"0"`
+	Levels    []LineGameLevel `json:"levels" validate:"required,gt=0"`
+}
+
+type LineGameLevel struct {
+	FieldSize int    `json:"field_size" example:"3"`
+	StartCell Cell   `json:"start_cell"`
+	EndCell   Cell   `json:"end_cell"`
+	Order     []Cell `json:"order"`
+	Blockers  []Cell `json:"blockers"`
+	// Answer is answers double slice with number-side quality:
+	// 0 - up, 1 - right, 2 - down, 3 - left, 4 - finish, 5 - block
+	Answer [][]int `json:"answer"`
+}
+
+type LevelValidation struct {
+	LevelNum int    `json:"level_num"`
+	Valid    bool   `json:"valid"`
+	Error    string `json:"error,omitempty"`
+}
+
+type UploadLineGameGroupResponse struct {
+	GroupCode string            `json:"group_code" example:"3_0_0"`
+	Saved     bool              `json:"saved"`
+	Levels    []LevelValidation `json:"levels"`
+}
+
+type GetLineGameGroupResponse struct {
+	GroupCode string        `json:"group_code" example:"3_0_0"`
+	Status    string        `json:"status" example:"draft"`
+	Group     LineGameGroup `json:"group"`
+}
+
+type LineGameGroupInfo struct {
+	GroupCode   string    `json:"group_code" example:"3_0_0"`
+	LevelsCount int       `json:"levels_count" example:"10"`
+	Status      string    `json:"status" example:"published"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type GetLineGameGroupsResponse struct {
+	Groups []LineGameGroupInfo `json:"groups"`
+}
+
+// GetGroups godoc
+// @Summary      Get uploaded and published line game level groups
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  GetLineGameGroupsResponse
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      409  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /admin/line/groups [get]
+func (h *LineGameAdminHandler) GetGroups(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	infoList, err := h.LineGameLevelEditor.GetGroupsInfo(r.Context(), userID)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to get groups info", err)
+		return
+	}
+	resp := GetLineGameGroupsResponse{
+		Groups: make([]LineGameGroupInfo, 0, len(infoList)),
+	}
+	for _, info := range infoList {
+		resp.Groups = append(
+			resp.Groups, LineGameGroupInfo{
+				GroupCode:   string(info.Code),
+				LevelsCount: info.LevelsCount,
+				Status:      string(info.Status),
+				UpdatedAt:   info.UpdatedAt,
+			},
+		)
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+// UploadGroup godoc
+// @Summary      Upload line game level group as a draft
+// @Description  Validates every level against its answer. The draft is saved only if all levels are valid.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body  LineGameGroup  true  "Level group in the levels file format"
+// @Success      200  {object}  UploadLineGameGroupResponse
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      409  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /admin/line/groups [post]
+func (h *LineGameAdminHandler) UploadGroup(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	var req LineGameGroup
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http_errors.SendBadRequest(w, "request body is invalid")
+		logs.Error("failed to decode the request", err)
+		return
+	}
+	if validationErr(w, h.validate, req) {
+		return
+	}
+	group := req.toModel()
+	report, saved, err := h.LineGameLevelEditor.UploadGroup(r.Context(), userID, group)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to upload group", err)
+		return
+	}
+	resp := UploadLineGameGroupResponse{
+		GroupCode: string(group.GetCode()),
+		Saved:     saved,
+		Levels:    make([]LevelValidation, 0, len(report)),
+	}
+	for _, validation := range report {
+		levelValidation := LevelValidation{
+			LevelNum: validation.LevelNum,
+			Valid:    validation.Err == nil,
+		}
+		if validation.Err != nil {
+			levelValidation.Error = validation.Err.Error()
+		}
+		resp.Levels = append(resp.Levels, levelValidation)
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+// GetGroup godoc
+// @Summary      Preview line game level group
+// @Description  Returns the draft of the group or the published group if there is no draft.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        group-code  path  string  true  "Group code"  example(3_0_0)
+// @Success      200  {object}  GetLineGameGroupResponse
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      404  {object}  http_errors.ResponseError
+// @Failure      409  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /admin/line/groups/{group-code} [get]
+func (h *LineGameAdminHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	groupCode := model.LineGameLevelGroupCode(mux.Vars(r)[constantce.RequestVariableLineGameGroupCode])
+	group, status, err := h.LineGameLevelEditor.GetGroupPreview(r.Context(), userID, groupCode)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to get group preview", err)
+		return
+	}
+	resp := GetLineGameGroupResponse{
+		GroupCode: string(groupCode),
+		Status:    string(status),
+		Group:     newLineGameGroup(group),
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+// PublishGroup godoc
+// @Summary      Publish draft of line game level group
+// @Description  Replaces published levels of the group with the draft.
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        group-code  path  string  true  "Group code"  example(3_0_0)
+// @Success      200
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      404  {object}  http_errors.ResponseError
+// @Failure      409  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /admin/line/groups/{group-code}/publish [post]
+func (h *LineGameAdminHandler) PublishGroup(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	groupCode := model.LineGameLevelGroupCode(mux.Vars(r)[constantce.RequestVariableLineGameGroupCode])
+	if err = h.LineGameLevelEditor.PublishGroup(r.Context(), userID, groupCode); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to publish group", err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (g LineGameGroup) toModel() model.LineGameLevelGroup {
+	group := model.LineGameLevelGroup{
+		FieldSize: g.FieldSize,
+		Order:     g.Orders,
+		Blockers:  g.Blockers,
+		Levels:    make([]model.LineGameLevel, 0, len(g.Levels)),
+	}
+	for _, rawLevel := range g.Levels {
+		level := model.LineGameLevel{
+			FieldSize: rawLevel.FieldSize,
+			Start:     model.LineGameLevelCell{X: rawLevel.StartCell.X, Y: rawLevel.StartCell.Y},
+			End:       model.LineGameLevelCell{X: rawLevel.EndCell.X, Y: rawLevel.EndCell.Y},
+			Order:     make([]model.LineGameLevelCell, 0, len(rawLevel.Order)),
+			Blockers:  make([]model.LineGameLevelCell, 0, len(rawLevel.Blockers)),
+			Answer:    rawLevel.Answer,
+		}
+		for _, cell := range rawLevel.Order {
+			level.Order = append(level.Order, model.LineGameLevelCell{X: cell.X, Y: cell.Y})
+		}
+		for _, cell := range rawLevel.Blockers {
+			level.Blockers = append(level.Blockers, model.LineGameLevelCell{X: cell.X, Y: cell.Y})
+		}
+		group.Levels = append(group.Levels, level)
+	}
+	return group
+}
+
+func newLineGameGroup(group model.LineGameLevelGroup) LineGameGroup {
+	resp := LineGameGroup{
+		FieldSize: group.FieldSize,
+		Orders:    group.Order,
+		Blockers:  group.Blockers,
+		Levels:    make([]LineGameLevel, 0, len(group.Levels)),
+	}
+	for _, level := range group.Levels {
+		respLevel := LineGameLevel{
+			FieldSize: level.FieldSize,
+			StartCell: Cell{X: level.Start.X, Y: level.Start.Y},
+			EndCell:   Cell{X: level.End.X, Y: level.End.Y},
+			Order:     make([]Cell, 0, len(level.Order)),
+			Blockers:  make([]Cell, 0, len(level.Blockers)),
+			Answer:    level.Answer,
+		}
+		for _, cell := range level.Order {
+			respLevel.Order = append(respLevel.Order, Cell{X: cell.X, Y: cell.Y})
+		}
+		for _, cell := range level.Blockers {
+			respLevel.Blockers = append(respLevel.Blockers, Cell{X: cell.X, Y: cell.Y})
+		}
+		resp.Levels = append(resp.Levels, respLevel)
+	}
+	return resp
+}
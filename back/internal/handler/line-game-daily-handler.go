@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	http_errors "github.com/4units/mos-hack-game/back/pkg/http-errors"
+	logs "github.com/4units/mos-hack-game/back/pkg/logging"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+type LineGameDailyProvider interface {
+	GetDaily(ctx context.Context, userID uuid.UUID) (model.LineGameDaily, error)
+	TryCompleteDaily(
+		ctx context.Context,
+		userID uuid.UUID,
+		answer [][]int,
+		timeSinceStart time.Duration,
+	) (model.LineGameDailyCompletion, error)
+}
+
+type LineGameDailyHandlerDeps struct {
+	LineGameDailyProvider LineGameDailyProvider
+	UserIDExtractor       UserIDExtractor
+}
+
+type LineGameDailyHandler struct {
+	LineGameDailyHandlerDeps
+	validate *validator.Validate
+}
+
+func NewLineGameDailyHandler(deps LineGameDailyHandlerDeps) *LineGameDailyHandler {
+	return &LineGameDailyHandler{
+		LineGameDailyHandlerDeps: deps,
+		validate:                 validator.New(),
+	}
+}
+
+type GetDailyLevelResponse struct {
+	Day       string `json:"day" example:"2025-10-19"`
+	Completed bool   `json:"completed"`
+	// Streak is a count of consecutive days with completed daily level
+	Streak    int    `json:"streak" example:"3"`
+	FieldSize int    `json:"field_size" example:"6"`
+	StartCell Cell   `json:"start_cell"`
+	EndCell   Cell   `json:"end_cell"`
+	Order     []Cell `json:"order"`
+	Blockers  []Cell `json:"blockers"`
+}
+
+type CompleteDailyLevelRequest struct {
+	Answer [][]int `json:"answer"`
+	// TimeSinceStart is the completion time in seconds with a fractional part for milliseconds
+	TimeSinceStart float64 `json:"time_since_start" validate:"required,gt=0" example:"1.25"`
+}
+
+type CompleteDailyLevelResponse struct {
+	SoftCurrency int `json:"soft_currency" example:"220"`
+	Streak       int `json:"streak" example:"2"`
+	// Boosters are counts of rewarded boosters by their ids put into the inventory
+	Boosters map[string]int `json:"boosters,omitempty"`
+}
+
+// GetDailyLevel godoc
+// @Summary      Get daily level
+// @Description  The level is the same for every user on a day.
+// @Tags         line-game
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  GetDailyLevelResponse
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      404  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/line/daily [get]
+func (h *LineGameDailyHandler) GetDailyLevel(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	daily, err := h.LineGameDailyProvider.GetDaily(r.Context(), userID)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to get daily level", err)
+		return
+	}
+	level := daily.Level
+	resp := GetDailyLevelResponse{
+		Day:       daily.Day.Format(time.DateOnly),
+		Completed: daily.Completed,
+		Streak:    daily.Streak,
+		FieldSize: level.FieldSize,
+		StartCell: Cell{X: level.Start.X, Y: level.Start.Y},
+		EndCell:   Cell{X: level.End.X, Y: level.End.Y},
+		Order:     make([]Cell, 0, len(level.Order)),
+		Blockers:  make([]Cell, 0, len(level.Blockers)),
+	}
+	for _, cell := range level.Order {
+		resp.Order = append(resp.Order, Cell{X: cell.X, Y: cell.Y})
+	}
+	for _, cell := range level.Blockers {
+		resp.Blockers = append(resp.Blockers, Cell{X: cell.X, Y: cell.Y})
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+// CompleteDailyLevel godoc
+// @Summary      Complete daily level
+// @Description  The answer is always checked. The level can be completed once a day.
+// @Tags         line-game
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body  CompleteDailyLevelRequest  true  "Complete level data"
+// @Success      200  {object}  CompleteDailyLevelResponse
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      404  {object}  http_errors.ResponseError
+// @Failure      409  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/line/daily [post]
+func (h *LineGameDailyHandler) CompleteDailyLevel(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	var req CompleteDailyLevelRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http_errors.SendBadRequest(w, "request body is invalid")
+		logs.Error("failed to decode the request", err)
+		return
+	}
+	if validationErr(w, h.validate, req) {
+		return
+	}
+	completion, err := h.LineGameDailyProvider.TryCompleteDaily(
+		r.Context(), userID, req.Answer,
+		time.Duration(req.TimeSinceStart*float64(time.Second)),
+	)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to complete daily level", err)
+		return
+	}
+	resp := CompleteDailyLevelResponse{
+		SoftCurrency: completion.Reward,
+		Streak:       completion.Streak,
+		Boosters:     completion.RewardBoosters,
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
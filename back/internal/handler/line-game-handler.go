@@ -4,12 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/4units/mos-hack-game/back/internal/model/constantce"
 	http_errors "github.com/4units/mos-hack-game/back/pkg/http-errors"
 	logs "github.com/4units/mos-hack-game/back/pkg/logging"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 	"net/http"
-	"time"
 )
 
 type LineGameLevelProvider interface {
@@ -21,13 +22,31 @@ type LineGameCompleteProcessor interface {
 		ctx context.Context,
 		userID uuid.UUID,
 		answer [][]int,
-		timeSinceStart time.Duration,
 	) (model.LineGameReward, error)
 }
 
+type LineGameStarsProvider interface {
+	GetLevelStars(
+		ctx context.Context,
+		userID uuid.UUID,
+		groupCode model.LineGameLevelGroupCode,
+	) ([]model.LineGameLevelStars, error)
+}
+
 type LineGameBoosterProvider interface {
 	GetLevelHint(ctx context.Context, userID uuid.UUID) ([][]int, error)
-	GetTimeStopBooster(ctx context.Context, userID uuid.UUID) error
+	BuyLevelHint(
+		ctx context.Context,
+		userID uuid.UUID,
+		hintType model.LineGameHintType,
+		path []model.LineGameLevelCell,
+	) (model.LineGameHint, error)
+	UseBooster(
+		ctx context.Context,
+		userID uuid.UUID,
+		boosterID string,
+		path []model.LineGameLevelCell,
+	) (model.LineGameBoosterUse, error)
 }
 
 type LineGameHandlerDeps struct {
@@ -35,6 +54,7 @@ type LineGameHandlerDeps struct {
 	LineGameCompleteProcessor LineGameCompleteProcessor
 	UserIDExtractor           UserIDExtractor
 	LineGameBoosterProvider   LineGameBoosterProvider
+	LineGameStarsProvider     LineGameStarsProvider
 	LineGameConifgProvider
 }
 
@@ -57,6 +77,20 @@ type GetUserLevelResponse struct {
 	EndCell   Cell   `json:"end_cell"`
 	Order     []Cell `json:"order"`
 	Blockers  []Cell `json:"blockers"`
+	// Endless is true for generated levels after the last level group
+	Endless bool `json:"endless"`
+	// Hints are bought for the current attempt of the level
+	Hints []LevelHint `json:"hints"`
+}
+
+type LevelHint struct {
+	Type string `json:"type" example:"steps"`
+	// KeptCells is a count of cells of the user path continued by the hint
+	KeptCells int `json:"kept_cells" example:"4"`
+	// Cells continue the kept part of the path, the full hint has the whole way from the start cell
+	Cells []Cell `json:"cells"`
+	// Price is paid for the hint once for the attempt of the level
+	Price int `json:"price" example:"100"`
 }
 
 type Cell struct {
@@ -66,12 +100,15 @@ type Cell struct {
 
 // GetUserLevel godoc
 // @Summary      Get current user level
+// @Description  The first request of the level attempt costs energy when the energy is enabled
+// @Description  and starts the time of the attempt.
 // @Tags         line-game
 // @Produce      json
 // @Security     BearerAuth
 // @Success      200  {object}  GetUserLevelResponse
 // @Failure      400  {object}  http_errors.ResponseError
 // @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
 // @Failure      500  {object}  http_errors.ResponseError
 // @Router       /game/line/level [get]
 func (l *LineGameHandler) GetUserLevel(w http.ResponseWriter, r *http.Request) {
@@ -100,6 +137,11 @@ func (l *LineGameHandler) GetUserLevel(w http.ResponseWriter, r *http.Request) {
 		},
 		Order:    make([]Cell, 0, len(level.Order)),
 		Blockers: make([]Cell, 0, len(level.Blockers)),
+		Endless:  level.Endless,
+		Hints:    make([]LevelHint, 0, len(level.Hints)),
+	}
+	for _, hint := range level.Hints {
+		resp.Hints = append(resp.Hints, newLevelHint(hint))
 	}
 	for _, cell := range level.Order {
 		resp.Order = append(resp.Order, Cell{X: cell.X, Y: cell.Y})
@@ -114,16 +156,42 @@ func (l *LineGameHandler) GetUserLevel(w http.ResponseWriter, r *http.Request) {
 }
 
 type CompleteLevelRequest struct {
-	Answer         [][]int `json:"answer"`
-	TimeSinceStart int     `json:"time_since_start" validate:"required" example:"1"`
+	Answer [][]int `json:"answer"`
 }
 
 type CompleteLevelResponse struct {
 	SoftCurrency int `json:"soft_currency"`
+	// Boosters are counts of rewarded boosters by their ids put into the inventory
+	Boosters map[string]int `json:"boosters,omitempty"`
+	// Stars is a rating of the completion from 1 to 3 by the reward tier
+	Stars int `json:"stars" example:"2"`
+	// TimeSinceStart is the completion time in seconds counted by the server from the first level request
+	TimeSinceStart float64 `json:"time_since_start" example:"25.4"`
+	// NextStar is null when the completion has the best rating
+	NextStar *StarThreshold `json:"next_star"`
+}
+
+type StarThreshold struct {
+	Stars int `json:"stars" example:"3"`
+	// MaxTime is seconds to complete the level faster than
+	MaxTime float64 `json:"max_time" example:"10"`
+}
+
+type LevelStars struct {
+	GroupCode string `json:"group_code" example:"3_0_0"`
+	LevelNum  int    `json:"level_num" example:"0"`
+	Stars     int    `json:"stars" example:"3"`
+	// BestTime is the best completion time in seconds
+	BestTime int `json:"best_time" example:"8"`
+}
+
+type GetLevelStarsResponse struct {
+	Levels []LevelStars `json:"levels"`
 }
 
 // CompleteLevel godoc
 // @Summary      Complete current user level
+// @Description  The completion time is counted from the first request of the level, the level must be requested before.
 // @Tags         line-game
 // @Produce      json
 // @Security     BearerAuth
@@ -132,6 +200,8 @@ type CompleteLevelResponse struct {
 // @Success      200  {object}  CompleteLevelResponse
 // @Failure      400  {object}  http_errors.ResponseError
 // @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      409  {object}  http_errors.ResponseError
 // @Failure      500  {object}  http_errors.ResponseError
 // @Router       /game/line/level [post]
 func (l *LineGameHandler) CompleteLevel(w http.ResponseWriter, r *http.Request) {
@@ -160,17 +230,23 @@ func (l *LineGameHandler) CompleteLevel(w http.ResponseWriter, r *http.Request)
 			}
 		}
 	}
-	reward, err := l.LineGameCompleteProcessor.TryCompleteUserLevel(
-		r.Context(), userID, req.Answer,
-		time.Duration(req.TimeSinceStart)*time.Second,
-	)
+	reward, err := l.LineGameCompleteProcessor.TryCompleteUserLevel(r.Context(), userID, req.Answer)
 	if err != nil {
 		http_errors.SendWrapped(w, err)
 		logs.Error("failed to complete level", err)
 		return
 	}
 	resp := CompleteLevelResponse{
-		reward.SoftCurrency,
+		SoftCurrency:   reward.SoftCurrency,
+		Boosters:       reward.Boosters,
+		Stars:          reward.Stars,
+		TimeSinceStart: reward.TimeSinceStart.Seconds(),
+	}
+	if reward.NextStar != nil {
+		resp.NextStar = &StarThreshold{
+			Stars:   reward.NextStar.Stars,
+			MaxTime: reward.NextStar.MaxTime.Seconds(),
+		}
 	}
 	if err = json.NewEncoder(w).Encode(resp); err != nil {
 		http_errors.SendWrapped(w, err)
@@ -182,8 +258,54 @@ type GetLevelHintResponse struct {
 	Answer [][]int `json:"answer" validate:"required"`
 }
 
+// GetLevelStars godoc
+// @Summary      Get star ratings of completed levels
+// @Description  Returns the best rating of every completed level for the level map.
+// @Tags         line-game
+// @Produce      json
+// @Security     BearerAuth
+// @Param        group_code  query  string  false  "Group code, all groups when empty"
+// @Success      200  {object}  GetLevelStarsResponse
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/line/stars [get]
+func (l *LineGameHandler) GetLevelStars(w http.ResponseWriter, r *http.Request) {
+	userID, err := l.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	levels, err := l.LineGameStarsProvider.GetLevelStars(
+		r.Context(), userID, model.LineGameLevelGroupCode(r.URL.Query().Get("group_code")),
+	)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to get level stars", err)
+		return
+	}
+	resp := GetLevelStarsResponse{
+		Levels: make([]LevelStars, 0, len(levels)),
+	}
+	for _, level := range levels {
+		resp.Levels = append(
+			resp.Levels, LevelStars{
+				GroupCode: string(level.GroupCode),
+				LevelNum:  level.LevelNum,
+				Stars:     level.Stars,
+				BestTime:  int(level.BestTime.Seconds()),
+			},
+		)
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
 // GetLevelHint godoc
 // @Summary      Get current user level's hint
+// @Description  The whole answer is paid as the full hint once for the level attempt.
 // @Tags         line-game
 // @Produce      json
 // @Security     BearerAuth
@@ -214,26 +336,149 @@ func (l *LineGameHandler) GetLevelHint(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// GetTimeStopBooster godoc
-// @Summary      Spend money on stop time booster
+type BuyLevelHintRequest struct {
+	Type string `json:"type" validate:"required,oneof=steps checkpoint full" example:"steps"`
+	// Path is the user path from the start cell, it is not used for the full hint
+	Path []Cell `json:"path"`
+}
+
+// BuyLevelHint godoc
+// @Summary      Buy a hint continuing the user path on the current level
+// @Description  Hint "steps" reveals the next few cells, "checkpoint" reveals cells up to the next order cell
+// @Description  or the end cell and "full" reveals the whole way. When the path can not be finished,
+// @Description  the hint continues its longest part which can be. Every hint type is paid once for the level
+// @Description  attempt, the bought hint is returned again for free.
+// @Tags         line-game
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body  BuyLevelHintRequest  true  "Hint type and user path"
+// @Success      200  {object}  LevelHint
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/line/hint [post]
+func (l *LineGameHandler) BuyLevelHint(w http.ResponseWriter, r *http.Request) {
+	userID, err := l.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	var req BuyLevelHintRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http_errors.SendBadRequest(w, "request body is invalid")
+		logs.Error("failed to decode the request", err)
+		return
+	}
+	if validationErr(w, l.validate, req) {
+		return
+	}
+	path := make([]model.LineGameLevelCell, 0, len(req.Path))
+	for _, cell := range req.Path {
+		path = append(path, model.LineGameLevelCell{X: cell.X, Y: cell.Y})
+	}
+	hint, err := l.LineGameBoosterProvider.BuyLevelHint(r.Context(), userID, model.LineGameHintType(req.Type), path)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to buy user level hint", err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(newLevelHint(hint)); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+func newLevelHint(hint model.LineGameHint) LevelHint {
+	resp := LevelHint{
+		Type:      string(hint.Type),
+		KeptCells: hint.KeptCells,
+		Cells:     make([]Cell, 0, len(hint.Cells)),
+		Price:     hint.Price,
+	}
+	for _, cell := range hint.Cells {
+		resp.Cells = append(resp.Cells, Cell{X: cell.X, Y: cell.Y})
+	}
+	return resp
+}
+
+type UseBoosterRequest struct {
+	// Path is the user path from the start cell, it is used by the reveal checkpoint booster
+	Path []Cell `json:"path"`
+}
+
+type UseBoosterResponse struct {
+	BoosterID string `json:"booster_id" example:"skip_level"`
+	Effect    string `json:"effect" example:"skip_level"`
+	// InventoryLeft is a count of boosters of the kind left in the inventory
+	InventoryLeft int `json:"inventory_left" example:"2"`
+	// UsesLeft is a count of uses left for the level attempt, it is -1 when the booster is unlimited
+	UsesLeft int `json:"uses_left" example:"0"`
+	// Hint is revealed by the reveal_checkpoint booster
+	Hint *LevelHint `json:"hint,omitempty"`
+}
+
+// UseBooster godoc
+// @Summary      Use a booster from the inventory for the current level
+// @Description  Boosters are configured in /config/boosters and bought in bundles via /game/inventory.
+// @Description  Effects "time_stop" and "undo" are applied by the client, "skip_level" moves to the next level
+// @Description  without a reward and "reveal_checkpoint" continues the path up to the next order cell or the end cell
+// @Description  like the checkpoint hint.
 // @Tags         line-game
+// @Accept       json
+// @Produce      json
 // @Security     BearerAuth
-// @Success      200
+// @Param        booster-id  path  string  true  "Booster id"
+// @Param        body  body  UseBoosterRequest  false  "User path for reveal_checkpoint"
+// @Success      200  {object}  UseBoosterResponse
 // @Failure      400  {object}  http_errors.ResponseError
 // @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      404  {object}  http_errors.ResponseError
+// @Failure      409  {object}  http_errors.ResponseError
 // @Failure      500  {object}  http_errors.ResponseError
-// @Router       /game/line/time-stop-booster [get]
-func (l *LineGameHandler) GetTimeStopBooster(w http.ResponseWriter, r *http.Request) {
+// @Router       /game/line/booster/{booster-id} [post]
+func (l *LineGameHandler) UseBooster(w http.ResponseWriter, r *http.Request) {
 	userID, err := l.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
 	if err != nil {
 		http_errors.SendWrapped(w, err)
 		logs.Error("failed to extract user id", err)
 		return
 	}
-	if err = l.LineGameBoosterProvider.GetTimeStopBooster(r.Context(), userID); err != nil {
+	var req UseBoosterRequest
+	if r.ContentLength != 0 {
+		if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http_errors.SendBadRequest(w, "request body is invalid")
+			logs.Error("failed to decode the request", err)
+			return
+		}
+	}
+	path := make([]model.LineGameLevelCell, 0, len(req.Path))
+	for _, cell := range req.Path {
+		path = append(path, model.LineGameLevelCell{X: cell.X, Y: cell.Y})
+	}
+	use, err := l.LineGameBoosterProvider.UseBooster(
+		r.Context(), userID, mux.Vars(r)[constantce.RequestVariableBoosterID], path,
+	)
+	if err != nil {
 		http_errors.SendWrapped(w, err)
-		logs.Error("failed to get time stop booster", err)
+		logs.Error("failed to use booster", err)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
+	resp := UseBoosterResponse{
+		BoosterID:     use.BoosterID,
+		Effect:        use.Effect,
+		InventoryLeft: use.InventoryLeft,
+		UsesLeft:      use.UsesLeft,
+	}
+	if use.Hint != nil {
+		hint := newLevelHint(*use.Hint)
+		resp.Hint = &hint
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
 }
@@ -4,35 +4,60 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/4units/mos-hack-game/back/internal/model/constantce"
 	http_errors "github.com/4units/mos-hack-game/back/pkg/http-errors"
 	logs "github.com/4units/mos-hack-game/back/pkg/logging"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 	"net/http"
+	"time"
+)
+
+const (
+	defaultQuizHistoryLimit = 20
+	maxQuizHistoryLimit     = 100
+	defaultQuizListLimit    = 20
+	maxQuizListLimit        = 100
 )
 
 type QuizSaver interface {
-	AddQuiz(
-		ctx context.Context, userID uuid.UUID, question string, answers []string,
-		correctAnswer int, infoLink, answerDescription string,
-	) error
-	UpdateQuiz(
-		ctx context.Context, userID, quizID uuid.UUID, question string, answers []string,
-		correctAnswer int, infoLink, answerDescription string,
-	) error
+	AddQuiz(ctx context.Context, userID uuid.UUID, quiz model.Quiz) (uuid.UUID, error)
+	UpdateQuiz(ctx context.Context, userID uuid.UUID, update model.QuizUpdate) error
+}
+
+type QuizModerator interface {
+	ChangeQuizStatus(ctx context.Context, userID, quizID uuid.UUID, status model.QuizStatus, comment string) error
+	GetQuizStatusTransitions(ctx context.Context, userID, quizID uuid.UUID) ([]model.QuizStatusTransition, error)
+	GetQuizzesForReview(ctx context.Context, userID uuid.UUID, limit int) ([]model.Quiz, error)
+	ArchiveQuiz(ctx context.Context, userID, quizID uuid.UUID) error
+}
+
+type QuizCatalogProvider interface {
+	SearchQuizzes(ctx context.Context, userID uuid.UUID, search model.QuizSearch) (model.QuizPage, error)
+	GetQuizVersions(ctx context.Context, userID, quizID uuid.UUID) ([]model.QuizVersion, error)
+	GetQuizStats(ctx context.Context, userID uuid.UUID, search model.QuizStatsSearch) (model.QuizStatsPage, error)
 }
 
 type QuizProvider interface {
-	GetRandomQuiz(ctx context.Context) (model.Quiz, error)
+	GetRandomQuiz(ctx context.Context, userID uuid.UUID, filter model.QuizFilter) (model.Quiz, error)
+	GetQuizHistory(ctx context.Context, userID uuid.UUID, limit int) ([]model.QuizAnswer, error)
 }
 
 type QuizAnswerProcessor interface {
-	TryCompleteQuiz(ctx context.Context, userID, quizID uuid.UUID, answer int) (int, error)
+	TryCompleteQuiz(
+		ctx context.Context,
+		userID, quizID uuid.UUID,
+		answer model.QuizAnswerValue,
+	) (model.QuizAnswerResult, error)
 }
 
 type QuizHandlerDeps struct {
 	QuizProvider          QuizProvider
 	NewQuizConsumer       QuizSaver
+	QuizModerator         QuizModerator
+	QuizCatalogProvider   QuizCatalogProvider
+	QuizImporter          QuizImporter
 	QuizCompleteProcessor QuizAnswerProcessor
 	UserIDExtractor       UserIDExtractor
 }
@@ -50,33 +75,71 @@ func NewQuizHandler(deps QuizHandlerDeps) *QuizHandler {
 }
 
 type GetQuizResponse struct {
-	ID                uuid.UUID `json:"id"`
-	Question          string    `json:"question"`
-	Answers           []string  `json:"answer"`
-	InfoLink          string    `json:"info_link"`
-	AnswerDescription string    `json:"answer_description"`
+	ID       uuid.UUID `json:"id"`
+	Type     string    `json:"type" example:"single_choice"`
+	Question string    `json:"question"`
+	// Answers are options of the quiz, numeric quizzes have no options
+	Answers    []string `json:"answer"`
+	InfoLink   string   `json:"info_link"`
+	Category   string   `json:"category" example:"deposits"`
+	Difficulty string   `json:"difficulty" example:"medium"`
+	Tags       []string `json:"tags" example:"savings,interest"`
+}
+
+type GetQuizRequest struct {
+	Category   string `validate:"lte=32"`
+	Difficulty string `validate:"omitempty,oneof=easy medium hard"`
 }
 
 // GetQuiz godoc
 // @Summary      Get quiz
+// @Description  Quizzes which the user has not answered yet are returned first.
+// @Description  Category and difficulty limit the choice, 404 is returned when no quiz matches them.
+// @Description  The answer description is returned only with the answer.
 // @Tags         quiz
 // @Produce      json
+// @Security     BearerAuth
+// @Param        category    query  string  false  "Quiz category"
+// @Param        difficulty  query  string  false  "Quiz difficulty"  Enums(easy, medium, hard)
 // @Success      200  {object}  GetQuizResponse
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      404  {object}  http_errors.ResponseError
 // @Failure      500  {object}  http_errors.ResponseError
 // @Router       /game/quiz [get]
 func (q QuizHandler) GetQuiz(w http.ResponseWriter, r *http.Request) {
-	quiz, err := q.QuizProvider.GetRandomQuiz(r.Context())
+	userID, err := q.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	req := GetQuizRequest{
+		Category:   r.URL.Query().Get("category"),
+		Difficulty: r.URL.Query().Get("difficulty"),
+	}
+	if validationErr(w, q.validate, req) {
+		return
+	}
+	quiz, err := q.QuizProvider.GetRandomQuiz(
+		r.Context(), userID, model.QuizFilter{
+			Category:   req.Category,
+			Difficulty: req.Difficulty,
+		},
+	)
 	if err != nil {
 		http_errors.SendWrapped(w, err)
 		logs.Error("failed to get random quiz", err)
 		return
 	}
 	resp := GetQuizResponse{
-		ID:                quiz.ID,
-		Question:          quiz.Question,
-		Answers:           quiz.Answers,
-		InfoLink:          quiz.InfoLink,
-		AnswerDescription: quiz.AnswerDescription,
+		ID:         quiz.ID,
+		Type:       string(quiz.TypeOrDefault()),
+		Question:   quiz.Question,
+		Answers:    quiz.Answers,
+		InfoLink:   quiz.InfoLink,
+		Category:   quiz.Category,
+		Difficulty: quiz.Difficulty,
+		Tags:       quiz.Tags,
 	}
 	if json.NewEncoder(w).Encode(resp); err != nil {
 		http_errors.SendWrapped(w, err)
@@ -85,17 +148,111 @@ func (q QuizHandler) GetQuiz(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// QuizAnswerFields are an answer to the quiz of any type, options are numbered from 1
+type QuizAnswerFields struct {
+	// Answer is the chosen option of single choice and true/false quizzes
+	Answer int `json:"answer" example:"2"`
+	// Options are chosen options of multiple choice quizzes or all options in the order for ordering quizzes
+	Options []int `json:"options,omitempty" validate:"lte=50" example:"3,1,2"`
+	// Number is the answer to numeric quizzes
+	Number *float64 `json:"number,omitempty" example:"12.5"`
+}
+
 type AnswerQuizRequest struct {
-	ID     uuid.UUID `json:"id" validate:"required"`
-	Answer int       `json:"answer"`
+	ID uuid.UUID `json:"id" validate:"required"`
+	QuizAnswerFields
 }
 
 type AnswerQuizResponse struct {
+	Correct bool `json:"correct"`
+	// SoftCurrency is zero for wrong answers, repeated correct answers and retries without a reward
 	SoftCurrency int `json:"soft_currency"`
+	// Attempt is a number of the answer to the quiz starting from 1, retries are rewarded less
+	Attempt int    `json:"attempt" example:"2"`
+	Type    string `json:"type" example:"single_choice"`
+	// KeyRevealed is false for wrong answers while a rewarded retry is possible, the correct answer fields
+	// are empty then
+	KeyRevealed bool `json:"key_revealed"`
+	// CorrectAnswer is the correct option of single choice and true/false quizzes
+	CorrectAnswer int `json:"correct_answer" example:"2"`
+	// CorrectAnswers are correct options of multiple choice quizzes or the correct order of ordering quizzes
+	CorrectAnswers    []int   `json:"correct_answers" example:"3,1,2"`
+	NumericAnswer     float64 `json:"numeric_answer" example:"12.5"`
+	NumericTolerance  float64 `json:"numeric_tolerance" example:"0.5"`
+	AnswerDescription string  `json:"answer_description"`
+	InfoLink          string  `json:"info_link"`
+}
+
+type QuizHistoryAnswer struct {
+	QuizID uuid.UUID `json:"quiz_id"`
+	QuizAnswerFields
+	Correct    bool      `json:"correct"`
+	Rewarded   bool      `json:"rewarded"`
+	AnsweredAt time.Time `json:"answered_at" example:"2025-10-19T12:00:00Z"`
+}
+
+type GetQuizHistoryResponse struct {
+	Answers []QuizHistoryAnswer `json:"answers"`
+}
+
+// GetQuizHistory godoc
+// @Summary      Get quiz answer history
+// @Tags         quiz
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit  query  int  false  "Count of the last answers, 20 by default and 100 at most"
+// @Success      200  {object}  GetQuizHistoryResponse
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/quiz/history [get]
+func (q QuizHandler) GetQuizHistory(w http.ResponseWriter, r *http.Request) {
+	userID, err := q.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	limit, err := intQueryParam(r.URL.Query().Get("limit"), defaultQuizHistoryLimit)
+	if err != nil {
+		http_errors.SendBadRequest(w, "limit is invalid")
+		logs.Error("failed to parse limit", err)
+		return
+	}
+	if limit <= 0 || limit > maxQuizHistoryLimit {
+		http_errors.SendBadRequest(w, "limit is invalid")
+		return
+	}
+	answers, err := q.QuizProvider.GetQuizHistory(r.Context(), userID, limit)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to get quiz history", err)
+		return
+	}
+	resp := GetQuizHistoryResponse{
+		Answers: make([]QuizHistoryAnswer, 0, len(answers)),
+	}
+	for _, answer := range answers {
+		resp.Answers = append(
+			resp.Answers, QuizHistoryAnswer{
+				QuizID:           answer.QuizID,
+				QuizAnswerFields: newQuizAnswerFields(answer.Answer),
+				Correct:          answer.Correct,
+				Rewarded:         answer.Rewarded,
+				AnsweredAt:       answer.AnsweredAt,
+			},
+		)
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
 }
 
 // AnswerQuiz godoc
 // @Summary      Complete current user quiz
+// @Description  Every answer gets the explanation and the info link, retries are rewarded less.
+// @Description  The correct answer is returned for wrong answers only when no rewarded retry is possible.
 // @Tags         quiz
 // @Produce      json
 // @Accept       json
@@ -122,14 +279,24 @@ func (q QuizHandler) AnswerQuiz(w http.ResponseWriter, r *http.Request) {
 	if validationErr(w, q.validate, req) {
 		return
 	}
-	reward, err := q.QuizCompleteProcessor.TryCompleteQuiz(r.Context(), userID, req.ID, req.Answer)
+	result, err := q.QuizCompleteProcessor.TryCompleteQuiz(r.Context(), userID, req.ID, req.QuizAnswerValue())
 	if err != nil {
 		http_errors.SendWrapped(w, err)
 		logs.Error("failed to complete quiz", err)
 		return
 	}
 	resp := AnswerQuizResponse{
-		SoftCurrency: reward,
+		Correct:           result.Correct,
+		SoftCurrency:      result.Reward,
+		Attempt:           result.Attempt,
+		Type:              string(result.Quiz.TypeOrDefault()),
+		KeyRevealed:       result.KeyRevealed,
+		CorrectAnswer:     result.Quiz.CorrectAnswer,
+		CorrectAnswers:    result.Quiz.CorrectAnswers,
+		NumericAnswer:     result.Quiz.NumericAnswer,
+		NumericTolerance:  result.Quiz.NumericTolerance,
+		AnswerDescription: result.Quiz.AnswerDescription,
+		InfoLink:          result.Quiz.InfoLink,
 	}
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		http_errors.SendWrapped(w, err)
@@ -138,22 +305,44 @@ func (q QuizHandler) AnswerQuiz(w http.ResponseWriter, r *http.Request) {
 }
 
 type AddQuizRequest struct {
-	Question          string   `json:"question" validate:"required,lte=130,gt=0"`
-	Answers           []string `json:"answers" validate:"required,lte=50,gt=0"`
-	CorrectAnswer     int      `json:"correct_answer" validate:"required,gt=0"`
-	InfoLink          string   `json:"info_link" validate:"required,url"`
-	AnswerDescription string   `json:"answer_description"`
+	// Type is single_choice by default
+	Type     string `json:"type" validate:"omitempty,oneof=single_choice multiple_choice true_false numeric ordering"`
+	Question string `json:"question" validate:"required,lte=130,gt=0"`
+	// Answers are options of the quiz, numeric quizzes have no options and true/false quizzes have 2 options
+	Answers []string `json:"answers" validate:"lte=50"`
+	// CorrectAnswer is the correct option of single choice and true/false quizzes starting from 1
+	CorrectAnswer int `json:"correct_answer" validate:"gte=0" example:"2"`
+	// CorrectAnswers are correct options of multiple choice quizzes or all options in the correct order
+	// for ordering quizzes, the correct order must differ from the order of answers
+	CorrectAnswers []int `json:"correct_answers" validate:"lte=50" example:"3,1,2"`
+	// NumericAnswer is the answer to numeric quizzes, answers differing by NumericTolerance at most are correct
+	NumericAnswer     float64 `json:"numeric_answer" example:"12.5"`
+	NumericTolerance  float64 `json:"numeric_tolerance" validate:"gte=0" example:"0.5"`
+	InfoLink          string  `json:"info_link" validate:"required,url"`
+	AnswerDescription string  `json:"answer_description"`
+	Category          string  `json:"category" validate:"lte=32" example:"deposits"`
+	// Difficulty is medium by default
+	Difficulty string   `json:"difficulty" validate:"omitempty,oneof=easy medium hard" example:"medium"`
+	Tags       []string `json:"tags" validate:"lte=10,dive,gt=0,lte=32" example:"savings,interest"`
+}
+
+type AddQuizResponse struct {
+	ID uuid.UUID `json:"id"`
 }
 
 // AddQuiz godoc
 // @Summary      Add quiz
+// @Description  The quiz is saved as a draft of the user, it is shown to players after it is submitted for review
+// @Description  via PUT /game/quiz/{quiz-id}/status and approved by an admin.
 // @Tags         quiz
 // @Accept       json
+// @Produce      json
 // @Security     BearerAuth
 // @Param        body  body  AddQuizRequest  true  "Add quiz data"
-// @Success      201
+// @Success      201  {object}  AddQuizResponse
 // @Failure      400  {object}  http_errors.ResponseError
 // @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
 // @Failure      500  {object}  http_errors.ResponseError
 // @Router       /game/quiz [post]
 func (q QuizHandler) AddQuiz(w http.ResponseWriter, r *http.Request) {
@@ -172,27 +361,40 @@ func (q QuizHandler) AddQuiz(w http.ResponseWriter, r *http.Request) {
 	if validationErr(w, q.validate, req) {
 		return
 	}
-	if err = q.NewQuizConsumer.AddQuiz(
-		r.Context(), userID, req.Question, req.Answers, req.CorrectAnswer, req.InfoLink, req.AnswerDescription,
-	); err != nil {
+	quizID, err := q.NewQuizConsumer.AddQuiz(r.Context(), userID, req.quiz())
+	if err != nil {
 		http_errors.SendWrapped(w, err)
 		logs.Error("failed to add quiz", err)
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(AddQuizResponse{ID: quizID}); err != nil {
+		logs.Error("failed to encode response", err)
+	}
 }
 
 type UpdateQuizRequest struct {
-	ID                uuid.UUID `json:"id" validate:"required"`
-	Question          string    `json:"question"`
-	Answers           []string  `json:"answers"`
-	CorrectAnswer     int       `json:"correct_answer"`
-	InfoLink          string    `json:"info_link,url"`
-	AnswerDescription string    `json:"answer_description"`
+	ID       uuid.UUID `json:"id" validate:"required"`
+	Type     *string   `json:"type" validate:"omitempty,oneof=single_choice multiple_choice true_false numeric ordering"`
+	Question string    `json:"question"`
+	// Answers replace the options when they are present, an empty list removes them for numeric quizzes
+	Answers []string `json:"answers" validate:"omitempty,lte=50"`
+	// CorrectAnswer is changed when it is present, zero is set when the type is changed to one without it
+	CorrectAnswer     *int     `json:"correct_answer" validate:"omitempty,gte=0" example:"2"`
+	CorrectAnswers    []int    `json:"correct_answers" validate:"omitempty,lte=50" example:"3,1,2"`
+	NumericAnswer     *float64 `json:"numeric_answer" example:"12.5"`
+	NumericTolerance  *float64 `json:"numeric_tolerance" validate:"omitempty,gte=0" example:"0.5"`
+	InfoLink          string   `json:"info_link" validate:"omitempty,url"`
+	AnswerDescription string   `json:"answer_description"`
+	Category          string   `json:"category" validate:"lte=32" example:"deposits"`
+	Difficulty        string   `json:"difficulty" validate:"omitempty,oneof=easy medium hard" example:"hard"`
+	// Tags replace the tags of the quiz when they are present, an empty list removes them
+	Tags []string `json:"tags" validate:"omitempty,lte=10,dive,gt=0,lte=32" example:"fraud"`
 }
 
 // UpdateQuiz godoc
 // @Summary      Update quiz
+// @Description  Quiz writers can edit only their drafts, admins can edit any quiz.
 // @Tags         quiz
 // @Accept       json
 // @Security     BearerAuth
@@ -200,6 +402,9 @@ type UpdateQuizRequest struct {
 // @Success      201
 // @Failure      400  {object}  http_errors.ResponseError
 // @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      404  {object}  http_errors.ResponseError
+// @Failure      409  {object}  http_errors.ResponseError
 // @Failure      500  {object}  http_errors.ResponseError
 // @Router       /game/quiz [put]
 func (q QuizHandler) UpdateQuiz(w http.ResponseWriter, r *http.Request) {
@@ -218,12 +423,510 @@ func (q QuizHandler) UpdateQuiz(w http.ResponseWriter, r *http.Request) {
 	if validationErr(w, q.validate, req) {
 		return
 	}
-	if err = q.NewQuizConsumer.UpdateQuiz(
-		r.Context(), userID, req.ID, req.Question, req.Answers, req.CorrectAnswer, req.InfoLink, req.AnswerDescription,
-	); err != nil {
+	update := model.QuizUpdate{
+		ID:                req.ID,
+		Question:          optionalString(req.Question),
+		Answers:           req.Answers,
+		CorrectAnswer:     req.CorrectAnswer,
+		CorrectAnswers:    req.CorrectAnswers,
+		NumericAnswer:     req.NumericAnswer,
+		NumericTolerance:  req.NumericTolerance,
+		InfoLink:          optionalString(req.InfoLink),
+		AnswerDescription: optionalString(req.AnswerDescription),
+		Category:          optionalString(req.Category),
+		Difficulty:        optionalString(req.Difficulty),
+		Tags:              req.Tags,
+	}
+	if req.Type != nil {
+		quizType := model.QuizType(*req.Type)
+		update.Type = &quizType
+	}
+	if err = q.NewQuizConsumer.UpdateQuiz(r.Context(), userID, update); err != nil {
 		http_errors.SendWrapped(w, err)
 		logs.Error("failed to add quiz", err)
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
 }
+
+type ChangeQuizStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=draft in_review published archived" example:"in_review"`
+	// Comment is required to reject a quiz in review by moving it back to the draft
+	Comment string `json:"comment" validate:"lte=1000" example:"The link does not work"`
+}
+
+// ChangeQuizStatus godoc
+// @Summary      Change quiz status
+// @Description  Quizzes move from draft to in_review, then to published or back to draft, published ones are
+// @Description  archived and archived ones go to draft. Quiz writers can only submit their drafts for review,
+// @Description  admins approve or reject quizzes in review, the rejection requires a comment.
+// @Tags         quiz
+// @Accept       json
+// @Security     BearerAuth
+// @Param        quiz-id  path  string  true  "Quiz id"
+// @Param        body  body  ChangeQuizStatusRequest  true  "New status"
+// @Success      204
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      404  {object}  http_errors.ResponseError
+// @Failure      409  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/quiz/{quiz-id}/status [put]
+func (q QuizHandler) ChangeQuizStatus(w http.ResponseWriter, r *http.Request) {
+	userID, err := q.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	quizID, err := uuid.Parse(mux.Vars(r)[constantce.RequestVariableQuizID])
+	if err != nil {
+		http_errors.SendBadRequest(w, "quiz id is invalid")
+		logs.Error("failed to parse quiz id", err)
+		return
+	}
+	var req ChangeQuizStatusRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http_errors.SendBadRequest(w, "request body is invalid")
+		logs.Error("failed to decode the request", err)
+		return
+	}
+	if validationErr(w, q.validate, req) {
+		return
+	}
+	if err = q.QuizModerator.ChangeQuizStatus(
+		r.Context(), userID, quizID, model.QuizStatus(req.Status), req.Comment,
+	); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to change quiz status", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type QuizStatusTransitionResponse struct {
+	// From is empty for the creation of the quiz
+	From      string    `json:"from" example:"in_review"`
+	To        string    `json:"to" example:"draft"`
+	UserID    uuid.UUID `json:"user_id"`
+	Comment   string    `json:"comment" example:"The link does not work"`
+	CreatedAt time.Time `json:"created_at" example:"2025-10-19T12:00:00Z"`
+}
+
+type GetQuizStatusTransitionsResponse struct {
+	Transitions []QuizStatusTransitionResponse `json:"transitions"`
+}
+
+// GetQuizStatusTransitions godoc
+// @Summary      Get quiz moderation history
+// @Description  Returns status changes of the quiz with comments of admins, the oldest change is first.
+// @Description  It is available to the author of the quiz and admins.
+// @Tags         quiz
+// @Produce      json
+// @Security     BearerAuth
+// @Param        quiz-id  path  string  true  "Quiz id"
+// @Success      200  {object}  GetQuizStatusTransitionsResponse
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      404  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/quiz/{quiz-id}/transitions [get]
+func (q QuizHandler) GetQuizStatusTransitions(w http.ResponseWriter, r *http.Request) {
+	userID, err := q.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	quizID, err := uuid.Parse(mux.Vars(r)[constantce.RequestVariableQuizID])
+	if err != nil {
+		http_errors.SendBadRequest(w, "quiz id is invalid")
+		logs.Error("failed to parse quiz id", err)
+		return
+	}
+	transitions, err := q.QuizModerator.GetQuizStatusTransitions(r.Context(), userID, quizID)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to get quiz status transitions", err)
+		return
+	}
+	resp := GetQuizStatusTransitionsResponse{
+		Transitions: make([]QuizStatusTransitionResponse, 0, len(transitions)),
+	}
+	for _, transition := range transitions {
+		resp.Transitions = append(
+			resp.Transitions, QuizStatusTransitionResponse{
+				From:      string(transition.From),
+				To:        string(transition.To),
+				UserID:    transition.UserID,
+				Comment:   transition.Comment,
+				CreatedAt: transition.CreatedAt,
+			},
+		)
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+// QuizDetails is a quiz with the correct answer and the moderation status shown to writers and admins
+type QuizDetails struct {
+	ID                uuid.UUID `json:"id"`
+	Type              string    `json:"type" example:"single_choice"`
+	Question          string    `json:"question"`
+	Answers           []string  `json:"answers"`
+	CorrectAnswer     int       `json:"correct_answer" example:"2"`
+	CorrectAnswers    []int     `json:"correct_answers" example:"3,1,2"`
+	NumericAnswer     float64   `json:"numeric_answer" example:"12.5"`
+	NumericTolerance  float64   `json:"numeric_tolerance" example:"0.5"`
+	InfoLink          string    `json:"info_link"`
+	AnswerDescription string    `json:"answer_description"`
+	Category          string    `json:"category" example:"deposits"`
+	Difficulty        string    `json:"difficulty" example:"medium"`
+	Tags              []string  `json:"tags" example:"savings,interest"`
+	Status            string    `json:"status" example:"in_review"`
+	// AuthorID is absent for quizzes added before the moderation
+	AuthorID  *uuid.UUID `json:"author_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at" example:"2025-10-19T12:00:00Z"`
+	Version   int        `json:"version" example:"3"`
+}
+
+type GetQuizListResponse struct {
+	Quizzes []QuizDetails `json:"quizzes"`
+}
+
+// GetQuizzesForReview godoc
+// @Summary      Get quizzes for review
+// @Description  Returns quizzes submitted for review, the oldest quiz is first. Quizzes are approved or rejected
+// @Description  via PUT /game/quiz/{quiz-id}/status.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit  query  int  false  "Count of quizzes, 20 by default and 100 at most"
+// @Success      200  {object}  GetQuizListResponse
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /admin/quiz/review [get]
+func (q QuizHandler) GetQuizzesForReview(w http.ResponseWriter, r *http.Request) {
+	userID, err := q.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	limit, err := intQueryParam(r.URL.Query().Get("limit"), defaultQuizListLimit)
+	if err != nil {
+		http_errors.SendBadRequest(w, "limit is invalid")
+		logs.Error("failed to parse limit", err)
+		return
+	}
+	if limit <= 0 || limit > maxQuizListLimit {
+		http_errors.SendBadRequest(w, "limit is invalid")
+		return
+	}
+	quizzes, err := q.QuizModerator.GetQuizzesForReview(r.Context(), userID, limit)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to get quizzes for review", err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(newGetQuizListResponse(quizzes)); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+func newGetQuizListResponse(quizzes []model.Quiz) GetQuizListResponse {
+	resp := GetQuizListResponse{
+		Quizzes: make([]QuizDetails, 0, len(quizzes)),
+	}
+	for _, quiz := range quizzes {
+		resp.Quizzes = append(resp.Quizzes, newQuizDetails(quiz))
+	}
+	return resp
+}
+
+func newQuizDetails(quiz model.Quiz) QuizDetails {
+	details := QuizDetails{
+		ID:                quiz.ID,
+		Type:              string(quiz.TypeOrDefault()),
+		Question:          quiz.Question,
+		Answers:           quiz.Answers,
+		CorrectAnswer:     quiz.CorrectAnswer,
+		CorrectAnswers:    quiz.CorrectAnswers,
+		NumericAnswer:     quiz.NumericAnswer,
+		NumericTolerance:  quiz.NumericTolerance,
+		InfoLink:          quiz.InfoLink,
+		AnswerDescription: quiz.AnswerDescription,
+		Category:          quiz.Category,
+		Difficulty:        quiz.Difficulty,
+		Tags:              quiz.Tags,
+		Status:            string(quiz.Status),
+		CreatedAt:         quiz.CreatedAt,
+		Version:           quiz.Version,
+	}
+	if quiz.AuthorID != uuid.Nil {
+		details.AuthorID = &quiz.AuthorID
+	}
+	return details
+}
+
+type SearchQuizzesRequest struct {
+	Text     string `validate:"lte=130"`
+	Category string `validate:"lte=32"`
+	Status   string `validate:"omitempty,oneof=draft in_review published archived"`
+	AuthorID string `validate:"omitempty,uuid"`
+	Limit    int    `validate:"gt=0,lte=100"`
+	Offset   int    `validate:"gte=0"`
+}
+
+type SearchQuizzesResponse struct {
+	Quizzes []QuizDetails `json:"quizzes"`
+	// Total is a count of all found quizzes for the pagination
+	Total int `json:"total" example:"42"`
+}
+
+// SearchQuizzes godoc
+// @Summary      Search quizzes
+// @Description  Returns quizzes of any status to quiz writers and admins, the newest quiz is first.
+// @Description  The text is searched in questions and answer descriptions ignoring the case.
+// @Tags         quiz
+// @Produce      json
+// @Security     BearerAuth
+// @Param        text       query  string  false  "Text in the question or the answer description"
+// @Param        category   query  string  false  "Quiz category"
+// @Param        status     query  string  false  "Quiz status"  Enums(draft, in_review, published, archived)
+// @Param        author_id  query  string  false  "Author id"
+// @Param        limit      query  int     false  "Count of quizzes on the page, 20 by default and 100 at most"
+// @Param        offset     query  int     false  "Count of skipped quizzes"
+// @Success      200  {object}  SearchQuizzesResponse
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/quiz/list [get]
+func (q QuizHandler) SearchQuizzes(w http.ResponseWriter, r *http.Request) {
+	userID, err := q.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	values := r.URL.Query()
+	req := SearchQuizzesRequest{
+		Text:     values.Get("text"),
+		Category: values.Get("category"),
+		Status:   values.Get("status"),
+		AuthorID: values.Get("author_id"),
+	}
+	if req.Limit, err = intQueryParam(values.Get("limit"), defaultQuizListLimit); err != nil {
+		http_errors.SendBadRequest(w, "limit is invalid")
+		return
+	}
+	if req.Offset, err = intQueryParam(values.Get("offset"), 0); err != nil {
+		http_errors.SendBadRequest(w, "offset is invalid")
+		return
+	}
+	if validationErr(w, q.validate, req) {
+		return
+	}
+	search := model.QuizSearch{
+		Text:     req.Text,
+		Category: req.Category,
+		Status:   model.QuizStatus(req.Status),
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	}
+	if req.AuthorID != "" {
+		search.AuthorID = uuid.MustParse(req.AuthorID)
+	}
+	page, err := q.QuizCatalogProvider.SearchQuizzes(r.Context(), userID, search)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to search quizzes", err)
+		return
+	}
+	resp := SearchQuizzesResponse{
+		Quizzes: newGetQuizListResponse(page.Quizzes).Quizzes,
+		Total:   page.Total,
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+// ArchiveQuiz godoc
+// @Summary      Archive quiz
+// @Description  Soft deletes the quiz: it is hidden from players and the review, but its history is kept and
+// @Description  admins can move it back to drafts. Quiz writers can archive only their drafts.
+// @Tags         quiz
+// @Security     BearerAuth
+// @Param        quiz-id  path  string  true  "Quiz id"
+// @Success      204
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      404  {object}  http_errors.ResponseError
+// @Failure      409  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/quiz/{quiz-id} [delete]
+func (q QuizHandler) ArchiveQuiz(w http.ResponseWriter, r *http.Request) {
+	userID, err := q.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	quizID, err := uuid.Parse(mux.Vars(r)[constantce.RequestVariableQuizID])
+	if err != nil {
+		http_errors.SendBadRequest(w, "quiz id is invalid")
+		logs.Error("failed to parse quiz id", err)
+		return
+	}
+	if err = q.QuizModerator.ArchiveQuiz(r.Context(), userID, quizID); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to archive quiz", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type QuizVersionResponse struct {
+	Version   int         `json:"version" example:"2"`
+	Quiz      QuizContent `json:"quiz"`
+	EditorID  *uuid.UUID  `json:"editor_id,omitempty"`
+	CreatedAt time.Time   `json:"created_at" example:"2025-10-19T12:00:00Z"`
+}
+
+// QuizContent is a saved version of quiz contents
+type QuizContent struct {
+	Type              string   `json:"type" example:"single_choice"`
+	Question          string   `json:"question"`
+	Answers           []string `json:"answers"`
+	CorrectAnswer     int      `json:"correct_answer" example:"2"`
+	CorrectAnswers    []int    `json:"correct_answers" example:"3,1,2"`
+	NumericAnswer     float64  `json:"numeric_answer" example:"12.5"`
+	NumericTolerance  float64  `json:"numeric_tolerance" example:"0.5"`
+	InfoLink          string   `json:"info_link"`
+	AnswerDescription string   `json:"answer_description"`
+	Category          string   `json:"category" example:"deposits"`
+	Difficulty        string   `json:"difficulty" example:"medium"`
+	Tags              []string `json:"tags" example:"savings,interest"`
+}
+
+type GetQuizVersionsResponse struct {
+	Versions []QuizVersionResponse `json:"versions"`
+}
+
+// GetQuizVersions godoc
+// @Summary      Get quiz edit history
+// @Description  Returns every saved version of the quiz contents with its editor, the newest version is first.
+// @Tags         quiz
+// @Produce      json
+// @Security     BearerAuth
+// @Param        quiz-id  path  string  true  "Quiz id"
+// @Success      200  {object}  GetQuizVersionsResponse
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      404  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/quiz/{quiz-id}/versions [get]
+func (q QuizHandler) GetQuizVersions(w http.ResponseWriter, r *http.Request) {
+	userID, err := q.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	quizID, err := uuid.Parse(mux.Vars(r)[constantce.RequestVariableQuizID])
+	if err != nil {
+		http_errors.SendBadRequest(w, "quiz id is invalid")
+		logs.Error("failed to parse quiz id", err)
+		return
+	}
+	versions, err := q.QuizCatalogProvider.GetQuizVersions(r.Context(), userID, quizID)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to get quiz versions", err)
+		return
+	}
+	resp := GetQuizVersionsResponse{
+		Versions: make([]QuizVersionResponse, 0, len(versions)),
+	}
+	for _, version := range versions {
+		versionResp := QuizVersionResponse{
+			Version: version.Version,
+			Quiz: QuizContent{
+				Type:              string(version.Quiz.TypeOrDefault()),
+				Question:          version.Quiz.Question,
+				Answers:           version.Quiz.Answers,
+				CorrectAnswer:     version.Quiz.CorrectAnswer,
+				CorrectAnswers:    version.Quiz.CorrectAnswers,
+				NumericAnswer:     version.Quiz.NumericAnswer,
+				NumericTolerance:  version.Quiz.NumericTolerance,
+				InfoLink:          version.Quiz.InfoLink,
+				AnswerDescription: version.Quiz.AnswerDescription,
+				Category:          version.Quiz.Category,
+				Difficulty:        version.Quiz.Difficulty,
+				Tags:              version.Quiz.Tags,
+			},
+			CreatedAt: version.CreatedAt,
+		}
+		if version.EditorID != uuid.Nil {
+			versionResp.EditorID = &version.EditorID
+		}
+		resp.Versions = append(resp.Versions, versionResp)
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+func (r AddQuizRequest) quiz() model.Quiz {
+	return model.Quiz{
+		Type:              model.QuizType(r.Type),
+		Question:          r.Question,
+		Answers:           r.Answers,
+		CorrectAnswer:     r.CorrectAnswer,
+		CorrectAnswers:    r.CorrectAnswers,
+		NumericAnswer:     r.NumericAnswer,
+		NumericTolerance:  r.NumericTolerance,
+		InfoLink:          r.InfoLink,
+		AnswerDescription: r.AnswerDescription,
+		Category:          r.Category,
+		Difficulty:        r.Difficulty,
+		Tags:              r.Tags,
+	}
+}
+
+func (f QuizAnswerFields) QuizAnswerValue() model.QuizAnswerValue {
+	return model.QuizAnswerValue{
+		Option:  f.Answer,
+		Options: f.Options,
+		Number:  f.Number,
+	}
+}
+
+func newQuizAnswerFields(answer model.QuizAnswerValue) QuizAnswerFields {
+	return QuizAnswerFields{
+		Answer:  answer.Option,
+		Options: answer.Options,
+		Number:  answer.Number,
+	}
+}
+
+// optionalString returns nil for empty strings which are not changed by updates
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,451 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	http_errors "github.com/4units/mos-hack-game/back/pkg/http-errors"
+	logs "github.com/4units/mos-hack-game/back/pkg/logging"
+	"github.com/google/uuid"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxQuizImportRows  = 1000
+	maxQuizImportBytes = 4 << 20
+	// quizListSeparator separates answers and tags in one CSV column
+	quizListSeparator = '|'
+	// quizListEscape escapes the separator and itself in answers and tags
+	quizListEscape = '\\'
+	// quizCSVFormulaPrefixes start cells which spreadsheets evaluate as formulas
+	quizCSVFormulaPrefixes = "=+-@"
+)
+
+var (
+	errQuizImportTooManyRows = fmt.Errorf("batch has more than %d quizzes", maxQuizImportRows)
+	// quizCSVColumns are columns of exported quizzes, import reads the same columns except id and status
+	quizCSVColumns = []string{
+		"id", "status", "type", "question", "answers", "correct_answer", "correct_answers", "numeric_answer",
+		"numeric_tolerance", "info_link", "answer_description", "category", "difficulty", "tags",
+	}
+	quizCSVRequiredColumns = []string{"question", "info_link"}
+)
+
+type QuizImporter interface {
+	ImportQuizzes(
+		ctx context.Context,
+		userID uuid.UUID,
+		rows []model.QuizImportRow,
+		dryRun bool,
+	) (model.QuizImportReport, error)
+	ExportQuizzes(ctx context.Context, userID uuid.UUID, search model.QuizSearch) ([]model.Quiz, error)
+}
+
+type QuizImportRowResult struct {
+	// Row is a number of the quiz in the batch starting from 1, the CSV header is not counted
+	Row    int    `json:"row" example:"3"`
+	Status string `json:"status" example:"duplicate" enums:"created,valid,duplicate,invalid"`
+	// QuizID is an id of the created quiz
+	QuizID *uuid.UUID `json:"quiz_id,omitempty"`
+	// DuplicateOfQuizID is an id of the saved quiz with the same question
+	DuplicateOfQuizID *uuid.UUID `json:"duplicate_of_quiz_id,omitempty"`
+	// DuplicateOfRow is a number of the earlier row of the batch with the same question
+	DuplicateOfRow int    `json:"duplicate_of_row,omitempty" example:"1"`
+	Error          string `json:"error,omitempty"`
+}
+
+type ImportQuizzesResponse struct {
+	DryRun bool `json:"dry_run"`
+	// Created is a count of created quizzes or quizzes which would be created in the dry run
+	Created    int                   `json:"created" example:"40"`
+	Duplicates int                   `json:"duplicates" example:"2"`
+	Invalid    int                   `json:"invalid" example:"1"`
+	Results    []QuizImportRowResult `json:"results"`
+}
+
+type ExportQuizzesRequest struct {
+	Format   string `validate:"oneof=csv json"`
+	Text     string `validate:"lte=130"`
+	Category string `validate:"lte=32"`
+	Status   string `validate:"omitempty,oneof=draft in_review published archived"`
+	AuthorID string `validate:"omitempty,uuid"`
+}
+
+// ImportQuizzes godoc
+// @Summary      Import quizzes
+// @Description  Saves a batch of quizzes as drafts of the user. The batch is a JSON array of quizzes like in
+// @Description  POST /game/quiz or a CSV file with the header, answers and tags are separated by "|" in CSV,
+// @Description  "|" and "\" in them are escaped by "\". A quote before "=", "+", "-" or "@" at the start
+// @Description  of a value is removed, exported values are protected from spreadsheet formulas by it.
+// @Description  CSV columns are type, question, answers, correct_answer, correct_answers, numeric_answer,
+// @Description  numeric_tolerance, info_link, answer_description, category, difficulty and tags, options
+// @Description  in correct_answers are separated by "|" too. Only question and info_link columns are required,
+// @Description  other columns are ignored, so exported files can be imported back.
+// @Description  Every row is reported: invalid quizzes and quizzes which questions are already saved or
+// @Description  are earlier in the batch are skipped. Questions are compared ignoring the case, spaces and
+// @Description  the final punctuation. Nothing is saved in the dry run.
+// @Tags         quiz
+// @Accept       json
+// @Accept       text/csv
+// @Produce      json
+// @Security     BearerAuth
+// @Param        dry_run  query  bool           false  "Only validate the batch"
+// @Param        body     body   []AddQuizRequest  true   "Quizzes, at most 1000"
+// @Success      200  {object}  ImportQuizzesResponse
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/quiz/import [post]
+func (q QuizHandler) ImportQuizzes(w http.ResponseWriter, r *http.Request) {
+	userID, err := q.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			http_errors.SendBadRequest(w, "dry_run is invalid")
+			return
+		}
+	}
+	contentType := "application/json"
+	if value := r.Header.Get("Content-Type"); value != "" {
+		if contentType, _, err = mime.ParseMediaType(value); err != nil {
+			http_errors.SendBadRequest(w, "content type is invalid")
+			return
+		}
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxQuizImportBytes)
+	var rows []model.QuizImportRow
+	switch contentType {
+	case "text/csv":
+		rows, err = q.readQuizImportCSV(body)
+	case "application/json":
+		rows, err = q.readQuizImportJSON(body)
+	default:
+		http_errors.SendBadRequest(w, "content type must be text/csv or application/json")
+		return
+	}
+	if err != nil {
+		http_errors.SendBadRequest(w, fmt.Sprintf("request body is invalid: %v", err))
+		logs.Error("failed to read quiz import", err)
+		return
+	}
+
+	report, err := q.QuizImporter.ImportQuizzes(r.Context(), userID, rows, dryRun)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to import quizzes", err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(newImportQuizzesResponse(report)); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+// ExportQuizzes godoc
+// @Summary      Export quizzes
+// @Description  Returns quizzes of any status found like in GET /game/quiz/list as a file, the newest quiz is first.
+// @Description  CSV has the header and the same columns as the import with id and status, JSON is an array.
+// @Description  Text values of CSV starting with "=", "+", "-" or "@" have a leading quote.
+// @Tags         quiz
+// @Produce      json
+// @Produce      text/csv
+// @Security     BearerAuth
+// @Param        format     query  string  false  "File format, json by default"  Enums(json, csv)
+// @Param        text       query  string  false  "Text in the question or the answer description"
+// @Param        category   query  string  false  "Quiz category"
+// @Param        status     query  string  false  "Quiz status"  Enums(draft, in_review, published, archived)
+// @Param        author_id  query  string  false  "Author id"
+// @Success      200  {array}   QuizDetails
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/quiz/export [get]
+func (q QuizHandler) ExportQuizzes(w http.ResponseWriter, r *http.Request) {
+	userID, err := q.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	values := r.URL.Query()
+	req := ExportQuizzesRequest{
+		Format:   values.Get("format"),
+		Text:     values.Get("text"),
+		Category: values.Get("category"),
+		Status:   values.Get("status"),
+		AuthorID: values.Get("author_id"),
+	}
+	if req.Format == "" {
+		req.Format = "json"
+	}
+	if validationErr(w, q.validate, req) {
+		return
+	}
+	search := model.QuizSearch{
+		Text:     req.Text,
+		Category: req.Category,
+		Status:   model.QuizStatus(req.Status),
+	}
+	if req.AuthorID != "" {
+		search.AuthorID = uuid.MustParse(req.AuthorID)
+	}
+	quizzes, err := q.QuizImporter.ExportQuizzes(r.Context(), userID, search)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to export quizzes", err)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"quizzes.%s\"", req.Format))
+	if req.Format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		if err = json.NewEncoder(w).Encode(newGetQuizListResponse(quizzes).Quizzes); err != nil {
+			logs.Error("failed to encode response", err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	writer := csv.NewWriter(w)
+	_ = writer.Write(quizCSVColumns)
+	for _, quiz := range quizzes {
+		_ = writer.Write(
+			[]string{
+				quiz.ID.String(), string(quiz.Status), string(quiz.TypeOrDefault()), quizCSVText(quiz.Question),
+				quizCSVText(joinQuizCSVList(quiz.Answers)), strconv.Itoa(quiz.CorrectAnswer),
+				joinQuizCSVOptions(quiz.CorrectAnswers), strconv.FormatFloat(quiz.NumericAnswer, 'f', -1, 64),
+				strconv.FormatFloat(quiz.NumericTolerance, 'f', -1, 64), quizCSVText(quiz.InfoLink),
+				quizCSVText(quiz.AnswerDescription), quizCSVText(quiz.Category), quizCSVText(quiz.Difficulty),
+				quizCSVText(joinQuizCSVList(quiz.Tags)),
+			},
+		)
+	}
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		logs.Error("failed to write csv", err)
+	}
+}
+
+// readQuizImportJSON reads the array of quizzes, quizzes which can not be decoded are reported as invalid rows.
+func (q QuizHandler) readQuizImportJSON(body io.Reader) ([]model.QuizImportRow, error) {
+	var rawQuizzes []json.RawMessage
+	if err := json.NewDecoder(body).Decode(&rawQuizzes); err != nil {
+		return nil, err
+	}
+	if len(rawQuizzes) > maxQuizImportRows {
+		return nil, errQuizImportTooManyRows
+	}
+	rows := make([]model.QuizImportRow, 0, len(rawQuizzes))
+	for i, rawQuiz := range rawQuizzes {
+		var req AddQuizRequest
+		if err := json.Unmarshal(rawQuiz, &req); err != nil {
+			rows = append(rows, model.QuizImportRow{Row: i + 1, Error: "quiz is not a valid json object"})
+			continue
+		}
+		rows = append(rows, q.newQuizImportRow(i+1, req))
+	}
+	return rows, nil
+}
+
+// readQuizImportCSV reads quizzes by the header, missing values of a row are empty.
+func (q QuizHandler) readQuizImportCSV(body io.Reader) ([]model.QuizImportRow, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, column := range quizCSVRequiredColumns {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("column %s is required", column)
+		}
+	}
+
+	rows := make([]model.QuizImportRow, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == maxQuizImportRows {
+			return nil, errQuizImportTooManyRows
+		}
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return parseQuizCSVText(strings.TrimSpace(record[i]))
+		}
+		rowNumber := len(rows) + 1
+		req := AddQuizRequest{
+			Type:              value("type"),
+			Question:          value("question"),
+			Answers:           splitQuizCSVList(value("answers")),
+			InfoLink:          value("info_link"),
+			AnswerDescription: value("answer_description"),
+			Category:          value("category"),
+			Difficulty:        value("difficulty"),
+			Tags:              splitQuizCSVList(value("tags")),
+		}
+		if err = parseQuizCSVNumbers(&req, value); err != nil {
+			rows = append(rows, model.QuizImportRow{Row: rowNumber, Error: err.Error()})
+			continue
+		}
+		rows = append(rows, q.newQuizImportRow(rowNumber, req))
+	}
+	return rows, nil
+}
+
+func (q QuizHandler) newQuizImportRow(rowNumber int, req AddQuizRequest) model.QuizImportRow {
+	if err := q.validate.Struct(req); err != nil {
+		return model.QuizImportRow{Row: rowNumber, Error: validationMessage(err)}
+	}
+	return model.QuizImportRow{
+		Row:  rowNumber,
+		Quiz: req.quiz(),
+	}
+}
+
+// parseQuizCSVNumbers parses numeric columns of the row, empty values are zero
+func parseQuizCSVNumbers(req *AddQuizRequest, value func(column string) string) error {
+	var err error
+	if column := value("correct_answer"); column != "" {
+		if req.CorrectAnswer, err = strconv.Atoi(column); err != nil {
+			return errors.New("correct_answer is not a number")
+		}
+	}
+	for _, option := range splitQuizCSVList(value("correct_answers")) {
+		correctAnswer, err := strconv.Atoi(option)
+		if err != nil {
+			return errors.New("correct_answers are not numbers")
+		}
+		req.CorrectAnswers = append(req.CorrectAnswers, correctAnswer)
+	}
+	if column := value("numeric_answer"); column != "" {
+		if req.NumericAnswer, err = strconv.ParseFloat(column, 64); err != nil {
+			return errors.New("numeric_answer is not a number")
+		}
+	}
+	if column := value("numeric_tolerance"); column != "" {
+		if req.NumericTolerance, err = strconv.ParseFloat(column, 64); err != nil {
+			return errors.New("numeric_tolerance is not a number")
+		}
+	}
+	return nil
+}
+
+// quizCSVText adds a quote before the value which spreadsheets would evaluate as a formula.
+func quizCSVText(value string) string {
+	if value != "" && strings.ContainsRune(quizCSVFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// parseQuizCSVText removes the quote added by quizCSVText.
+func parseQuizCSVText(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(quizCSVFormulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+func joinQuizCSVOptions(options []int) string {
+	items := make([]string, 0, len(options))
+	for _, option := range options {
+		items = append(items, strconv.Itoa(option))
+	}
+	return strings.Join(items, string(quizListSeparator))
+}
+
+// joinQuizCSVList joins the items escaping the separator in them.
+func joinQuizCSVList(items []string) string {
+	var list strings.Builder
+	for i, item := range items {
+		if i > 0 {
+			list.WriteRune(quizListSeparator)
+		}
+		for _, r := range item {
+			if r == quizListSeparator || r == quizListEscape {
+				list.WriteRune(quizListEscape)
+			}
+			list.WriteRune(r)
+		}
+	}
+	return list.String()
+}
+
+// splitQuizCSVList splits the list joined by joinQuizCSVList, an escaped rune is kept as it is.
+func splitQuizCSVList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	var (
+		items   []string
+		item    strings.Builder
+		escaped bool
+	)
+	for _, r := range value {
+		switch {
+		case escaped:
+			item.WriteRune(r)
+			escaped = false
+		case r == quizListEscape:
+			escaped = true
+		case r == quizListSeparator:
+			items = append(items, strings.TrimSpace(item.String()))
+			item.Reset()
+		default:
+			item.WriteRune(r)
+		}
+	}
+	return append(items, strings.TrimSpace(item.String()))
+}
+
+func newImportQuizzesResponse(report model.QuizImportReport) ImportQuizzesResponse {
+	resp := ImportQuizzesResponse{
+		DryRun:     report.DryRun,
+		Created:    report.Created,
+		Duplicates: report.Duplicates,
+		Invalid:    report.Invalid,
+		Results:    make([]QuizImportRowResult, 0, len(report.Results)),
+	}
+	for _, result := range report.Results {
+		rowResult := QuizImportRowResult{
+			Row:            result.Row,
+			Status:         string(result.Status),
+			DuplicateOfRow: result.DuplicateOfRow,
+			Error:          result.Error,
+		}
+		if result.QuizID != uuid.Nil {
+			rowResult.QuizID = &result.QuizID
+		}
+		if result.DuplicateOfQuizID != uuid.Nil {
+			rowResult.DuplicateOfQuizID = &result.DuplicateOfQuizID
+		}
+		resp.Results = append(resp.Results, rowResult)
+	}
+	return resp
+}
@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	http_errors "github.com/4units/mos-hack-game/back/pkg/http-errors"
+	logs "github.com/4units/mos-hack-game/back/pkg/logging"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+type QuizRoundProcessor interface {
+	StartRound(
+		ctx context.Context,
+		userID uuid.UUID,
+		filter model.QuizFilter,
+		questions int,
+	) (model.QuizRoundProgress, error)
+	GetRound(ctx context.Context, userID uuid.UUID) (model.QuizRoundProgress, error)
+	AnswerRound(
+		ctx context.Context,
+		userID, quizID uuid.UUID,
+		answer model.QuizAnswerValue,
+	) (model.QuizRoundProgress, error)
+}
+
+type QuizRoundHandlerDeps struct {
+	QuizRoundProcessor QuizRoundProcessor
+	UserIDExtractor    UserIDExtractor
+}
+
+type QuizRoundHandler struct {
+	QuizRoundHandlerDeps
+	validate *validator.Validate
+}
+
+func NewQuizRoundHandler(deps QuizRoundHandlerDeps) *QuizRoundHandler {
+	return &QuizRoundHandler{
+		QuizRoundHandlerDeps: deps,
+		validate:             validator.New(),
+	}
+}
+
+type StartQuizRoundRequest struct {
+	Category   string `json:"category" validate:"lte=32" example:"fraud"`
+	Difficulty string `json:"difficulty" validate:"omitempty,oneof=easy medium hard" example:"medium"`
+	// Questions is a count of questions, the count from the config is used when it is zero
+	Questions int `json:"questions" validate:"gte=0" example:"5"`
+}
+
+type AnswerQuizRoundRequest struct {
+	// QuizID is an id of the current question
+	QuizID uuid.UUID `json:"quiz_id" validate:"required"`
+	QuizAnswerFields
+}
+
+type QuizRoundQuestion struct {
+	// Number is a number of the question in the round starting from 1
+	Number     int       `json:"number" example:"2"`
+	ID         uuid.UUID `json:"id"`
+	Type       string    `json:"type" example:"single_choice"`
+	Question   string    `json:"question"`
+	Answers    []string  `json:"answers"`
+	Category   string    `json:"category" example:"fraud"`
+	Difficulty string    `json:"difficulty" example:"medium"`
+	// TimeLeft is a time in seconds to answer the question
+	TimeLeft float64 `json:"time_left" example:"17.5"`
+}
+
+type QuizRoundAnswerResult struct {
+	QuizID uuid.UUID `json:"quiz_id"`
+	QuizAnswerFields
+	Correct  bool `json:"correct"`
+	TimedOut bool `json:"timed_out"`
+	// Time is a time in seconds spent on the answer
+	Time   float64 `json:"time" example:"4.2"`
+	Reward int     `json:"reward" example:"65"`
+}
+
+type QuizRoundSummary struct {
+	// Score is a count of correct answers
+	Score     int `json:"score" example:"4"`
+	Questions int `json:"questions" example:"5"`
+	// Time is a time in seconds spent on all questions
+	Time         float64 `json:"time" example:"42.7"`
+	SoftCurrency int     `json:"soft_currency" example:"260"`
+}
+
+type QuizRoundResponse struct {
+	ID         uuid.UUID               `json:"id"`
+	Category   string                  `json:"category" example:"fraud"`
+	Difficulty string                  `json:"difficulty" example:"medium"`
+	Questions  int                     `json:"questions" example:"5"`
+	Answers    []QuizRoundAnswerResult `json:"answers"`
+	// Question is the current question, it is absent when the round is finished
+	Question *QuizRoundQuestion `json:"question,omitempty"`
+	// Summary is present only when the round is finished
+	Summary *QuizRoundSummary `json:"summary,omitempty"`
+}
+
+// StartQuizRound godoc
+// @Summary      Start quiz round
+// @Description  Starts a round of random quizzes answered one by one with a time limit per question.
+// @Description  The round has fewer questions when not enough quizzes match the category and the difficulty.
+// @Description  One round can be in progress, it is resumed via GET /game/quiz/round.
+// @Tags         quiz
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body  StartQuizRoundRequest  true  "Round params"
+// @Success      200  {object}  QuizRoundResponse
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      404  {object}  http_errors.ResponseError
+// @Failure      409  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/quiz/round [post]
+func (h *QuizRoundHandler) StartQuizRound(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	var req StartQuizRoundRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http_errors.SendBadRequest(w, "request body is invalid")
+		logs.Error("failed to decode the request", err)
+		return
+	}
+	if validationErr(w, h.validate, req) {
+		return
+	}
+	progress, err := h.QuizRoundProcessor.StartRound(
+		r.Context(), userID, model.QuizFilter{
+			Category:   req.Category,
+			Difficulty: req.Difficulty,
+		}, req.Questions,
+	)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to start quiz round", err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(newQuizRoundResponse(progress)); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+// GetQuizRound godoc
+// @Summary      Get quiz round in progress
+// @Description  Resumes the round after a reconnect, questions which time is over are counted as wrong answers.
+// @Description  When the time of the last question is over the finished round is returned once with the summary.
+// @Tags         quiz
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  QuizRoundResponse
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      404  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/quiz/round [get]
+func (h *QuizRoundHandler) GetQuizRound(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	progress, err := h.QuizRoundProcessor.GetRound(r.Context(), userID)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to get quiz round", err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(newQuizRoundResponse(progress)); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+// AnswerQuizRound godoc
+// @Summary      Answer current question of quiz round
+// @Description  Returns the next question or the summary with the reward after the last question.
+// @Description  The reward is a sum of rewards of correct answers by difficulty with a bonus for the speed.
+// @Description  Like single quizzes, only the first correct answer to a quiz is rewarded with the retry policy.
+// @Description  409 is returned when the time of the question is over, the round is reloaded via GET then.
+// @Tags         quiz
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body  AnswerQuizRoundRequest  true  "Answer data"
+// @Success      200  {object}  QuizRoundResponse
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      404  {object}  http_errors.ResponseError
+// @Failure      409  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/quiz/round/answer [post]
+func (h *QuizRoundHandler) AnswerQuizRound(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	var req AnswerQuizRoundRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http_errors.SendBadRequest(w, "request body is invalid")
+		logs.Error("failed to decode the request", err)
+		return
+	}
+	if validationErr(w, h.validate, req) {
+		return
+	}
+	progress, err := h.QuizRoundProcessor.AnswerRound(r.Context(), userID, req.QuizID, req.QuizAnswerValue())
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to answer quiz round", err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(newQuizRoundResponse(progress)); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+func newQuizRoundResponse(progress model.QuizRoundProgress) QuizRoundResponse {
+	round := progress.Round
+	resp := QuizRoundResponse{
+		ID:         round.ID,
+		Category:   round.Category,
+		Difficulty: round.Difficulty,
+		Questions:  len(round.QuizIDs),
+		Answers:    make([]QuizRoundAnswerResult, 0, len(round.Answers)),
+	}
+	for _, answer := range round.Answers {
+		resp.Answers = append(
+			resp.Answers, QuizRoundAnswerResult{
+				QuizID:           answer.QuizID,
+				QuizAnswerFields: newQuizAnswerFields(answer.Answer),
+				Correct:          answer.Correct,
+				TimedOut:         answer.TimedOut,
+				Time:             answer.Time.Seconds(),
+				Reward:           answer.Reward,
+			},
+		)
+	}
+	if progress.Question != nil {
+		resp.Question = &QuizRoundQuestion{
+			Number:     len(round.Answers) + 1,
+			ID:         progress.Question.ID,
+			Type:       string(progress.Question.TypeOrDefault()),
+			Question:   progress.Question.Question,
+			Answers:    progress.Question.Answers,
+			Category:   progress.Question.Category,
+			Difficulty: progress.Question.Difficulty,
+			TimeLeft:   progress.TimeLeft.Seconds(),
+		}
+	}
+	if round.Finished() {
+		resp.Summary = &QuizRoundSummary{
+			Score:        round.Score(),
+			Questions:    len(round.QuizIDs),
+			Time:         round.Time().Seconds(),
+			SoftCurrency: round.Reward,
+		}
+	}
+	return resp
+}
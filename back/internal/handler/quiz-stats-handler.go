@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	http_errors "github.com/4units/mos-hack-game/back/pkg/http-errors"
+	logs "github.com/4units/mos-hack-game/back/pkg/logging"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+type GetQuizStatsRequest struct {
+	Category   string `validate:"lte=32"`
+	Status     string `validate:"omitempty,oneof=draft in_review published archived"`
+	MinAnswers int    `validate:"gte=0"`
+	Sort       string `validate:"oneof=correct_rate average_time answers shown"`
+	Order      string `validate:"oneof=asc desc"`
+	Limit      int    `validate:"gt=0,lte=100"`
+	Offset     int    `validate:"gte=0"`
+}
+
+// QuizAnswerOptionStats are answers with the option, options of ordering quizzes are not counted
+type QuizAnswerOptionStats struct {
+	// Option is a number of the answer starting from 1
+	Option  int    `json:"option" example:"2"`
+	Answer  string `json:"answer"`
+	Correct bool   `json:"correct"`
+	Count   int    `json:"count" example:"37"`
+	// Rate is a share of answers with the option from 0 to 1
+	Rate float64 `json:"rate" example:"0.25"`
+}
+
+type QuizStatsResponse struct {
+	QuizID     uuid.UUID `json:"quiz_id"`
+	Question   string    `json:"question"`
+	Category   string    `json:"category" example:"deposits"`
+	Difficulty string    `json:"difficulty" example:"medium"`
+	Status     string    `json:"status" example:"published"`
+	// Shown is a count of times the quiz was given to players
+	Shown   int `json:"shown" example:"180"`
+	Answers int `json:"answers" example:"148"`
+	Correct int `json:"correct" example:"37"`
+	// CorrectRate is a share of correct answers from 0 to 1, it is absent for quizzes without answers
+	CorrectRate *float64 `json:"correct_rate,omitempty" example:"0.25"`
+	// AverageTime is an average time in seconds from showing the quiz to the answer
+	AverageTime *float64                `json:"average_time,omitempty" example:"12.4"`
+	Options     []QuizAnswerOptionStats `json:"options"`
+}
+
+type GetQuizStatsResponse struct {
+	Stats []QuizStatsResponse `json:"stats"`
+	// Total is a count of all found quizzes for the pagination
+	Total int `json:"total" example:"42"`
+}
+
+// GetQuizStats godoc
+// @Summary      Get quiz stats
+// @Description  Returns stats of answers to quizzes for quiz writers and admins to find questions which are
+// @Description  too hard or misleading. By default quizzes with the lowest correct rate are first, quizzes
+// @Description  without answers are the last ones when sorted by the correct rate or the average time.
+// @Description  Stats are sorted by correct_rate in asc order by default.
+// @Description  Only answers to single quizzes are counted, answers in rounds are not.
+// @Tags         quiz
+// @Produce      json
+// @Security     BearerAuth
+// @Param        category     query  string  false  "Quiz category"
+// @Param        status       query  string  false  "Quiz status"  Enums(draft, in_review, published, archived)
+// @Param        min_answers  query  int     false  "Min count of answers to the quiz"
+// @Param        sort         query  string  false  "Sort field"  Enums(correct_rate, average_time, answers, shown)
+// @Param        order        query  string  false  "Sort order"  Enums(asc, desc)
+// @Param        limit        query  int     false  "Count of quizzes on the page, 20 by default and 100 at most"
+// @Param        offset       query  int     false  "Count of skipped quizzes"
+// @Success      200  {object}  GetQuizStatsResponse
+// @Failure      400  {object}  http_errors.ResponseError
+// @Failure      401  {object}  http_errors.ResponseError
+// @Failure      403  {object}  http_errors.ResponseError
+// @Failure      500  {object}  http_errors.ResponseError
+// @Router       /game/quiz/stats [get]
+func (q QuizHandler) GetQuizStats(w http.ResponseWriter, r *http.Request) {
+	userID, err := q.UserIDExtractor.GetVerifiedUserIDFromRequest(r)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to extract user id", err)
+		return
+	}
+	values := r.URL.Query()
+	req := GetQuizStatsRequest{
+		Category: values.Get("category"),
+		Status:   values.Get("status"),
+		Sort:     values.Get("sort"),
+		Order:    values.Get("order"),
+	}
+	if req.Sort == "" {
+		req.Sort = string(model.QuizStatsSortCorrectRate)
+	}
+	if req.Order == "" {
+		req.Order = "asc"
+	}
+	if req.MinAnswers, err = intQueryParam(values.Get("min_answers"), 0); err != nil {
+		http_errors.SendBadRequest(w, "min_answers is invalid")
+		return
+	}
+	if req.Limit, err = intQueryParam(values.Get("limit"), defaultQuizListLimit); err != nil {
+		http_errors.SendBadRequest(w, "limit is invalid")
+		return
+	}
+	if req.Offset, err = intQueryParam(values.Get("offset"), 0); err != nil {
+		http_errors.SendBadRequest(w, "offset is invalid")
+		return
+	}
+	if validationErr(w, q.validate, req) {
+		return
+	}
+	page, err := q.QuizCatalogProvider.GetQuizStats(
+		r.Context(), userID, model.QuizStatsSearch{
+			Category:   req.Category,
+			Status:     model.QuizStatus(req.Status),
+			MinAnswers: req.MinAnswers,
+			Sort:       model.QuizStatsSort(req.Sort),
+			Desc:       req.Order == "desc",
+			Limit:      req.Limit,
+			Offset:     req.Offset,
+		},
+	)
+	if err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to get quiz stats", err)
+		return
+	}
+	resp := GetQuizStatsResponse{
+		Stats: make([]QuizStatsResponse, 0, len(page.Stats)),
+		Total: page.Total,
+	}
+	for _, stats := range page.Stats {
+		resp.Stats = append(resp.Stats, newQuizStatsResponse(stats))
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		http_errors.SendWrapped(w, err)
+		logs.Error("failed to encode response", err)
+	}
+}
+
+func newQuizStatsResponse(stats model.QuizStats) QuizStatsResponse {
+	resp := QuizStatsResponse{
+		QuizID:     stats.Quiz.ID,
+		Question:   stats.Quiz.Question,
+		Category:   stats.Quiz.Category,
+		Difficulty: stats.Quiz.Difficulty,
+		Status:     string(stats.Quiz.Status),
+		Shown:      stats.Shown,
+		Answers:    stats.Answers,
+		Correct:    stats.Correct,
+		Options:    make([]QuizAnswerOptionStats, 0, len(stats.AnswerCounts)),
+	}
+	if stats.Answers > 0 {
+		correctRate := stats.CorrectRate()
+		resp.CorrectRate = &correctRate
+	}
+	if stats.AverageTime > 0 {
+		averageTime := stats.AverageTime.Seconds()
+		resp.AverageTime = &averageTime
+	}
+	for i, count := range stats.AnswerCounts {
+		option := QuizAnswerOptionStats{
+			Option:  i + 1,
+			Correct: stats.Quiz.IsCorrectOption(i + 1),
+			Count:   count,
+		}
+		if i < len(stats.Quiz.Answers) {
+			option.Answer = stats.Quiz.Answers[i]
+		}
+		if stats.Answers > 0 {
+			option.Rate = float64(count) / float64(stats.Answers)
+		}
+		resp.Options = append(resp.Options, option)
+	}
+	return resp
+}
@@ -12,30 +12,34 @@ import (
 
 func validationErr(w http.ResponseWriter, validate *validator.Validate, req interface{}) bool {
 	if err := validate.Struct(req); err != nil {
-		var validatorErr validator.ValidationErrors
-		if errors.As(err, &validatorErr) {
-			errs := make([]string, len(validatorErr))
-			for i, fieldError := range validatorErr {
-				if fieldError.Param() != "" {
-					errs[i] = fmt.Sprintf(
-						"failed to validate field '%s', because of tag '%s:%s'",
-						strings.ToLower(fieldError.Field()),
-						fieldError.Tag(), fieldError.Param(),
-					)
-				} else {
-					errs[i] = fmt.Sprintf(
-						"failed to validate field '%s', because of tag '%s'", strings.ToLower(fieldError.Field()),
-						fieldError.Tag(),
-					)
-				}
-				break
-			}
-			http_errors.SendBadRequest(w, strings.Join(errs, ";"))
-		} else {
-			http_errors.SendBadRequest(w, "failed to validate request")
-		}
+		http_errors.SendBadRequest(w, validationMessage(err))
 		logs.Error("failed to validate the request", err)
 		return true
 	}
 	return false
 }
+
+// validationMessage describes the first invalid field of the validation error
+func validationMessage(err error) string {
+	var validatorErr validator.ValidationErrors
+	if !errors.As(err, &validatorErr) {
+		return "failed to validate request"
+	}
+	errs := make([]string, len(validatorErr))
+	for i, fieldError := range validatorErr {
+		if fieldError.Param() != "" {
+			errs[i] = fmt.Sprintf(
+				"failed to validate field '%s', because of tag '%s:%s'",
+				strings.ToLower(fieldError.Field()),
+				fieldError.Tag(), fieldError.Param(),
+			)
+		} else {
+			errs[i] = fmt.Sprintf(
+				"failed to validate field '%s', because of tag '%s'", strings.ToLower(fieldError.Field()),
+				fieldError.Tag(),
+			)
+		}
+		break
+	}
+	return strings.Join(errs, ";")
+}
@@ -0,0 +1,267 @@
+// Package line_solver searches ways through line game levels. The search is ported from the level-generator
+// answer search and additionally can build new levels around a random way.
+package line_solver
+
+import (
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"math/rand/v2"
+	"slices"
+)
+
+const (
+	// maxSolveSteps limits the search of an answer for an existing level
+	maxSolveSteps = 2_000_000
+	// maxGenerateSteps limits the search of a way for one attempt of level generation
+	maxGenerateSteps = 20_000
+	// maxGenerateAttempts is a count of blockers placements tried before giving up
+	maxGenerateAttempts = 100
+)
+
+var (
+	ErrAnswerNotFound     = errors.New("line game level answer not found")
+	ErrGenerationFailed   = errors.New("failed to generate line game level")
+	ErrInvalidLevelParams = errors.New("invalid line game level params")
+)
+
+// moves are the answer numbers with their vectors: 0 - up, 1 - right, 2 - down, 3 - left
+var moves = [4]model.LineGameLevelCell{
+	{X: 0, Y: -1},
+	{X: 1, Y: 0},
+	{X: 0, Y: 1},
+	{X: -1, Y: 0},
+}
+
+const (
+	answerFinish = 4
+	answerBlock  = 5
+)
+
+type search struct {
+	fieldSize int
+	blocked   [][]bool
+	visited   [][]bool
+	orders    map[model.LineGameLevelCell]int
+	ordersLen int
+	// end is nil when the way can finish in any cell
+	end      *model.LineGameLevelCell
+	way      []model.LineGameLevelCell
+	cells    int
+	steps    int
+	maxSteps int
+	// rng shuffles moves, the search is deterministic without it
+	rng *rand.Rand
+}
+
+func newSearch(fieldSize int, blockers []model.LineGameLevelCell, maxSteps int) *search {
+	s := &search{
+		fieldSize: fieldSize,
+		blocked:   make([][]bool, fieldSize),
+		visited:   make([][]bool, fieldSize),
+		orders:    make(map[model.LineGameLevelCell]int),
+		cells:     fieldSize*fieldSize - len(blockers),
+		maxSteps:  maxSteps,
+	}
+	for y := range fieldSize {
+		s.blocked[y] = make([]bool, fieldSize)
+		s.visited[y] = make([]bool, fieldSize)
+	}
+	for _, cell := range blockers {
+		s.blocked[cell.Y][cell.X] = true
+	}
+	return s
+}
+
+// Solve returns an answer of the level. The stored answer of the level is not used.
+func Solve(level model.LineGameLevel) ([][]int, error) {
+	way, err := SolveFrom(level, []model.LineGameLevelCell{level.Start})
+	if err != nil {
+		return nil, err
+	}
+	return wayToAnswer(level.FieldSize, way, level.Blockers), nil
+}
+
+// SolveFrom returns the whole way through the level continuing the path. The path must be checked
+// by model.LineGameLevel.CheckPath before.
+func SolveFrom(level model.LineGameLevel, path []model.LineGameLevelCell) ([]model.LineGameLevelCell, error) {
+	way, _, err := SolveFromWithin(level, path, maxSolveSteps)
+	return way, err
+}
+
+// SolveFromWithin is SolveFrom limited by the count of search steps, it returns the count of made steps too.
+func SolveFromWithin(
+	level model.LineGameLevel,
+	path []model.LineGameLevelCell,
+	maxSteps int,
+) ([]model.LineGameLevelCell, int, error) {
+	s := newSearch(level.FieldSize, level.Blockers, maxSteps)
+	for i, cell := range level.Order {
+		s.orders[cell] = i
+	}
+	s.ordersLen = len(level.Order)
+	s.end = &level.End
+	if !s.runFrom(path) {
+		return nil, min(s.steps, maxSteps), ErrAnswerNotFound
+	}
+	return s.way, s.steps, nil
+}
+
+// Generate returns a level with the given params. The same seed always gives the same level.
+func Generate(seed uint64, fieldSize, orders, blockers int) (model.LineGameLevel, error) {
+	if fieldSize <= 1 || orders < 0 || blockers < 0 || orders+blockers+2 > fieldSize*fieldSize {
+		return model.LineGameLevel{}, fmt.Errorf(
+			"field size %v, orders %v, blockers %v: %w", fieldSize, orders, blockers, ErrInvalidLevelParams,
+		)
+	}
+	rng := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+	cells := make([]model.LineGameLevelCell, 0, fieldSize*fieldSize)
+	for y := range fieldSize {
+		for x := range fieldSize {
+			cells = append(cells, model.LineGameLevelCell{X: x, Y: y})
+		}
+	}
+	for range maxGenerateAttempts {
+		rng.Shuffle(len(cells), func(i, j int) { cells[i], cells[j] = cells[j], cells[i] })
+		levelBlockers := slices.Clone(cells[:blockers])
+		s := newSearch(fieldSize, levelBlockers, maxGenerateSteps)
+		s.rng = rng
+		if !s.run(cells[blockers]) {
+			continue
+		}
+		orderNums := rng.Perm(len(s.way) - 2)[:orders]
+		slices.Sort(orderNums)
+		levelOrders := make([]model.LineGameLevelCell, 0, orders)
+		for _, num := range orderNums {
+			levelOrders = append(levelOrders, s.way[num+1])
+		}
+		return model.LineGameLevel{
+			FieldSize: fieldSize,
+			Start:     s.way[0],
+			End:       s.way[len(s.way)-1],
+			Order:     levelOrders,
+			Blockers:  levelBlockers,
+			Answer:    wayToAnswer(fieldSize, s.way, levelBlockers),
+		}, nil
+	}
+	return model.LineGameLevel{}, fmt.Errorf(
+		"field size %v, orders %v, blockers %v: %w", fieldSize, orders, blockers, ErrGenerationFailed,
+	)
+}
+
+func (s *search) run(start model.LineGameLevelCell) bool {
+	return s.runFrom([]model.LineGameLevelCell{start})
+}
+
+func (s *search) runFrom(path []model.LineGameLevelCell) bool {
+	if len(path) == 0 {
+		return false
+	}
+	nextOrder := 0
+	for _, cell := range path {
+		if !s.free(cell) {
+			return false
+		}
+		if num, ok := s.orders[cell]; ok {
+			if num != nextOrder {
+				return false
+			}
+			nextOrder++
+		}
+		s.visit(cell)
+	}
+	return s.next(path[len(path)-1], nextOrder)
+}
+
+func (s *search) next(cur model.LineGameLevelCell, nextOrder int) bool {
+	s.steps++
+	if s.steps > s.maxSteps {
+		return false
+	}
+	if len(s.way) == s.cells {
+		return nextOrder == s.ordersLen && (s.end == nil || *s.end == cur)
+	}
+	if s.end != nil && *s.end == cur {
+		return false
+	}
+	candidates := make([]model.LineGameLevelCell, 0, len(moves))
+	for _, move := range moves {
+		cell := model.LineGameLevelCell{X: cur.X + move.X, Y: cur.Y + move.Y}
+		if !s.free(cell) {
+			continue
+		}
+		if num, ok := s.orders[cell]; ok && num != nextOrder {
+			continue
+		}
+		candidates = append(candidates, cell)
+	}
+	if s.rng != nil {
+		s.rng.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	}
+	// cells with less free neighbours go first, so the way does not leave unreachable cells behind
+	slices.SortStableFunc(
+		candidates, func(a, b model.LineGameLevelCell) int {
+			return s.freeNeighbours(a) - s.freeNeighbours(b)
+		},
+	)
+	for _, cell := range candidates {
+		cellNextOrder := nextOrder
+		if _, ok := s.orders[cell]; ok {
+			cellNextOrder++
+		}
+		s.visit(cell)
+		if s.next(cell, cellNextOrder) {
+			return true
+		}
+		s.unvisit()
+		if s.steps > s.maxSteps {
+			return false
+		}
+	}
+	return false
+}
+
+func (s *search) free(cell model.LineGameLevelCell) bool {
+	return cell.X >= 0 && cell.X < s.fieldSize && cell.Y >= 0 && cell.Y < s.fieldSize &&
+		!s.blocked[cell.Y][cell.X] && !s.visited[cell.Y][cell.X]
+}
+
+func (s *search) freeNeighbours(cell model.LineGameLevelCell) int {
+	count := 0
+	for _, move := range moves {
+		if s.free(model.LineGameLevelCell{X: cell.X + move.X, Y: cell.Y + move.Y}) {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *search) visit(cell model.LineGameLevelCell) {
+	s.visited[cell.Y][cell.X] = true
+	s.way = append(s.way, cell)
+}
+
+func (s *search) unvisit() {
+	cell := s.way[len(s.way)-1]
+	s.visited[cell.Y][cell.X] = false
+	s.way = s.way[:len(s.way)-1]
+}
+
+func wayToAnswer(fieldSize int, way, blockers []model.LineGameLevelCell) [][]int {
+	answer := make([][]int, fieldSize)
+	for y := range answer {
+		answer[y] = make([]int, fieldSize)
+	}
+	for i := 0; i < len(way)-1; i++ {
+		cur, next := way[i], way[i+1]
+		answer[cur.Y][cur.X] = slices.Index(
+			moves[:], model.LineGameLevelCell{X: next.X - cur.X, Y: next.Y - cur.Y},
+		)
+	}
+	end := way[len(way)-1]
+	answer[end.Y][end.X] = answerFinish
+	for _, cell := range blockers {
+		answer[cell.Y][cell.X] = answerBlock
+	}
+	return answer
+}
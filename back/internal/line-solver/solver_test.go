@@ -0,0 +1,72 @@
+package line_solver
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		fieldSize int
+		orders    int
+		blockers  int
+	}{
+		{3, 0, 0},
+		{4, 2, 1},
+		{5, 3, 2},
+		{6, 4, 3},
+		{7, 5, 4},
+	}
+	for _, test := range tests {
+		for seed := range uint64(20) {
+			level, err := Generate(seed, test.fieldSize, test.orders, test.blockers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(level.Order) != test.orders || len(level.Blockers) != test.blockers {
+				t.Errorf(
+					"Wrong level params. Expected %v orders and %v blockers, got %v and %v\n",
+					test.orders, test.blockers, len(level.Order), len(level.Blockers),
+				)
+			}
+			if err = level.Validate(); err != nil {
+				t.Errorf("Wrong generated level. Expected valid level, got %v\n", err)
+			}
+			same, err := Generate(seed, test.fieldSize, test.orders, test.blockers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(level, same) {
+				t.Errorf("Wrong generated level. Expected the same level for seed %v\n", seed)
+			}
+		}
+	}
+}
+
+func TestGenerate_InvalidParams(t *testing.T) {
+	if _, err := Generate(1, 3, 4, 4); !errors.Is(err, ErrInvalidLevelParams) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", ErrInvalidLevelParams, err)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	for seed := range uint64(20) {
+		level, err := Generate(seed, 6, 3, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		storedAnswer := level.Answer
+		level.Answer = nil
+		answer, err := Solve(level)
+		if err != nil {
+			t.Fatalf("Wrong solve result for seed %v. Expected answer, got %v\n", seed, err)
+		}
+		if err = level.CheckAnswer(answer); err != nil {
+			t.Errorf("Wrong answer for seed %v. Expected correct answer, got %v\n", seed, err)
+		}
+		if err = level.CheckAnswer(storedAnswer); err != nil {
+			t.Errorf("Wrong stored answer for seed %v. Expected correct answer, got %v\n", seed, err)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package model
+
+// BoosterStack is a count of boosters of one kind in the user inventory
+type BoosterStack struct {
+	BoosterID string
+	Effect    string
+	Count     int
+}
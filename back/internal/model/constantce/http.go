@@ -1,5 +1,9 @@
 package constantce
 
 var (
-	RequestVariableQuizID = "quiz-id"
+	RequestVariableQuizID            = "quiz-id"
+	RequestVariableLineGameGroupCode = "group-code"
+	RequestVariableBoosterID         = "booster-id"
+	RequestVariableBundleID          = "bundle-id"
+	RequestVariableUserID            = "user-id"
 )
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+)
+
+type UserEnergy struct {
+	Value int
+	// UpdatedAt is a start of regeneration of the next unit
+	UpdatedAt time.Time
+	// PaidPassedCount is a passed count of the last level attempt paid with energy, it is -1 when nothing is paid
+	PaidPassedCount int
+}
+
+// Regenerate adds units regenerated till now. A full energy starts regeneration from now after spending.
+func (e UserEnergy) Regenerate(maxValue int, interval time.Duration, now time.Time) UserEnergy {
+	if e.Value >= maxValue || interval <= 0 {
+		e.Value = max(e.Value, maxValue)
+		e.UpdatedAt = now
+		return e
+	}
+	units := int(now.Sub(e.UpdatedAt) / interval)
+	if units <= 0 {
+		return e
+	}
+	if e.Value+units >= maxValue {
+		e.Value = maxValue
+		e.UpdatedAt = now
+		return e
+	}
+	e.Value += units
+	e.UpdatedAt = e.UpdatedAt.Add(time.Duration(units) * interval)
+	return e
+}
+
+// NextRefillAt returns the time of regeneration of the next unit, it is zero when the energy is full.
+func (e UserEnergy) NextRefillAt(maxValue int, interval time.Duration) time.Time {
+	if e.Value >= maxValue || interval <= 0 {
+		return time.Time{}
+	}
+	return e.UpdatedAt.Add(interval)
+}
+
+// EnergyStatus is the energy of the user shown to the client
+type EnergyStatus struct {
+	Enabled   bool
+	Value     int
+	Max       int
+	LevelCost int
+	// NextRefillAt is zero when the energy is full
+	NextRefillAt time.Time
+}
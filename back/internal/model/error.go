@@ -12,7 +12,11 @@ var (
 	ErrLineGameNotExistsLevelInStorage = errors.New("line game level does not exist in storage")
 	ErrLineGameNoFileWithLevelGroups   = errors.New("line game has no file with level groups")
 	ErrLineGameGroupsIsFinished        = errors.New("line game groups is finished")
-	ErrLineGameFieldSizeNotEqual       = http_errors.NewSame(
+	ErrLineGameGroupDraftDoesNotExist  = http_errors.NewSame(
+		"line game group draft does not exist",
+		http.StatusNotFound,
+	)
+	ErrLineGameFieldSizeNotEqual = http_errors.NewSame(
 		"answers size not equal to level size",
 		http.StatusBadRequest,
 	)
@@ -29,6 +33,50 @@ var (
 		"answers out of borders", "out of borders", http.StatusBadRequest,
 	)
 
-	ErrBalanceNotExists    = http_errors.NewSame("balance does not exist", http.StatusNotFound)
-	ErrUserRoleHasNoAccess = http_errors.NewSame("has no access", http.StatusForbidden)
+	ErrLineGameLevelNotStarted = http_errors.NewSame("level is not started", http.StatusConflict)
+
+	ErrUserHasNoDailyCompletion      = errors.New("user has no daily level completion")
+	ErrLineGameDailyAlreadyCompleted = http_errors.NewSame("daily level is already completed", http.StatusConflict)
+	ErrLineGameDailyDisabled         = http_errors.NewSame("daily level is disabled", http.StatusNotFound)
+
+	ErrLineGameBoosterNotExists     = http_errors.NewSame("booster does not exist", http.StatusNotFound)
+	ErrLineGameBoosterLimitExceeded = http_errors.NewSame("booster limit for the level is exceeded", http.StatusConflict)
+	ErrLineGameBoosterNotAvailable  = http_errors.NewSame("booster is not available for the level", http.StatusConflict)
+	ErrBoosterBundleNotExists       = http_errors.NewSame("booster bundle does not exist", http.StatusNotFound)
+	ErrBoosterNotInInventory        = http_errors.NewSame("booster is not in the inventory", http.StatusConflict)
+
+	ErrQuizStatusChanged = http_errors.NewSame("quiz status was changed by another request", http.StatusConflict)
+	ErrQuizChanged       = http_errors.NewSame("quiz was changed by another request", http.StatusConflict)
+
+	ErrQuizTypeUnknown          = http_errors.NewSame("quiz type is unknown", http.StatusBadRequest)
+	ErrQuizTooFewAnswers        = http_errors.NewSame("quiz has too few answers for its type", http.StatusBadRequest)
+	ErrQuizTrueFalseAnswers     = http_errors.NewSame("true/false quiz must have 2 answers", http.StatusBadRequest)
+	ErrQuizCorrectAnswerInvalid = http_errors.NewSame(
+		"correct answer must be a number of one of the answers", http.StatusBadRequest,
+	)
+	ErrQuizCorrectAnswersInvalid = http_errors.NewSame(
+		"correct answers must be different numbers of the answers", http.StatusBadRequest,
+	)
+	ErrQuizOrderingInvalid = http_errors.NewSame(
+		"correct order must have every answer once and differ from the order of the answers", http.StatusBadRequest,
+	)
+	ErrQuizNumericAnswerInvalid = http_errors.NewSame(
+		"numeric answer must be a finite number with a non-negative tolerance", http.StatusBadRequest,
+	)
+	ErrQuizAnswerKeyOfOtherType = http_errors.NewSame(
+		"quiz has answers of another quiz type", http.StatusBadRequest,
+	)
+
+	ErrQuizRoundNotExists  = http_errors.NewSame("quiz round is not in progress", http.StatusNotFound)
+	ErrQuizRoundInProgress = http_errors.NewSame("quiz round is already in progress", http.StatusConflict)
+	ErrQuizRoundChanged    = http_errors.NewSame("quiz round was changed by another request", http.StatusConflict)
+
+	ErrLeaderboardHasNoUserScore = errors.New("leaderboard has no user score")
+
+	ErrBalanceNotExists      = http_errors.NewSame("balance does not exist", http.StatusNotFound)
+	ErrNotEnoughSoftCurrency = http_errors.NewSame("not enough soft currency", http.StatusForbidden)
+	ErrEnergyNotExists       = errors.New("energy does not exist")
+	ErrNotEnoughEnergy       = http_errors.NewSame("not enough energy", http.StatusForbidden)
+	ErrEnergyIsFull          = http_errors.NewSame("energy is full", http.StatusConflict)
+	ErrUserRoleHasNoAccess   = http_errors.NewSame("has no access", http.StatusForbidden)
 )
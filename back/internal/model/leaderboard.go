@@ -0,0 +1,123 @@
+package model
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+type LeaderboardMetric string
+
+const (
+	// LeaderboardLevelTime is the fastest time in milliseconds of a campaign level
+	LeaderboardLevelTime LeaderboardMetric = "level_time"
+	// LeaderboardDailyTime is the fastest time in milliseconds of the daily level
+	LeaderboardDailyTime LeaderboardMetric = "daily_time"
+	// LeaderboardStars is a sum of the best stars of completed line game levels
+	LeaderboardStars LeaderboardMetric = "stars"
+	// LeaderboardQuiz is a count of correct quiz answers
+	LeaderboardQuiz LeaderboardMetric = "quiz"
+)
+
+type LeaderboardPeriod string
+
+const (
+	LeaderboardGlobal LeaderboardPeriod = "global"
+	LeaderboardWeekly LeaderboardPeriod = "weekly"
+	LeaderboardDaily  LeaderboardPeriod = "daily"
+)
+
+// LeaderboardKey identifies a board with scores of users
+type LeaderboardKey struct {
+	// Board is a metric name with its params, for example "level_time:3_0_0:1"
+	Board string
+	// Period is "all" for global boards, a week like "2025-W10" or a date like "2025-03-01"
+	Period string
+	// LowerIsBetter is true for boards of times
+	LowerIsBetter bool
+}
+
+type LeaderboardEntry struct {
+	Rank        int
+	UserID      uuid.UUID
+	DisplayName string
+	Score       int
+}
+
+type Leaderboard struct {
+	Key     LeaderboardKey
+	Entries []LeaderboardEntry
+	// User is an entry of the requesting user, it is nil when the user has no score on the board
+	User *LeaderboardEntry
+}
+
+type LeaderboardQuery struct {
+	Metric LeaderboardMetric
+	Period LeaderboardPeriod
+	// GroupCode and LevelNum are used for the level time metric
+	GroupCode LineGameLevelGroupCode
+	LevelNum  int
+	Limit     int
+}
+
+func NewLevelTimeLeaderboardKey(
+	groupCode LineGameLevelGroupCode,
+	levelNum int,
+	period LeaderboardPeriod,
+	now time.Time,
+) LeaderboardKey {
+	return LeaderboardKey{
+		Board:         fmt.Sprintf("%s:%s:%v", LeaderboardLevelTime, groupCode, levelNum),
+		Period:        leaderboardPeriodKey(period, now),
+		LowerIsBetter: true,
+	}
+}
+
+func NewDailyTimeLeaderboardKey(day time.Time) LeaderboardKey {
+	return LeaderboardKey{
+		Board:         string(LeaderboardDailyTime),
+		Period:        day.Format(time.DateOnly),
+		LowerIsBetter: true,
+	}
+}
+
+func NewLeaderboardKey(metric LeaderboardMetric, period LeaderboardPeriod, now time.Time) LeaderboardKey {
+	return LeaderboardKey{
+		Board:  string(metric),
+		Period: leaderboardPeriodKey(period, now),
+	}
+}
+
+func leaderboardPeriodKey(period LeaderboardPeriod, now time.Time) string {
+	switch period {
+	case LeaderboardWeekly:
+		year, week := now.UTC().ISOWeek()
+		return fmt.Sprintf("%v-W%02d", year, week)
+	case LeaderboardDaily:
+		return now.UTC().Format(time.DateOnly)
+	default:
+		return "all"
+	}
+}
+
+// RankLeaderboardEntries sets ranks of sorted entries, entries with the same score have the same rank.
+func RankLeaderboardEntries(entries []LeaderboardEntry) {
+	for i := range entries {
+		if i > 0 && entries[i].Score == entries[i-1].Score {
+			entries[i].Rank = entries[i-1].Rank
+			continue
+		}
+		entries[i].Rank = i + 1
+	}
+}
+
+// LeaderboardDisplayName hides the most part of the email, users without email are named by their id.
+func LeaderboardDisplayName(userID uuid.UUID, email string) string {
+	name, _, _ := strings.Cut(email, "@")
+	if name == "" {
+		return "Player #" + strings.ToUpper(userID.String()[:6])
+	}
+	runes := []rune(name)
+	return string(runes[:min(len(runes), 3)]) + "***"
+}
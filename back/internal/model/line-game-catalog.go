@@ -0,0 +1,148 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+)
+
+// LineGameCatalog is an immutable index of level groups in the order they are played.
+// It must not be modified after creation, so it is safe for concurrent reads.
+type LineGameCatalog struct {
+	groups     []LineGameLevelGroup
+	groupIndex map[LineGameLevelGroupCode]int
+}
+
+// NewLineGameCatalog validates every group and builds the index keeping the groups order.
+func NewLineGameCatalog(groups []LineGameLevelGroup) (*LineGameCatalog, error) {
+	catalog := &LineGameCatalog{
+		groups:     make([]LineGameLevelGroup, 0, len(groups)),
+		groupIndex: make(map[LineGameLevelGroupCode]int, len(groups)),
+	}
+	for _, group := range groups {
+		code := group.GetCode()
+		if _, ok := catalog.groupIndex[code]; ok {
+			return nil, fmt.Errorf("group %s is duplicated", code)
+		}
+		if err := group.Validate(); err != nil {
+			return nil, fmt.Errorf("group %s is invalid: %w", code, err)
+		}
+		catalog.groupIndex[code] = len(catalog.groups)
+		catalog.groups = append(catalog.groups, group)
+	}
+	return catalog, nil
+}
+
+func (c *LineGameCatalog) Group(code LineGameLevelGroupCode) (LineGameLevelGroup, bool) {
+	i, ok := c.groupIndex[code]
+	if !ok {
+		return LineGameLevelGroup{}, false
+	}
+	return c.groups[i], true
+}
+
+func (c *LineGameCatalog) Groups() []LineGameLevelGroup {
+	return slices.Clone(c.groups)
+}
+
+func (c *LineGameCatalog) Level(code LineGameLevelGroupCode, num int) (LineGameLevel, error) {
+	group, ok := c.Group(code)
+	if !ok || num < 0 || num >= len(group.Levels) {
+		return LineGameLevel{}, fmt.Errorf("group %s level %v: %w", code, num, ErrLineGameNotExistsLevelInStorage)
+	}
+	return group.Levels[num], nil
+}
+
+// NextLevel returns the level after the current one. When the current level is not in
+// the catalog, the next level is counted from its closest existing level.
+func (c *LineGameCatalog) NextLevel(code LineGameLevelGroupCode, num int) (LineGameLevelGroupCode, int, error) {
+	group, ok := c.Group(code)
+	if !ok || num >= len(group.Levels) {
+		code, num, ok = c.ClosestLevel(code, num)
+		if !ok {
+			return "", 0, ErrLineGameNoFileWithLevelGroups
+		}
+		group, _ = c.Group(code)
+	}
+	if num+1 < len(group.Levels) {
+		return code, num + 1, nil
+	}
+	nextCode, ok := c.NextGroupCode(code)
+	if !ok {
+		return "", 0, ErrLineGameGroupsIsFinished
+	}
+	return nextCode, 0, nil
+}
+
+// NextGroupLevel returns the first level of the group after the group with the provided code. When the group
+// is not in the catalog, the group after its closest existing group is used.
+func (c *LineGameCatalog) NextGroupLevel(code LineGameLevelGroupCode) (LineGameLevelGroupCode, int, error) {
+	if _, ok := c.Group(code); !ok {
+		code, _, ok = c.ClosestLevel(code, 0)
+		if !ok {
+			return "", 0, ErrLineGameNoFileWithLevelGroups
+		}
+	}
+	nextCode, ok := c.NextGroupCode(code)
+	if !ok {
+		return "", 0, ErrLineGameGroupsIsFinished
+	}
+	return nextCode, 0, nil
+}
+
+func (c *LineGameCatalog) StartGroupCode() (LineGameLevelGroupCode, bool) {
+	if len(c.groups) == 0 {
+		return "", false
+	}
+	return c.groups[0].GetCode(), true
+}
+
+// NextGroupCode returns the code of the group played after the group with the provided code.
+func (c *LineGameCatalog) NextGroupCode(code LineGameLevelGroupCode) (LineGameLevelGroupCode, bool) {
+	i, ok := c.groupIndex[code]
+	if !ok || i+1 >= len(c.groups) {
+		return "", false
+	}
+	return c.groups[i+1].GetCode(), true
+}
+
+// ClosestLevel maps a level which may no longer exist to the closest existing one:
+//   - the nearest lower level of the same group when the group became shorter;
+//   - the first level of the hardest group with equal or lower field size, orders and blockers when the group
+//     was removed, where field size is compared first, then orders and then blockers, levels of other groups
+//     were not played by the user;
+//   - the first level of the start group when there is no such group.
+func (c *LineGameCatalog) ClosestLevel(code LineGameLevelGroupCode, num int) (LineGameLevelGroupCode, int, bool) {
+	if group, ok := c.Group(code); ok {
+		return code, max(min(num, len(group.Levels)-1), 0), true
+	}
+	if group, ok := c.closestLowGroup(code); ok {
+		return group.GetCode(), 0, true
+	}
+	startCode, ok := c.StartGroupCode()
+	return startCode, 0, ok
+}
+
+func (c *LineGameCatalog) closestLowGroup(code LineGameLevelGroupCode) (LineGameLevelGroup, bool) {
+	fieldSize, orders, blockers, err := code.ParseLineGameLevelGroupID()
+	if err != nil {
+		return LineGameLevelGroup{}, false
+	}
+	var (
+		closest LineGameLevelGroup
+		found   bool
+	)
+	for _, group := range c.groups {
+		if group.FieldSize > fieldSize || group.Order > orders || group.Blockers > blockers {
+			continue
+		}
+		if !found || closest.Less(group) {
+			closest = group
+			found = true
+		}
+	}
+	return closest, found
+}
+
+func (c *LineGameCatalog) GroupsCount() int {
+	return len(c.groups)
+}
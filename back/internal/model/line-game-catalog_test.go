@@ -0,0 +1,145 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLineGameCatalog_ClosestLevel(t *testing.T) {
+	catalog, err := NewLineGameCatalog(
+		[]LineGameLevelGroup{
+			snakeGroup(3, 0, 0, 2),
+			snakeGroup(3, 1, 0, 1),
+			snakeGroup(4, 0, 2, 1),
+			snakeGroup(5, 2, 0, 3),
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name         string
+		code         LineGameLevelGroupCode
+		num          int
+		expectedCode LineGameLevelGroupCode
+		expectedNum  int
+	}{
+		{"existing level", "5_2_0", 1, "5_2_0", 1},
+		{"group became shorter", "3_0_0", 5, "3_0_0", 1},
+		{"removed group with lower field size", "4_1_2", 3, "4_0_2", 0},
+		{"removed group with lower orders", "5_3_0", 2, "5_2_0", 0},
+		{"removed group with lower blockers", "5_2_1", 4, "5_2_0", 0},
+		{"removed group with more levels", "6_3_3", 40, "5_2_0", 0},
+		{"no group with lower difficulty", "2_0_0", 1, "3_0_0", 0},
+		{"invalid group code", "unknown", 1, "3_0_0", 0},
+	}
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				code, num, ok := catalog.ClosestLevel(test.code, test.num)
+				if !ok || code != test.expectedCode || num != test.expectedNum {
+					t.Errorf(
+						"Wrong closest level. Expected %v %v, got %v %v (found %v)\n",
+						test.expectedCode, test.expectedNum, code, num, ok,
+					)
+				}
+			},
+		)
+	}
+}
+
+func TestLineGameCatalog_ClosestLevel_Empty(t *testing.T) {
+	catalog, err := NewLineGameCatalog(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := catalog.ClosestLevel("3_0_0", 0); ok {
+		t.Errorf("Wrong result. Expected no closest level in empty catalog\n")
+	}
+}
+
+func TestLineGameCatalog_NextGroupLevel(t *testing.T) {
+	catalog, err := NewLineGameCatalog(
+		[]LineGameLevelGroup{
+			snakeGroup(3, 0, 0, 2),
+			snakeGroup(4, 0, 0, 2),
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, num, err := catalog.NextGroupLevel("3_0_0")
+	if err != nil || code != "4_0_0" || num != 0 {
+		t.Errorf("Wrong next group level. Expected 4_0_0 0, got %v %v (%v)\n", code, num, err)
+	}
+	code, num, err = catalog.NextGroupLevel("3_1_0")
+	if err != nil || code != "4_0_0" || num != 0 {
+		t.Errorf("Wrong next group level of removed group. Expected 4_0_0 0, got %v %v (%v)\n", code, num, err)
+	}
+	if _, _, err = catalog.NextGroupLevel("4_0_0"); !errors.Is(err, ErrLineGameGroupsIsFinished) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", ErrLineGameGroupsIsFinished, err)
+	}
+}
+
+func TestLineGameLevel_CheckAnswer_Loop(t *testing.T) {
+	level := snakeGroup(3, 0, 0, 1).Levels[0]
+	level.Answer[0][1] = 3
+
+	if err := level.CheckAnswer(level.Answer); err != ErrLineGameAnswerHasLoop {
+		t.Errorf("Wrong error. Expected %v, got %v\n", ErrLineGameAnswerHasLoop, err)
+	}
+}
+
+// snakeGroup returns a group of the same levels passing rows left to right and right to left in turn.
+// Orders are the first cells after the start and blockers are the last cells of the snake.
+func snakeGroup(fieldSize, orders, blockers, levelsCount int) LineGameLevelGroup {
+	snake := make([]LineGameLevelCell, 0, fieldSize*fieldSize)
+	for y := 0; y < fieldSize; y++ {
+		for i := 0; i < fieldSize; i++ {
+			x := i
+			if y%2 == 1 {
+				x = fieldSize - 1 - i
+			}
+			snake = append(snake, LineGameLevelCell{X: x, Y: y})
+		}
+	}
+	path := snake[:len(snake)-blockers]
+	answer := make([][]int, fieldSize)
+	for y := range answer {
+		answer[y] = make([]int, fieldSize)
+	}
+	for i := 0; i < len(path)-1; i++ {
+		cur, next := path[i], path[i+1]
+		switch {
+		case next.X > cur.X:
+			answer[cur.Y][cur.X] = 1
+		case next.X < cur.X:
+			answer[cur.Y][cur.X] = 3
+		default:
+			answer[cur.Y][cur.X] = 2
+		}
+	}
+	for _, cell := range snake[len(path):] {
+		answer[cell.Y][cell.X] = 5
+	}
+	end := path[len(path)-1]
+	answer[end.Y][end.X] = 4
+	group := LineGameLevelGroup{
+		FieldSize: fieldSize,
+		Order:     orders,
+		Blockers:  blockers,
+	}
+	for range levelsCount {
+		group.Levels = append(
+			group.Levels, LineGameLevel{
+				FieldSize: fieldSize,
+				Start:     path[0],
+				End:       end,
+				Order:     path[1 : 1+orders],
+				Blockers:  snake[len(path):],
+				Answer:    answer,
+			},
+		)
+	}
+	return group
+}
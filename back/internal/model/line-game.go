@@ -3,12 +3,17 @@ package model
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type LineGameLevelGroupCode string
 
+// LineGameEndlessGroupCode is a group of generated levels played after the last level group
+const LineGameEndlessGroupCode LineGameLevelGroupCode = "endless"
+
 type LineGameLevel struct {
 	PassedCount int
 	FieldSize   int
@@ -19,6 +24,17 @@ type LineGameLevel struct {
 	// Answer is answers double slice with number-side quality:
 	// 0 - up, 1 - right, 2 - down, 3 - left, 4 - finish, 5 - block
 	Answer [][]int
+	// Endless is true for generated levels of the endless mode
+	Endless bool
+	// Hints are bought by the user for the current attempt of the level
+	Hints []LineGameHint
+}
+
+// LineGameLevelAttempt identifies a play of the level, the passed count is different for every play
+type LineGameLevelAttempt struct {
+	GroupCode   LineGameLevelGroupCode
+	LevelNum    int
+	PassedCount int
 }
 
 type LineGameLevelGroup struct {
@@ -28,13 +44,340 @@ type LineGameLevelGroup struct {
 	Levels    []LineGameLevel
 }
 
+type LineGameLevelGroupStatus string
+
+const (
+	LineGameLevelGroupDraft     LineGameLevelGroupStatus = "draft"
+	LineGameLevelGroupPublished LineGameLevelGroupStatus = "published"
+)
+
+type LineGameLevelValidation struct {
+	LevelNum int
+	Err      error
+}
+
+type LineGameLevelGroupInfo struct {
+	Code        LineGameLevelGroupCode
+	LevelsCount int
+	Status      LineGameLevelGroupStatus
+	UpdatedAt   time.Time
+}
+
 type LineGameLevelCell struct {
 	X int
 	Y int
 }
 
+type LineGameHintType string
+
+const (
+	LineGameHintSteps      LineGameHintType = "steps"
+	LineGameHintCheckpoint LineGameHintType = "checkpoint"
+	LineGameHintFull       LineGameHintType = "full"
+)
+
+type LineGameHint struct {
+	Type LineGameHintType
+	// KeptCells is a count of cells of the user path continued by the hint
+	KeptCells int
+	// Cells continue the kept part of the user path, the full hint has the whole way
+	Cells []LineGameLevelCell
+	Price int
+}
+
+// Reveals returns true when both hints reveal the same cells after the same kept part of the path
+func (h LineGameHint) Reveals(other LineGameHint) bool {
+	return h.Type == other.Type && h.KeptCells == other.KeptCells && slices.Equal(h.Cells, other.Cells)
+}
+
+// LineGameBoosterUse is a result of using a booster from the inventory for the current level attempt
+type LineGameBoosterUse struct {
+	BoosterID string
+	Effect    string
+	// InventoryLeft is a count of boosters of the kind left in the user inventory
+	InventoryLeft int
+	// UsesLeft is a count of uses left for the level attempt, it is -1 when the booster is unlimited
+	UsesLeft int
+	// Hint is revealed by the reveal checkpoint booster
+	Hint *LineGameHint
+}
+
+// LineGameDaily is the level of the day shared by all users
+type LineGameDaily struct {
+	Day       time.Time
+	Level     LineGameLevel
+	Completed bool
+	// Streak is a count of consecutive days with completed daily level, it is zero when a day is missed
+	Streak int
+}
+
+type LineGameDailyCompletion struct {
+	Day            time.Time
+	TimeSinceStart time.Duration
+	Reward         int
+	Streak         int
+	// RewardBoosters are put into the user inventory, they are not saved with the completion
+	RewardBoosters map[string]int
+}
+
+// LineGameProgression is a decision of the progression policy about the level after the completed one
+type LineGameProgression string
+
+const (
+	// LineGameProgressionNext moves to the next level of the group or to the next group
+	LineGameProgressionNext LineGameProgression = "next"
+	// LineGameProgressionSkipGroup moves to the first level of the next group
+	LineGameProgressionSkipGroup LineGameProgression = "skip_group"
+	// LineGameProgressionRepeatGroup moves to the first level of the same group after its last level
+	LineGameProgressionRepeatGroup LineGameProgression = "repeat_group"
+)
+
+// LineGameLevelResult is a completion of a level saved for the progression policy and analysis
+type LineGameLevelResult struct {
+	GroupCode LineGameLevelGroupCode
+	LevelNum  int
+	// Cells is a count of cells of the way through the level
+	Cells          int
+	TimeSinceStart time.Duration
+	HintsCount     int
+	Policy         string
+	Progression    LineGameProgression
+}
+
+// CellTime returns seconds spent on one cell of the way.
+func (r LineGameLevelResult) CellTime() float64 {
+	if r.Cells == 0 {
+		return 0
+	}
+	return r.TimeSinceStart.Seconds() / float64(r.Cells)
+}
+
 type LineGameReward struct {
 	SoftCurrency int
+	// Boosters are counts of boosters by their ids put into the user inventory
+	Boosters map[string]int
+	// Stars is a rating of the completion from 1 to 3 by the reward tier
+	Stars int
+	// TimeSinceStart is the completion time counted by the server
+	TimeSinceStart time.Duration
+	// NextStar is nil when the completion has the best rating
+	NextStar *LineGameStarThreshold
+}
+
+// LineGameStarThreshold is a time to complete a level faster than for the count of stars
+type LineGameStarThreshold struct {
+	Stars   int
+	MaxTime time.Duration
+}
+
+// LineGameLevelStars is the best rating of a level shown on the level map
+type LineGameLevelStars struct {
+	GroupCode LineGameLevelGroupCode
+	LevelNum  int
+	Stars     int
+	BestTime  time.Duration
+}
+
+func (group *LineGameLevelGroup) GetCode() LineGameLevelGroupCode {
+	return GetLevelGroupID(group.FieldSize, group.Order, group.Blockers)
+}
+
+// Less reports whether the group is easier than the other one comparing field size, orders and blockers in turn.
+func (group *LineGameLevelGroup) Less(other LineGameLevelGroup) bool {
+	if group.FieldSize != other.FieldSize {
+		return group.FieldSize < other.FieldSize
+	}
+	if group.Order != other.Order {
+		return group.Order < other.Order
+	}
+	return group.Blockers < other.Blockers
+}
+
+func (group *LineGameLevelGroup) Validate() error {
+	if err := group.ValidateParams(); err != nil {
+		return err
+	}
+	for i := range group.Levels {
+		if err := group.ValidateLevel(i); err != nil {
+			return fmt.Errorf("level %v is invalid: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (group *LineGameLevelGroup) ValidateParams() error {
+	if group.FieldSize <= 1 {
+		return fmt.Errorf("invalid field size %v", group.FieldSize)
+	}
+	if group.Order < 0 || group.Blockers < 0 {
+		return errors.New("orders and blockers count can not be negative")
+	}
+	if len(group.Levels) == 0 {
+		return errors.New("group has no levels")
+	}
+	return nil
+}
+
+func (group *LineGameLevelGroup) ValidateLevel(i int) error {
+	level := &group.Levels[i]
+	if level.FieldSize != group.FieldSize || len(level.Order) != group.Order ||
+		len(level.Blockers) != group.Blockers {
+		return errors.New("level does not match group parameters")
+	}
+	return level.Validate()
+}
+
+func (level *LineGameLevel) Validate() error {
+	cells := make([]LineGameLevelCell, 0, len(level.Order)+len(level.Blockers)+2)
+	cells = append(cells, level.Start, level.End)
+	cells = append(cells, level.Order...)
+	cells = append(cells, level.Blockers...)
+	used := make(map[LineGameLevelCell]struct{}, len(cells))
+	for _, cell := range cells {
+		if !level.InBorders(cell) {
+			return fmt.Errorf("cell (%v, %v) is out of borders", cell.X, cell.Y)
+		}
+		if _, ok := used[cell]; ok {
+			return fmt.Errorf("cell (%v, %v) is used twice", cell.X, cell.Y)
+		}
+		used[cell] = struct{}{}
+	}
+	if err := level.CheckAnswer(level.Answer); err != nil {
+		return fmt.Errorf("stored answer is incorrect: %w", err)
+	}
+	return nil
+}
+
+func (level *LineGameLevel) InBorders(cell LineGameLevelCell) bool {
+	return cell.X >= 0 && cell.X < level.FieldSize && cell.Y >= 0 && cell.Y < level.FieldSize
+}
+
+// CheckAnswer verifies that the answer leads from the start cell to the end cell
+// visiting the order cells in sequence and every cell that is not a blocker.
+func (level *LineGameLevel) CheckAnswer(answer [][]int) error {
+	if level.FieldSize != len(answer) {
+		return ErrLineGameFieldSizeNotEqual
+	}
+	for _, row := range answer {
+		if len(row) != level.FieldSize {
+			return ErrLineGameFieldSizeNotEqual
+		}
+	}
+	verifiedOrders := 0
+	x, y := level.Start.X, level.Start.Y
+	checkedCells := 1
+	maxCells := level.FieldSize * level.FieldSize
+
+Loop:
+	for x != level.End.X || y != level.End.Y {
+		numberToMove := answer[y][x]
+		switch numberToMove {
+		case 0:
+			y--
+		case 1:
+			x++
+		case 2:
+			y++
+		case 3:
+			x--
+		default:
+			break Loop
+		}
+		checkedCells++
+		if checkedCells > maxCells {
+			return ErrLineGameAnswerHasLoop
+		}
+		if verifiedOrders+1 <= len(level.Order) {
+			orderCell := level.Order[verifiedOrders]
+			if orderCell.X == x && orderCell.Y == y {
+				verifiedOrders++
+			}
+		}
+		if x < 0 || x >= level.FieldSize || y < 0 || y >= level.FieldSize {
+			return ErrLineGameAnswerOutOfBorders
+		}
+	}
+	if verifiedOrders != len(level.Order) {
+		return ErrLineGameAnswerOrderIncorrect
+	}
+	expectedCellsInWay := level.FieldSize*level.FieldSize - len(level.Blockers)
+	if checkedCells != expectedCellsInWay {
+		return fmt.Errorf(
+			"cells in way %v, expected %v error: %w", checkedCells, expectedCellsInWay,
+			ErrLineGameAnswerCellsWayIncorrect,
+		)
+	}
+	return nil
+}
+
+// CheckPath verifies that the path is an unfinished part of a way starting at the start cell.
+func (level *LineGameLevel) CheckPath(path []LineGameLevelCell) error {
+	if len(path) == 0 || path[0] != level.Start {
+		return errors.New("path does not begin at the start cell")
+	}
+	blockers := make(map[LineGameLevelCell]struct{}, len(level.Blockers))
+	for _, cell := range level.Blockers {
+		blockers[cell] = struct{}{}
+	}
+	visited := make(map[LineGameLevelCell]struct{}, len(path))
+	verifiedOrders := 0
+	for i, cell := range path {
+		if !level.InBorders(cell) {
+			return fmt.Errorf("cell (%v, %v) is out of borders", cell.X, cell.Y)
+		}
+		if _, ok := blockers[cell]; ok {
+			return fmt.Errorf("cell (%v, %v) is a blocker", cell.X, cell.Y)
+		}
+		if _, ok := visited[cell]; ok {
+			return fmt.Errorf("cell (%v, %v) is visited twice", cell.X, cell.Y)
+		}
+		visited[cell] = struct{}{}
+		if i > 0 && abs(cell.X-path[i-1].X)+abs(cell.Y-path[i-1].Y) != 1 {
+			return fmt.Errorf("cell (%v, %v) is not next to the previous one", cell.X, cell.Y)
+		}
+		if cell == level.End {
+			return errors.New("path is already finished")
+		}
+		if slices.Contains(level.Order, cell) {
+			if verifiedOrders == len(level.Order) || level.Order[verifiedOrders] != cell {
+				return fmt.Errorf("order cell (%v, %v) is visited out of turn", cell.X, cell.Y)
+			}
+			verifiedOrders++
+		}
+	}
+	return nil
+}
+
+// AnswerWay returns cells of the stored answer from the start cell to the end cell.
+func (level *LineGameLevel) AnswerWay() ([]LineGameLevelCell, error) {
+	if err := level.CheckAnswer(level.Answer); err != nil {
+		return nil, err
+	}
+	way := make([]LineGameLevelCell, 0, level.FieldSize*level.FieldSize-len(level.Blockers))
+	cell := level.Start
+	for {
+		way = append(way, cell)
+		if cell == level.End {
+			return way, nil
+		}
+		switch level.Answer[cell.Y][cell.X] {
+		case 0:
+			cell.Y--
+		case 1:
+			cell.X++
+		case 2:
+			cell.Y++
+		case 3:
+			cell.X--
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
 }
 
 func (level *LineGameLevel) GetLevelGroupCode() LineGameLevelGroupCode {
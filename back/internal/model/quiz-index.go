@@ -0,0 +1,86 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"math/rand/v2"
+)
+
+// quizIndexPickAttempts is a count of random picks before the scan of the whole bucket
+const quizIndexPickAttempts = 32
+
+// QuizIndexEntry is a quiz in the index of the random selection
+type QuizIndexEntry struct {
+	ID         uuid.UUID
+	Category   string
+	Difficulty string
+}
+
+// QuizIndex is an immutable list of quizzes for the random selection without loading their contents.
+// Entries are grouped in buckets by every kind of the filter, so filtered picks do not scan other quizzes.
+// It must not be modified after creation, so it is safe for concurrent reads.
+type QuizIndex struct {
+	entries []QuizIndexEntry
+	buckets map[QuizFilter][]QuizIndexEntry
+}
+
+func NewQuizIndex(entries []QuizIndexEntry) *QuizIndex {
+	index := &QuizIndex{
+		entries: entries,
+		buckets: make(map[QuizFilter][]QuizIndexEntry),
+	}
+	for _, entry := range entries {
+		for _, filter := range []QuizFilter{
+			{Category: entry.Category},
+			{Difficulty: entry.Difficulty},
+			{Category: entry.Category, Difficulty: entry.Difficulty},
+		} {
+			index.buckets[filter] = append(index.buckets[filter], entry)
+		}
+	}
+	return index
+}
+
+func (i *QuizIndex) Len() int {
+	return len(i.entries)
+}
+
+// Pick returns a random entry matching the filter which is not excluded, excluded entries are picked only
+// when all matching entries are excluded. It returns false when no entries match the filter.
+// Random picks take constant time while the most entries are not excluded, otherwise the bucket is scanned once.
+func (i *QuizIndex) Pick(filter QuizFilter, excluded map[uuid.UUID]bool) (QuizIndexEntry, bool) {
+	entries := i.entries
+	if filter != (QuizFilter{}) {
+		entries = i.buckets[filter]
+	}
+	if len(entries) == 0 {
+		return QuizIndexEntry{}, false
+	}
+	for range quizIndexPickAttempts {
+		entry := entries[rand.IntN(len(entries))]
+		if !excluded[entry.ID] {
+			return entry, true
+		}
+	}
+	// reservoir sampling keeps a uniform choice among both kinds of entries in one pass
+	var (
+		picked, pickedExcluded QuizIndexEntry
+		count, countExcluded   int
+	)
+	for _, entry := range entries {
+		if excluded[entry.ID] {
+			countExcluded++
+			if rand.IntN(countExcluded) == 0 {
+				pickedExcluded = entry
+			}
+			continue
+		}
+		count++
+		if rand.IntN(count) == 0 {
+			picked = entry
+		}
+	}
+	if count > 0 {
+		return picked, true
+	}
+	return pickedExcluded, countExcluded > 0
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"testing"
+)
+
+var testQuizDifficulties = []string{"easy", "medium", "hard"}
+
+func newTestQuizIndex(size int) (*QuizIndex, []QuizIndexEntry) {
+	entries := make([]QuizIndexEntry, size)
+	for i := range entries {
+		entries[i] = QuizIndexEntry{
+			ID:         uuid.New(),
+			Category:   fmt.Sprintf("category-%v", i%10),
+			Difficulty: testQuizDifficulties[i%len(testQuizDifficulties)],
+		}
+	}
+	return NewQuizIndex(entries), entries
+}
+
+func TestQuizIndex_Pick(t *testing.T) {
+	index, entries := newTestQuizIndex(100)
+	excluded := make(map[uuid.UUID]bool, len(entries))
+	for _, entry := range entries[:99] {
+		excluded[entry.ID] = true
+	}
+	for range 10 {
+		entry, ok := index.Pick(QuizFilter{}, excluded)
+		if !ok || entry.ID != entries[99].ID {
+			t.Fatalf("Wrong entry. Expected the only not excluded %v, got %v\n", entries[99].ID, entry.ID)
+		}
+	}
+
+	excluded[entries[99].ID] = true
+	if _, ok := index.Pick(QuizFilter{}, excluded); !ok {
+		t.Errorf("Wrong result. Expected an excluded entry when all entries are excluded\n")
+	}
+
+	// entries 1, 31, 61 and 91 are in category 1 with medium difficulty
+	filter := QuizFilter{Category: "category-1", Difficulty: "medium"}
+	for range 10 {
+		entry, ok := index.Pick(filter, nil)
+		if !ok || entry.Category != filter.Category || entry.Difficulty != filter.Difficulty {
+			t.Fatalf("Wrong entry. Expected an entry matching %v, got %v\n", filter, entry)
+		}
+	}
+	if entry, ok := index.Pick(QuizFilter{Difficulty: "hard"}, nil); !ok || entry.Difficulty != "hard" {
+		t.Errorf("Wrong entry. Expected a hard entry, got %v\n", entry)
+	}
+	if _, ok := index.Pick(QuizFilter{Category: "unknown"}, nil); ok {
+		t.Errorf("Wrong result. Expected no entry when nothing matches\n")
+	}
+	if _, ok := NewQuizIndex(nil).Pick(QuizFilter{}, nil); ok {
+		t.Errorf("Wrong result. Expected no entry in the empty index\n")
+	}
+}
+
+// BenchmarkQuizIndex_Pick shows that the pick time does not depend on the index size
+// while the user has answered a small part of quizzes.
+func BenchmarkQuizIndex_Pick(b *testing.B) {
+	for _, size := range []int{1_000, 10_000, 100_000} {
+		index, entries := newTestQuizIndex(size)
+		excluded := make(map[uuid.UUID]bool, 500)
+		for _, entry := range entries[:500] {
+			excluded[entry.ID] = true
+		}
+		for _, filter := range []QuizFilter{{}, {Category: "category-3", Difficulty: "hard"}} {
+			b.Run(
+				fmt.Sprintf("quizzes=%v/filter=%v", size, filter != QuizFilter{}), func(b *testing.B) {
+					for range b.N {
+						if _, ok := index.Pick(filter, excluded); !ok {
+							b.Fatal("no entry picked")
+						}
+					}
+				},
+			)
+		}
+	}
+}
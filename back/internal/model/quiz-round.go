@@ -0,0 +1,77 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// QuizRound is a series of quizzes answered one by one, the user has one round in progress at most
+type QuizRound struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Category   string
+	Difficulty string
+	QuizIDs    []uuid.UUID
+	// Answers are in the order of quizzes, the current question is the first one without an answer
+	Answers []QuizRoundAnswer
+	// QuestionTimeLimit is copied from the config on the start, so config changes do not affect the round
+	QuestionTimeLimit time.Duration
+	// QuestionStartedAt is a time when the time of the current question started
+	QuestionStartedAt time.Time
+	StartedAt         time.Time
+	// FinishedAt is zero while the round is in progress
+	FinishedAt time.Time
+	// Reward is a sum of rewards of answers which is added to the balance when the round is finished
+	Reward int
+}
+
+type QuizRoundAnswer struct {
+	QuizID uuid.UUID
+	Answer QuizAnswerValue
+	// TimedOut is true when the time of the question was over before the answer
+	TimedOut bool
+	Correct  bool
+	Time     time.Duration
+	Reward   int
+}
+
+// QuizRoundProgress is a round with the current question shown to the user
+type QuizRoundProgress struct {
+	Round QuizRound
+	// Question is nil when the round is finished
+	Question *Quiz
+	// TimeLeft is a time to answer the current question
+	TimeLeft time.Duration
+}
+
+func (r QuizRound) Finished() bool {
+	return len(r.Answers) >= len(r.QuizIDs)
+}
+
+// CurrentQuizID returns the id of the quiz which is not answered yet, it is nil when the round is finished.
+func (r QuizRound) CurrentQuizID() uuid.UUID {
+	if r.Finished() {
+		return uuid.Nil
+	}
+	return r.QuizIDs[len(r.Answers)]
+}
+
+// Score returns a count of correct answers.
+func (r QuizRound) Score() int {
+	score := 0
+	for _, answer := range r.Answers {
+		if answer.Correct {
+			score++
+		}
+	}
+	return score
+}
+
+// Time returns a sum of times spent on the answered questions.
+func (r QuizRound) Time() time.Duration {
+	var total time.Duration
+	for _, answer := range r.Answers {
+		total += answer.Time
+	}
+	return total
+}
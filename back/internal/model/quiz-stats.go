@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+)
+
+// QuizStats are aggregated answers of players to the quiz from the answer history
+type QuizStats struct {
+	// Quiz has contents and the status of the quiz
+	Quiz Quiz
+	// Shown is a count of times the quiz was given to players
+	Shown   int
+	Answers int
+	Correct int
+	// AnswerCounts are counts of answers by options, the first count is of the first option
+	AnswerCounts []int
+	// AverageTime is an average time from showing the quiz to the answer, it is zero when no answer has the time
+	AverageTime time.Duration
+}
+
+// CorrectRate is a share of correct answers from 0 to 1, it is zero for quizzes without answers
+func (s QuizStats) CorrectRate() float64 {
+	if s.Answers == 0 {
+		return 0
+	}
+	return float64(s.Correct) / float64(s.Answers)
+}
+
+// QuizStatsSort is a field which quiz stats are sorted by
+type QuizStatsSort string
+
+const (
+	QuizStatsSortCorrectRate QuizStatsSort = "correct_rate"
+	QuizStatsSortAverageTime QuizStatsSort = "average_time"
+	QuizStatsSortAnswers     QuizStatsSort = "answers"
+	QuizStatsSortShown       QuizStatsSort = "shown"
+)
+
+// QuizStatsSearch filters and sorts quiz stats, empty fields are not checked
+type QuizStatsSearch struct {
+	Category string
+	Status   QuizStatus
+	// MinAnswers hides quizzes with fewer answers which stats say little
+	MinAnswers int
+	Sort       QuizStatsSort
+	Desc       bool
+	Limit      int
+	Offset     int
+}
+
+// QuizStatsPage is a page of quiz stats, Total is a count of all found quizzes
+type QuizStatsPage struct {
+	Stats []QuizStats
+	Total int
+}
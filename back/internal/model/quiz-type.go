@@ -0,0 +1,142 @@
+package model
+
+import (
+	"math"
+	"slices"
+)
+
+// QuizType defines how the quiz is answered and checked
+type QuizType string
+
+const (
+	// QuizTypeSingleChoice quizzes have one correct option in CorrectAnswer, quizzes saved before types have it
+	QuizTypeSingleChoice QuizType = "single_choice"
+	// QuizTypeMultipleChoice quizzes have all correct options in CorrectAnswers, all of them must be chosen
+	QuizTypeMultipleChoice QuizType = "multiple_choice"
+	// QuizTypeTrueFalse quizzes are single choice quizzes with the true and false options
+	QuizTypeTrueFalse QuizType = "true_false"
+	// QuizTypeNumeric quizzes have no options, the answer must differ from NumericAnswer by NumericTolerance at most
+	QuizTypeNumeric QuizType = "numeric"
+	// QuizTypeOrdering quizzes have options in the saved order and the correct order of them in CorrectAnswers
+	QuizTypeOrdering QuizType = "ordering"
+)
+
+// QuizAnswerValue is an answer of the player to the quiz of any type, options are numbered from 1
+type QuizAnswerValue struct {
+	// Option is the chosen option of single choice and true/false quizzes
+	Option int
+	// Options are chosen options of multiple choice quizzes or all options in the order of ordering quizzes
+	Options []int
+	// Number is an answer to numeric quizzes, it is nil for quizzes of other types
+	Number *float64
+}
+
+// TypeOrDefault returns the type of the quiz, quizzes without the type are single choice ones
+func (q Quiz) TypeOrDefault() QuizType {
+	if q.Type == "" {
+		return QuizTypeSingleChoice
+	}
+	return q.Type
+}
+
+// ValidateAnswerKey checks that options and correct answers of the quiz match its type
+// and fields of other types are empty.
+func (q Quiz) ValidateAnswerKey() error {
+	switch q.TypeOrDefault() {
+	case QuizTypeSingleChoice, QuizTypeTrueFalse:
+		if q.Type == QuizTypeTrueFalse && len(q.Answers) != 2 {
+			return ErrQuizTrueFalseAnswers
+		}
+		if len(q.Answers) == 0 {
+			return ErrQuizTooFewAnswers
+		}
+		if q.CorrectAnswer < 1 || q.CorrectAnswer > len(q.Answers) {
+			return ErrQuizCorrectAnswerInvalid
+		}
+		if len(q.CorrectAnswers) > 0 || q.NumericAnswer != 0 || q.NumericTolerance != 0 {
+			return ErrQuizAnswerKeyOfOtherType
+		}
+	case QuizTypeMultipleChoice, QuizTypeOrdering:
+		if len(q.Answers) < 2 {
+			return ErrQuizTooFewAnswers
+		}
+		if q.CorrectAnswer != 0 || q.NumericAnswer != 0 || q.NumericTolerance != 0 {
+			return ErrQuizAnswerKeyOfOtherType
+		}
+		if !quizOptionsDistinct(q.CorrectAnswers, len(q.Answers)) || len(q.CorrectAnswers) == 0 {
+			return ErrQuizCorrectAnswersInvalid
+		}
+		if q.Type == QuizTypeMultipleChoice {
+			return nil
+		}
+		// options are shown in the saved order, so it must not be the correct one
+		if len(q.CorrectAnswers) != len(q.Answers) || slices.IsSorted(q.CorrectAnswers) {
+			return ErrQuizOrderingInvalid
+		}
+	case QuizTypeNumeric:
+		if len(q.Answers) > 0 || q.CorrectAnswer != 0 || len(q.CorrectAnswers) > 0 {
+			return ErrQuizAnswerKeyOfOtherType
+		}
+		if q.NumericTolerance < 0 || math.IsNaN(q.NumericAnswer) || math.IsInf(q.NumericAnswer, 0) {
+			return ErrQuizNumericAnswerInvalid
+		}
+	default:
+		return ErrQuizTypeUnknown
+	}
+	return nil
+}
+
+// CheckAnswer returns true when the answer is correct for the type of the quiz
+func (q Quiz) CheckAnswer(answer QuizAnswerValue) bool {
+	switch q.TypeOrDefault() {
+	case QuizTypeSingleChoice, QuizTypeTrueFalse:
+		return answer.Option == q.CorrectAnswer
+	case QuizTypeMultipleChoice:
+		if !quizOptionsDistinct(answer.Options, len(q.Answers)) || len(answer.Options) != len(q.CorrectAnswers) {
+			return false
+		}
+		for _, option := range answer.Options {
+			if !slices.Contains(q.CorrectAnswers, option) {
+				return false
+			}
+		}
+		return true
+	case QuizTypeNumeric:
+		return answer.Number != nil && math.Abs(*answer.Number-q.NumericAnswer) <= q.NumericTolerance
+	case QuizTypeOrdering:
+		return slices.Equal(answer.Options, q.CorrectAnswers)
+	default:
+		return false
+	}
+}
+
+// IsCorrectOption returns true when the option is correct in single choice, true/false and
+// multiple choice quizzes, options of other types are not correct on their own
+func (q Quiz) IsCorrectOption(option int) bool {
+	switch q.TypeOrDefault() {
+	case QuizTypeSingleChoice, QuizTypeTrueFalse:
+		return option == q.CorrectAnswer
+	case QuizTypeMultipleChoice:
+		return slices.Contains(q.CorrectAnswers, option)
+	default:
+		return false
+	}
+}
+
+// WithoutAnswerKey returns the quiz without the correct answer of any type
+func (q Quiz) WithoutAnswerKey() Quiz {
+	q.CorrectAnswer, q.CorrectAnswers, q.NumericAnswer, q.NumericTolerance = 0, nil, 0, 0
+	return q
+}
+
+// quizOptionsDistinct returns true when options are different numbers from 1 to the count of options
+func quizOptionsDistinct(options []int, count int) bool {
+	seen := make(map[int]bool, len(options))
+	for _, option := range options {
+		if option < 1 || option > count || seen[option] {
+			return false
+		}
+		seen[option] = true
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQuiz_ValidateAnswerKey(t *testing.T) {
+	options := []string{"a", "b", "c"}
+	tests := []struct {
+		name     string
+		quiz     Quiz
+		expected error
+	}{
+		{"single choice without type", Quiz{Answers: options, CorrectAnswer: 2}, nil},
+		{"single choice out of range", Quiz{Answers: options, CorrectAnswer: 4}, ErrQuizCorrectAnswerInvalid},
+		{
+			"true false with three options",
+			Quiz{Type: QuizTypeTrueFalse, Answers: options, CorrectAnswer: 1},
+			ErrQuizTrueFalseAnswers,
+		},
+		{
+			"multiple choice",
+			Quiz{Type: QuizTypeMultipleChoice, Answers: options, CorrectAnswers: []int{1, 3}},
+			nil,
+		},
+		{
+			"multiple choice with repeated option",
+			Quiz{Type: QuizTypeMultipleChoice, Answers: options, CorrectAnswers: []int{1, 1}},
+			ErrQuizCorrectAnswersInvalid,
+		},
+		{
+			"multiple choice with single correct answer",
+			Quiz{Type: QuizTypeMultipleChoice, Answers: options, CorrectAnswer: 1, CorrectAnswers: []int{1}},
+			ErrQuizAnswerKeyOfOtherType,
+		},
+		{"ordering", Quiz{Type: QuizTypeOrdering, Answers: options, CorrectAnswers: []int{3, 1, 2}}, nil},
+		{
+			"ordering in saved order",
+			Quiz{Type: QuizTypeOrdering, Answers: options, CorrectAnswers: []int{1, 2, 3}},
+			ErrQuizOrderingInvalid,
+		},
+		{
+			"ordering without all options",
+			Quiz{Type: QuizTypeOrdering, Answers: options, CorrectAnswers: []int{3, 1}},
+			ErrQuizOrderingInvalid,
+		},
+		{"numeric", Quiz{Type: QuizTypeNumeric, NumericAnswer: 3.5, NumericTolerance: 0.5}, nil},
+		{
+			"numeric with negative tolerance",
+			Quiz{Type: QuizTypeNumeric, NumericAnswer: 3.5, NumericTolerance: -1},
+			ErrQuizNumericAnswerInvalid,
+		},
+		{
+			"numeric with options",
+			Quiz{Type: QuizTypeNumeric, Answers: options, NumericAnswer: 3},
+			ErrQuizAnswerKeyOfOtherType,
+		},
+		{"unknown type", Quiz{Type: "essay", Answers: options, CorrectAnswer: 1}, ErrQuizTypeUnknown},
+	}
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				if err := test.quiz.ValidateAnswerKey(); !errors.Is(err, test.expected) {
+					t.Errorf("Wrong error. Expected %v, got %v\n", test.expected, err)
+				}
+			},
+		)
+	}
+}
+
+func TestQuiz_CheckAnswer(t *testing.T) {
+	options := []string{"a", "b", "c"}
+	number := func(n float64) *float64 {
+		return &n
+	}
+	multiple := Quiz{Type: QuizTypeMultipleChoice, Answers: options, CorrectAnswers: []int{1, 3}}
+	ordering := Quiz{Type: QuizTypeOrdering, Answers: options, CorrectAnswers: []int{3, 1, 2}}
+	numeric := Quiz{Type: QuizTypeNumeric, NumericAnswer: 10, NumericTolerance: 0.5}
+	tests := []struct {
+		name     string
+		quiz     Quiz
+		answer   QuizAnswerValue
+		expected bool
+	}{
+		{"single choice correct", Quiz{Answers: options, CorrectAnswer: 2}, QuizAnswerValue{Option: 2}, true},
+		{"single choice wrong", Quiz{Answers: options, CorrectAnswer: 2}, QuizAnswerValue{Option: 1}, false},
+		{"multiple choice in other order", multiple, QuizAnswerValue{Options: []int{3, 1}}, true},
+		{"multiple choice with missed option", multiple, QuizAnswerValue{Options: []int{1}}, false},
+		{"multiple choice with extra option", multiple, QuizAnswerValue{Options: []int{1, 2, 3}}, false},
+		{"multiple choice with repeated option", multiple, QuizAnswerValue{Options: []int{1, 1}}, false},
+		{"ordering correct", ordering, QuizAnswerValue{Options: []int{3, 1, 2}}, true},
+		{"ordering wrong", ordering, QuizAnswerValue{Options: []int{1, 3, 2}}, false},
+		{"numeric within tolerance", numeric, QuizAnswerValue{Number: number(10.5)}, true},
+		{"numeric out of tolerance", numeric, QuizAnswerValue{Number: number(9.4)}, false},
+		{"numeric without number", numeric, QuizAnswerValue{Option: 10}, false},
+	}
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				if correct := test.quiz.CheckAnswer(test.answer); correct != test.expected {
+					t.Errorf("Wrong correct. Expected %v, got %v\n", test.expected, correct)
+				}
+			},
+		)
+	}
+}
@@ -2,13 +2,215 @@ package model
 
 import (
 	"github.com/google/uuid"
+	"strings"
+	"time"
 )
 
 type Quiz struct {
-	ID                uuid.UUID
-	Question          string
-	Answers           []string
-	CorrectAnswer     int
+	ID       uuid.UUID
+	Type     QuizType
+	Question string
+	// Answers are options of the quiz, numeric quizzes have no options
+	Answers []string
+	// CorrectAnswer is the correct option of single choice and true/false quizzes starting from 1
+	CorrectAnswer int
+	// CorrectAnswers are correct options of multiple choice quizzes or the correct order of ordering quizzes
+	CorrectAnswers    []int
+	NumericAnswer     float64
+	NumericTolerance  float64
 	InfoLink          string
 	AnswerDescription string
+	// Category is a theme of the quiz like "deposits" or "fraud", it is empty for quizzes without a theme
+	Category string
+	// Difficulty is one of config.QuizDifficulty values
+	Difficulty string
+	Tags       []string
+	Status     QuizStatus
+	// AuthorID is nil for quizzes added before the moderation
+	AuthorID  uuid.UUID
+	CreatedAt time.Time
+	// Version is increased by every edit of the quiz, the first version is 1
+	Version int
+}
+
+// QuizUpdate has changed fields of the quiz, nil fields are not changed
+type QuizUpdate struct {
+	ID       uuid.UUID
+	Type     *QuizType
+	Question *string
+	Answers  []string
+	// CorrectAnswer can be set to zero when the quiz type is changed to one without the correct option
+	CorrectAnswer     *int
+	CorrectAnswers    []int
+	NumericAnswer     *float64
+	NumericTolerance  *float64
+	InfoLink          *string
+	AnswerDescription *string
+	Category          *string
+	Difficulty        *string
+	Tags              []string
+}
+
+// Apply returns the quiz with changed fields of the update
+func (u QuizUpdate) Apply(quiz Quiz) Quiz {
+	if u.Type != nil {
+		quiz.Type = *u.Type
+	}
+	if u.Question != nil {
+		quiz.Question = *u.Question
+	}
+	if u.Answers != nil {
+		quiz.Answers = u.Answers
+	}
+	if u.CorrectAnswer != nil {
+		quiz.CorrectAnswer = *u.CorrectAnswer
+	}
+	if u.CorrectAnswers != nil {
+		quiz.CorrectAnswers = u.CorrectAnswers
+	}
+	if u.NumericAnswer != nil {
+		quiz.NumericAnswer = *u.NumericAnswer
+	}
+	if u.NumericTolerance != nil {
+		quiz.NumericTolerance = *u.NumericTolerance
+	}
+	if u.InfoLink != nil {
+		quiz.InfoLink = *u.InfoLink
+	}
+	if u.AnswerDescription != nil {
+		quiz.AnswerDescription = *u.AnswerDescription
+	}
+	if u.Category != nil {
+		quiz.Category = *u.Category
+	}
+	if u.Difficulty != nil {
+		quiz.Difficulty = *u.Difficulty
+	}
+	if u.Tags != nil {
+		quiz.Tags = u.Tags
+	}
+	return quiz
+}
+
+// QuizSearch filters quizzes listed for writers and admins, empty fields are not checked
+type QuizSearch struct {
+	// Text is searched in questions and answer descriptions ignoring the case
+	Text     string
+	Category string
+	Status   QuizStatus
+	AuthorID uuid.UUID
+	Limit    int
+	Offset   int
+}
+
+// QuizPage is a page of found quizzes, Total is a count of all found quizzes
+type QuizPage struct {
+	Quizzes []Quiz
+	Total   int
+}
+
+// QuizVersion is contents of the quiz saved by its creation or an edit
+type QuizVersion struct {
+	Version int
+	// Quiz has only contents of the quiz without the status
+	Quiz      Quiz
+	EditorID  uuid.UUID
+	CreatedAt time.Time
+}
+
+// QuizStatus is a stage of the quiz moderation, only published quizzes are shown to players
+type QuizStatus string
+
+const (
+	QuizStatusDraft     QuizStatus = "draft"
+	QuizStatusInReview  QuizStatus = "in_review"
+	QuizStatusPublished QuizStatus = "published"
+	QuizStatusArchived  QuizStatus = "archived"
+)
+
+// QuizStatusTransition is a change of the quiz status made by a writer or an admin
+type QuizStatusTransition struct {
+	QuizID uuid.UUID
+	// From is empty for the creation of the quiz
+	From   QuizStatus
+	To     QuizStatus
+	UserID uuid.UUID
+	// Comment is a reason of the rejection or any note of the admin
+	Comment   string
+	CreatedAt time.Time
+}
+
+// QuizFilter limits the random selection of quizzes, empty fields are not checked
+type QuizFilter struct {
+	Category   string
+	Difficulty string
+}
+
+// QuizAnswer is an answer of the user saved in the quiz history
+type QuizAnswer struct {
+	QuizID uuid.UUID
+	Answer QuizAnswerValue
+	// Correct is true when the answer is the correct option of the quiz
+	Correct bool
+	// Rewarded is true only for the first correct answer of the user to the quiz
+	Rewarded   bool
+	AnsweredAt time.Time
+}
+
+// QuizAnswerResult is the checked answer of the user with the quiz to explain the correct answer
+type QuizAnswerResult struct {
+	Correct bool
+	// Attempt is a number of the answer of the user to the quiz starting from 1
+	Attempt int
+	// Reward is zero for wrong answers, repeated correct answers and attempts without a reward by the retry policy
+	Reward int
+	// KeyRevealed is false for wrong answers while a retry can be rewarded, the quiz has no answer key then
+	KeyRevealed bool
+	// Quiz has the answer key, the answer description and the info link of the quiz
+	Quiz Quiz
+}
+
+// NormalizeQuizQuestion makes questions which differ only in the case, spaces and the final punctuation equal,
+// the migration of normalized questions does the same in SQL.
+func NormalizeQuizQuestion(question string) string {
+	return strings.TrimRight(strings.ToLower(strings.Join(strings.Fields(question), " ")), "?!.,;: ")
+}
+
+// QuizImportRow is a quiz of the import batch
+type QuizImportRow struct {
+	// Row is a number of the quiz in the batch starting from 1
+	Row  int
+	Quiz Quiz
+	// Error is a reason why the quiz is invalid, invalid quizzes are only reported
+	Error string
+}
+
+type QuizImportStatus string
+
+const (
+	// QuizImportCreated is a status of a saved quiz, QuizImportValid replaces it in the dry run
+	QuizImportCreated   QuizImportStatus = "created"
+	QuizImportValid     QuizImportStatus = "valid"
+	QuizImportDuplicate QuizImportStatus = "duplicate"
+	QuizImportInvalid   QuizImportStatus = "invalid"
+)
+
+type QuizImportResult struct {
+	Row    int
+	Status QuizImportStatus
+	// QuizID is an id of the created quiz
+	QuizID uuid.UUID
+	// DuplicateOfQuizID is set when the question is already saved, DuplicateOfRow when it is earlier in the batch
+	DuplicateOfQuizID uuid.UUID
+	DuplicateOfRow    int
+	Error             string
+}
+
+type QuizImportReport struct {
+	DryRun  bool
+	Results []QuizImportResult
+	// Created is a count of created quizzes or quizzes which would be created in the dry run
+	Created    int
+	Duplicates int
+	Invalid    int
 }
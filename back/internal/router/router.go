@@ -15,12 +15,18 @@ type DocsWriter interface {
 }
 
 type Deps struct {
-	UserHandler     *handler.UserHandler
-	LineGameHandler *handler.LineGameHandler
-	BalanceHandler  *handler.BalanceHandler
-	QuizHandler     *handler.QuizHandler
-	ConfigHandler   *handler.ConfigHandler
-	DocsWriter      DocsWriter
+	UserHandler        *handler.UserHandler
+	LineGameHandler    *handler.LineGameHandler
+	DailyHandler       *handler.LineGameDailyHandler
+	BalanceHandler     *handler.BalanceHandler
+	EnergyHandler      *handler.EnergyHandler
+	InventoryHandler   *handler.InventoryHandler
+	QuizHandler        *handler.QuizHandler
+	QuizRoundHandler   *handler.QuizRoundHandler
+	ConfigHandler      *handler.ConfigHandler
+	AdminHandler       *handler.LineGameAdminHandler
+	LeaderboardHandler *handler.LeaderboardHandler
+	DocsWriter         DocsWriter
 }
 
 func Setup(rt *mux.Router, deps Deps, cfg config.Router) (http.Handler, error) {
@@ -38,16 +44,38 @@ func Setup(rt *mux.Router, deps Deps, cfg config.Router) (http.Handler, error) {
 	gameRouter := rt.PathPrefix("/game").Subrouter()
 
 	gameRouter.HandleFunc("/balance", deps.BalanceHandler.GetUserBalance).Methods(http.MethodGet)
+	gameRouter.HandleFunc("/energy", deps.EnergyHandler.GetEnergy).Methods(http.MethodGet)
+	gameRouter.HandleFunc("/energy/refill", deps.EnergyHandler.BuyEnergyRefill).Methods(http.MethodPost)
+	gameRouter.HandleFunc("/inventory", deps.InventoryHandler.GetInventory).Methods(http.MethodGet)
+	gameRouter.HandleFunc("/inventory/bundles/{bundle-id}", deps.InventoryHandler.BuyBoosterBundle).Methods(http.MethodPost)
 
 	gameRouter.HandleFunc("/line/level", deps.LineGameHandler.GetUserLevel).Methods(http.MethodGet)
 	gameRouter.HandleFunc("/line/level", deps.LineGameHandler.CompleteLevel).Methods(http.MethodPost)
+	gameRouter.HandleFunc("/line/stars", deps.LineGameHandler.GetLevelStars).Methods(http.MethodGet)
 	gameRouter.HandleFunc("/line/hint", deps.LineGameHandler.GetLevelHint).Methods(http.MethodGet)
-	gameRouter.HandleFunc("/line/time-stop-booster", deps.LineGameHandler.GetTimeStopBooster).Methods(http.MethodGet)
+	gameRouter.HandleFunc("/line/hint", deps.LineGameHandler.BuyLevelHint).Methods(http.MethodPost)
+	gameRouter.HandleFunc("/line/daily", deps.DailyHandler.GetDailyLevel).Methods(http.MethodGet)
+	gameRouter.HandleFunc("/line/daily", deps.DailyHandler.CompleteDailyLevel).Methods(http.MethodPost)
+	gameRouter.HandleFunc("/line/booster/{booster-id}", deps.LineGameHandler.UseBooster).Methods(http.MethodPost)
 
 	gameRouter.HandleFunc("/quiz", deps.QuizHandler.GetQuiz).Methods(http.MethodGet)
 	gameRouter.HandleFunc("/quiz", deps.QuizHandler.AddQuiz).Methods(http.MethodPost)
 	gameRouter.HandleFunc("/quiz", deps.QuizHandler.UpdateQuiz).Methods(http.MethodPut)
 	gameRouter.HandleFunc("/quiz/answer", deps.QuizHandler.AnswerQuiz).Methods(http.MethodPost)
+	gameRouter.HandleFunc("/quiz/history", deps.QuizHandler.GetQuizHistory).Methods(http.MethodGet)
+	gameRouter.HandleFunc("/quiz/round", deps.QuizRoundHandler.GetQuizRound).Methods(http.MethodGet)
+	gameRouter.HandleFunc("/quiz/round", deps.QuizRoundHandler.StartQuizRound).Methods(http.MethodPost)
+	gameRouter.HandleFunc("/quiz/list", deps.QuizHandler.SearchQuizzes).Methods(http.MethodGet)
+	gameRouter.HandleFunc("/quiz/import", deps.QuizHandler.ImportQuizzes).Methods(http.MethodPost)
+	gameRouter.HandleFunc("/quiz/export", deps.QuizHandler.ExportQuizzes).Methods(http.MethodGet)
+	gameRouter.HandleFunc("/quiz/stats", deps.QuizHandler.GetQuizStats).Methods(http.MethodGet)
+	gameRouter.HandleFunc("/quiz/{quiz-id}", deps.QuizHandler.ArchiveQuiz).Methods(http.MethodDelete)
+	gameRouter.HandleFunc("/quiz/{quiz-id}/versions", deps.QuizHandler.GetQuizVersions).Methods(http.MethodGet)
+	gameRouter.HandleFunc("/quiz/{quiz-id}/status", deps.QuizHandler.ChangeQuizStatus).Methods(http.MethodPut)
+	gameRouter.HandleFunc("/quiz/{quiz-id}/transitions", deps.QuizHandler.GetQuizStatusTransitions).Methods(http.MethodGet)
+	gameRouter.HandleFunc("/quiz/round/answer", deps.QuizRoundHandler.AnswerQuizRound).Methods(http.MethodPost)
+
+	rt.HandleFunc("/leaderboard", deps.LeaderboardHandler.GetLeaderboard).Methods(http.MethodGet)
 
 	configRouter := rt.PathPrefix("/config").Subrouter()
 
@@ -59,6 +87,19 @@ func Setup(rt *mux.Router, deps Deps, cfg config.Router) (http.Handler, error) {
 	configRouter.HandleFunc("/balance", deps.ConfigHandler.UpdateBalanceGameConfig).Methods(http.MethodPut)
 	configRouter.HandleFunc("/price", deps.ConfigHandler.GetPriceGameConfig).Methods(http.MethodGet)
 	configRouter.HandleFunc("/price", deps.ConfigHandler.UpdatePriceGameConfig).Methods(http.MethodPut)
+	configRouter.HandleFunc("/energy", deps.ConfigHandler.GetEnergyGameConfig).Methods(http.MethodGet)
+	configRouter.HandleFunc("/energy", deps.ConfigHandler.UpdateEnergyGameConfig).Methods(http.MethodPut)
+	configRouter.HandleFunc("/boosters", deps.ConfigHandler.GetBoostersGameConfig).Methods(http.MethodGet)
+	configRouter.HandleFunc("/boosters", deps.ConfigHandler.UpdateBoostersGameConfig).Methods(http.MethodPut)
+
+	adminRouter := rt.PathPrefix("/admin").Subrouter()
+
+	adminRouter.HandleFunc("/line/groups", deps.AdminHandler.GetGroups).Methods(http.MethodGet)
+	adminRouter.HandleFunc("/line/groups", deps.AdminHandler.UploadGroup).Methods(http.MethodPost)
+	adminRouter.HandleFunc("/line/groups/{group-code}", deps.AdminHandler.GetGroup).Methods(http.MethodGet)
+	adminRouter.HandleFunc("/line/groups/{group-code}/publish", deps.AdminHandler.PublishGroup).Methods(http.MethodPost)
+	adminRouter.HandleFunc("/quiz/review", deps.QuizHandler.GetQuizzesForReview).Methods(http.MethodGet)
+	adminRouter.HandleFunc("/users/{user-id}/boosters", deps.InventoryHandler.GiftBoosters).Methods(http.MethodPost)
 
 	rt.PathPrefix("/swagger/").Handler(
 		httpSwagger.Handler(
@@ -3,17 +3,16 @@ package file_storage
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"github.com/4units/mos-hack-game/back/config"
 	"github.com/4units/mos-hack-game/back/internal/model"
+	logs "github.com/4units/mos-hack-game/back/pkg/logging"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
-)
-
-var (
-	ErrGroupFileDoesNotExist = errors.New("group file does not exist")
+	"sync/atomic"
+	"time"
 )
 
 type lineGameGroup struct {
@@ -44,24 +43,84 @@ type LineGameConifgProvider interface {
 
 type LevelStorage struct {
 	LineGameConifgProvider
-	levelGroups   map[model.LineGameLevelGroupCode][]model.LineGameLevel
+	catalog       atomic.Pointer[model.LineGameCatalog]
+	dirState      string
 	lineLevelsDir string
 }
 
-func NewLineGameLevelStorage(lineGameConfigProvider LineGameConifgProvider, lineLevelsDir string) *LevelStorage {
-	return &LevelStorage{
+// NewLineGameLevelStorage loads and validates all level groups from the directory once,
+// so the storage never touches the file system while serving requests.
+func NewLineGameLevelStorage(
+	lineGameConfigProvider LineGameConifgProvider,
+	lineLevelsDir string,
+) (*LevelStorage, error) {
+	storage := &LevelStorage{
 		LineGameConifgProvider: lineGameConfigProvider,
-		levelGroups:            make(map[model.LineGameLevelGroupCode][]model.LineGameLevel),
 		lineLevelsDir:          lineLevelsDir,
 	}
+	if _, err := storage.Reload(); err != nil {
+		return nil, err
+	}
+	return storage, nil
+}
+
+// Watch polls the levels directory and publishes a new catalog when group files are changed.
+// An invalid change is logged and the previous catalog keeps being served.
+func (l *LevelStorage) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := l.Reload()
+			if err != nil {
+				logs.Error("failed to reload line game levels", err, slog.String("dir", l.lineLevelsDir))
+				continue
+			}
+			if reloaded {
+				slog.Info(
+					"line game levels are reloaded", slog.String("dir", l.lineLevelsDir),
+					slog.Int("groups", l.catalog.Load().GroupsCount()),
+				)
+			}
+		}
+	}
 }
 
+// Reload loads the catalog again if group files are changed since the last call.
+// It is not safe for concurrent use with itself, only with the reads.
+func (l *LevelStorage) Reload() (bool, error) {
+	state, err := readDirState(l.lineLevelsDir)
+	if err != nil {
+		return false, err
+	}
+	if state == l.dirState {
+		return false, nil
+	}
+	catalog, err := loadCatalog(l.lineLevelsDir)
+	if err != nil {
+		return false, err
+	}
+	l.catalog.Store(catalog)
+	// the state is saved only after the load, so a file read in the middle of its write is loaded again
+	l.dirState = state
+	return true, nil
+}
+
+// GetClosestLowOrDefaultLevel returns the position of the closest existing level for the level which
+// is not in the storage anymore.
 func (l *LevelStorage) GetClosestLowOrDefaultLevel(
-	ctx context.Context,
+	_ context.Context,
 	groupCode model.LineGameLevelGroupCode,
 	num int,
-) (model.LineGameLevel, error) {
-	return l.GetLevel(ctx, groupCode, num)
+) (model.LineGameLevelGroupCode, int, error) {
+	closestCode, closestNum, ok := l.catalog.Load().ClosestLevel(groupCode, num)
+	if !ok {
+		return "", 0, model.ErrLineGameNoFileWithLevelGroups
+	}
+	return closestCode, closestNum, nil
 }
 
 func (l *LevelStorage) GetLevel(
@@ -69,75 +128,66 @@ func (l *LevelStorage) GetLevel(
 	groupCode model.LineGameLevelGroupCode,
 	levelNum int,
 ) (model.LineGameLevel, error) {
-	if err := l.loadGroup(groupCode); err != nil {
-		return model.LineGameLevel{}, err
-	}
-	levels := l.levelGroups[groupCode]
-	if levelNum >= len(levels) {
-		return model.LineGameLevel{}, model.ErrLineGameNotExistsLevelInStorage
-	}
-	return levels[levelNum], nil
+	return l.catalog.Load().Level(groupCode, levelNum)
 }
 
 func (l lineGameGroup) GetCode() model.LineGameLevelGroupCode {
 	return model.GetLevelGroupID(l.FieldSize, l.Orders, l.Blockers)
 }
 
+// GetNextLevel returns the level after the current one. When the current level is not in
+// the storage anymore, the next level is counted from its closest existing level.
 func (l *LevelStorage) GetNextLevel(
 	_ context.Context,
 	currentGroupCode model.LineGameLevelGroupCode,
 	currentLevelNum int,
 ) (model.LineGameLevelGroupCode, int, error) {
-	if err := l.loadGroup(currentGroupCode); err != nil {
-		return "", 0, err
-	}
-	if currentLevelNum+1 < len(l.levelGroups[currentGroupCode]) {
-		return currentGroupCode, currentLevelNum + 1, nil
+	return l.catalog.Load().NextLevel(currentGroupCode, currentLevelNum)
+}
+
+// GetNextGroupLevel returns the first level of the group after the current one.
+func (l *LevelStorage) GetNextGroupLevel(
+	_ context.Context,
+	currentGroupCode model.LineGameLevelGroupCode,
+) (model.LineGameLevelGroupCode, int, error) {
+	return l.catalog.Load().NextGroupLevel(currentGroupCode)
+}
+
+func (l *LevelStorage) GetStartGroupCode(_ context.Context) (model.LineGameLevelGroupCode, error) {
+	groupCode, ok := l.catalog.Load().StartGroupCode()
+	if !ok {
+		return "", model.ErrLineGameNoFileWithLevelGroups
 	}
-	files, err := os.ReadDir(l.lineLevelsDir)
+	return groupCode, nil
+}
+
+// readDirState describes names, sizes and modification times of group files to detect changes.
+func readDirState(lineLevelsDir string) (string, error) {
+	files, err := os.ReadDir(lineLevelsDir)
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to read dir with levels: %w", err)
+		return "", fmt.Errorf("failed to read dir with levels: %w", err)
 	}
-	foundGroupCurrent := false
+	var state strings.Builder
 	for _, file := range files {
-		if file.IsDir() {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
 			continue
 		}
-		fileName := file.Name()
-		if !strings.HasSuffix(fileName, ".json") {
-			continue
-		}
-		filePath := filepath.Join(l.lineLevelsDir, fileName)
-		rawFile, err := os.ReadFile(filePath)
+		info, err := file.Info()
 		if err != nil {
-			return "", 0, fmt.Errorf("failed to read file %s: %w", filePath, err)
-		}
-		var rawGroup lineGameGroup
-
-		if err = json.Unmarshal(rawFile, &rawGroup); err != nil {
-			return "", 0, fmt.Errorf("failed to unmarshal file %s: %w", filePath, err)
-		}
-		if rawGroup.FieldSize == 0 {
-			continue
-		}
-
-		if foundGroupCurrent {
-			return rawGroup.GetCode(), 0, nil
-		}
-
-		if rawGroup.GetCode() == currentGroupCode {
-			foundGroupCurrent = true
-			continue
+			return "", fmt.Errorf("failed to get info of file %s: %w", file.Name(), err)
 		}
+		state.WriteString(fmt.Sprintf("%s:%v:%v;", file.Name(), info.Size(), info.ModTime().UnixNano()))
 	}
-	return "", 0, model.ErrLineGameGroupsIsFinished
+	return state.String(), nil
 }
 
-func (l *LevelStorage) GetStartGroupCode(_ context.Context) (model.LineGameLevelGroupCode, error) {
-	files, err := os.ReadDir(l.lineLevelsDir)
+// loadCatalog reads every group file of the directory. Groups are played in the order of file names.
+func loadCatalog(lineLevelsDir string) (*model.LineGameCatalog, error) {
+	files, err := os.ReadDir(lineLevelsDir)
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("failed to read dir with levels: %w", err)
 	}
+	groups := make([]model.LineGameLevelGroup, 0, len(files))
 	for _, file := range files {
 		if file.IsDir() {
 			continue
@@ -146,81 +196,80 @@ func (l *LevelStorage) GetStartGroupCode(_ context.Context) (model.LineGameLevel
 		if !strings.HasSuffix(fileName, ".json") {
 			continue
 		}
-		filePath := filepath.Join(l.lineLevelsDir, fileName)
+		filePath := filepath.Join(lineLevelsDir, fileName)
 		rawFile, err := os.ReadFile(filePath)
 		if err != nil {
-			return "", fmt.Errorf("failed to read file %s: %w", filePath, err)
+			return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
 		}
 		var rawGroup lineGameGroup
 
 		if err = json.Unmarshal(rawFile, &rawGroup); err != nil {
-			return "", fmt.Errorf("failed to unmarshal file %s: %w", filePath, err)
+			return nil, fmt.Errorf("failed to unmarshal file %s: %w", filePath, err)
 		}
 		if rawGroup.FieldSize == 0 {
 			continue
 		}
-		return rawGroup.GetCode(), nil
+		if len(rawGroup.Levels) == 0 {
+			slog.Warn("Skip level group without levels", slog.String("file", filePath))
+			continue
+		}
+		if expected := string(rawGroup.GetCode()) + ".json"; fileName != expected {
+			return nil, fmt.Errorf("file %s should be named %s", filePath, expected)
+		}
+		groups = append(groups, rawGroup.toModel())
+	}
+	catalog, err := model.NewLineGameCatalog(groups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build catalog of %s: %w", lineLevelsDir, err)
 	}
-	return "", model.ErrLineGameNoFileWithLevelGroups
+	return catalog, nil
 }
 
-func (l *LevelStorage) loadGroup(groupCode model.LineGameLevelGroupCode) error {
-	if _, ok := l.levelGroups[groupCode]; !ok {
-		filePath := fmt.Sprintf("%s/%s.json", l.lineLevelsDir, groupCode)
-		if _, err := os.Stat(filePath); err != nil {
-			if os.IsNotExist(err) {
-				return fmt.Errorf("stat file %s error: %w", filePath, ErrGroupFileDoesNotExist)
-			}
-			return fmt.Errorf("stat file %s error: %w", filePath, err)
-		}
-		fileContent, err := os.ReadFile(filePath)
-		if err != nil {
-			return fmt.Errorf("failed to read file of level group %s: %w", filePath, err)
-		}
-		var group lineGameGroup
-		if err = json.Unmarshal(fileContent, &group); err != nil {
-			return fmt.Errorf("failed to unmarshal file %s: %w", filePath, err)
+func (l lineGameGroup) toModel() model.LineGameLevelGroup {
+	group := model.LineGameLevelGroup{
+		FieldSize: l.FieldSize,
+		Order:     l.Orders,
+		Blockers:  l.Blockers,
+		Levels:    make([]model.LineGameLevel, 0, len(l.Levels)),
+	}
+	for _, rawLevel := range l.Levels {
+		level := model.LineGameLevel{
+			FieldSize: rawLevel.FieldSize,
+			Start: model.LineGameLevelCell{
+				X: rawLevel.StartCell.X,
+				Y: rawLevel.StartCell.Y,
+			},
+			End: model.LineGameLevelCell{
+				X: rawLevel.EndCell.X,
+				Y: rawLevel.EndCell.Y,
+			},
+			Order:    make([]model.LineGameLevelCell, 0, len(rawLevel.Order)),
+			Blockers: make([]model.LineGameLevelCell, 0, len(rawLevel.Blockers)),
+			Answer:   make([][]int, len(rawLevel.Answer)),
 		}
-		l.levelGroups[groupCode] = make([]model.LineGameLevel, 0, len(group.Levels))
-		for _, rawLevel := range group.Levels {
-			level := model.LineGameLevel{
-				FieldSize: rawLevel.FieldSize,
-				Start: model.LineGameLevelCell{
-					X: rawLevel.StartCell.X,
-					Y: rawLevel.StartCell.Y,
+		for _, cell := range rawLevel.Order {
+			level.Order = append(
+				level.Order, model.LineGameLevelCell{
+					X: cell.X,
+					Y: cell.Y,
 				},
-				End: model.LineGameLevelCell{
-					X: rawLevel.EndCell.X,
-					Y: rawLevel.EndCell.Y,
+			)
+		}
+		for _, cell := range rawLevel.Blockers {
+			level.Blockers = append(
+				level.Blockers, model.LineGameLevelCell{
+					X: cell.X,
+					Y: cell.Y,
 				},
-				Order:    make([]model.LineGameLevelCell, 0, len(rawLevel.Order)),
-				Blockers: make([]model.LineGameLevelCell, 0, len(rawLevel.Blockers)),
-				Answer:   make([][]int, len(rawLevel.Answer)),
-			}
-			for _, cell := range rawLevel.Order {
-				level.Order = append(
-					level.Order, model.LineGameLevelCell{
-						X: cell.X,
-						Y: cell.Y,
-					},
-				)
-			}
-			for _, cell := range rawLevel.Blockers {
-				level.Blockers = append(
-					level.Blockers, model.LineGameLevelCell{
-						X: cell.X,
-						Y: cell.Y,
-					},
-				)
-			}
-			for i, answerRow := range rawLevel.Answer {
-				level.Answer[i] = make([]int, 0, len(answerRow))
-				for _, cellVector := range answerRow {
-					level.Answer[i] = append(level.Answer[i], cellVector)
-				}
+			)
+		}
+		for i, answerRow := range rawLevel.Answer {
+			level.Answer[i] = make([]int, 0, len(answerRow))
+			for _, cellVector := range answerRow {
+				level.Answer[i] = append(level.Answer[i], cellVector)
 			}
-			l.levelGroups[groupCode] = append(l.levelGroups[groupCode], level)
 		}
+		group.Levels = append(group.Levels, level)
 	}
-	return nil
+	return group
 }
@@ -0,0 +1,255 @@
+package file_storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLevelStorage_PlayAllLevels(t *testing.T) {
+	storage, err := NewLineGameLevelStorage(nil, "../../../levels")
+	if err != nil {
+		t.Fatalf("Failed to load levels: %v\n", err)
+	}
+	ctx := context.Background()
+	groupCode, err := storage.GetStartGroupCode(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get start group: %v\n", err)
+	}
+	levelNum, played := 0, 0
+	for {
+		level, err := storage.GetLevel(ctx, groupCode, levelNum)
+		if err != nil {
+			t.Fatalf("Failed to get level %s %v: %v\n", groupCode, levelNum, err)
+		}
+		if err = level.CheckAnswer(level.Answer); err != nil {
+			t.Errorf("Wrong stored answer of level %s %v: %v\n", groupCode, levelNum, err)
+		}
+		played++
+		groupCode, levelNum, err = storage.GetNextLevel(ctx, groupCode, levelNum)
+		if errors.Is(err, model.ErrLineGameGroupsIsFinished) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to get next level: %v\n", err)
+		}
+	}
+	if played == 0 {
+		t.Errorf("Wrong played levels count. Expected more than 0, got %v\n", played)
+	}
+}
+
+func TestLevelStorage_InvalidAnswer(t *testing.T) {
+	dir := t.TempDir()
+	group := snakeGroup(3, 0)
+	group.Levels[0].Answer[0][0] = 2
+	writeGroup(t, dir, group)
+
+	if _, err := NewLineGameLevelStorage(nil, dir); err == nil {
+		t.Errorf("Wrong result. Expected error for level with incorrect answer, got nil\n")
+	}
+}
+
+func TestLevelStorage_WrongFileName(t *testing.T) {
+	dir := t.TempDir()
+	content, err := json.Marshal(snakeGroup(3, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, "3_1_0.json"), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = NewLineGameLevelStorage(nil, dir); err == nil {
+		t.Errorf("Wrong result. Expected error for file named not by group code, got nil\n")
+	}
+}
+
+func TestLevelStorage_Reload(t *testing.T) {
+	dir := t.TempDir()
+	writeGroup(t, dir, snakeGroup(3, 0))
+	storage, err := NewLineGameLevelStorage(nil, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	writeGroup(t, dir, snakeGroup(4, 0))
+	if reloaded, err := storage.Reload(); err != nil || !reloaded {
+		t.Fatalf("Wrong reload result. Expected reloaded catalog, got %v, %v\n", reloaded, err)
+	}
+	nextCode, _, err := storage.GetNextLevel(ctx, "3_0_0", 0)
+	if err != nil || nextCode != "4_0_0" {
+		t.Errorf("Wrong next group. Expected 4_0_0, got %v, %v\n", nextCode, err)
+	}
+
+	invalidGroup := snakeGroup(5, 0)
+	invalidGroup.Levels[0].Answer[0][0] = 0
+	writeGroup(t, dir, invalidGroup)
+	if _, err = storage.Reload(); err == nil {
+		t.Errorf("Wrong reload result. Expected error for invalid group, got nil\n")
+	}
+	if _, _, err = storage.GetNextLevel(ctx, "4_0_0", 0); !errors.Is(err, model.ErrLineGameGroupsIsFinished) {
+		t.Errorf("Wrong next level error. Expected previous catalog to be served, got %v\n", err)
+	}
+	// the failed load is retried by the next reload without changes of files
+	if _, err = storage.Reload(); err == nil {
+		t.Errorf("Wrong repeated reload result. Expected error for invalid group, got nil\n")
+	}
+}
+
+func TestLevelStorage_RemovedGroup(t *testing.T) {
+	dir := t.TempDir()
+	for _, fieldSize := range []int{3, 4, 5} {
+		writeGroup(t, dir, snakeGroup(fieldSize, 0))
+	}
+	storage, err := NewLineGameLevelStorage(nil, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if err = os.Remove(filepath.Join(dir, "4_0_0.json")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = storage.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = storage.GetLevel(ctx, "4_0_0", 0); !errors.Is(err, model.ErrLineGameNotExistsLevelInStorage) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrLineGameNotExistsLevelInStorage, err)
+	}
+	closestCode, closestNum, err := storage.GetClosestLowOrDefaultLevel(ctx, "4_0_0", 0)
+	if err != nil || closestCode != "3_0_0" || closestNum != 0 {
+		t.Errorf("Wrong closest level. Expected 3_0_0 0, got %v %v, %v\n", closestCode, closestNum, err)
+	}
+	nextCode, nextNum, err := storage.GetNextLevel(ctx, "4_0_0", 0)
+	if err != nil || nextCode != "5_0_0" || nextNum != 0 {
+		t.Errorf("Wrong next level. Expected 5_0_0 0, got %v %v, %v\n", nextCode, nextNum, err)
+	}
+}
+
+// BenchmarkLevelStorage_GetNextLevel measures the transition from the last level of the middle group
+// to the next group, which used to read every file of the levels directory.
+func BenchmarkLevelStorage_GetNextLevel(b *testing.B) {
+	for _, filesCount := range []int{10, 100, 1000} {
+		b.Run(
+			fmt.Sprintf("files=%v", filesCount), func(b *testing.B) {
+				dir := b.TempDir()
+				codes := writeSnakeGroups(b, dir, filesCount)
+				storage, err := NewLineGameLevelStorage(nil, dir)
+				if err != nil {
+					b.Fatal(err)
+				}
+				ctx := context.Background()
+				groupCode := codes[len(codes)/2]
+
+				b.ResetTimer()
+				for range b.N {
+					if _, _, err = storage.GetNextLevel(ctx, groupCode, 0); err != nil {
+						b.Fatal(err)
+					}
+				}
+			},
+		)
+	}
+}
+
+func BenchmarkLevelStorage_GetNextLevel_Parallel(b *testing.B) {
+	dir := b.TempDir()
+	codes := writeSnakeGroups(b, dir, 100)
+	storage, err := NewLineGameLevelStorage(nil, dir)
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+
+	b.ResetTimer()
+	b.RunParallel(
+		func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				if _, _, err := storage.GetNextLevel(ctx, codes[i%len(codes)], 0); err != nil {
+					b.Error(err)
+					return
+				}
+				i++
+			}
+		},
+	)
+}
+
+// writeSnakeGroups writes groups with unique codes, varying field size and orders count.
+// Returned codes are sorted in the play order without the last group.
+func writeSnakeGroups(tb testing.TB, dir string, count int) []model.LineGameLevelGroupCode {
+	codes := make([]model.LineGameLevelGroupCode, 0, count)
+	for fieldSize := 2; len(codes) < count; fieldSize++ {
+		for orders := 0; orders <= fieldSize*fieldSize-2 && len(codes) < count; orders++ {
+			group := snakeGroup(fieldSize, orders)
+			writeGroup(tb, dir, group)
+			codes = append(codes, group.GetCode())
+		}
+	}
+	slices.Sort(codes)
+	return codes[:len(codes)-1]
+}
+
+func writeGroup(tb testing.TB, dir string, group lineGameGroup) {
+	content, err := json.Marshal(group)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, string(group.GetCode())+".json"), content, 0644); err != nil {
+		tb.Fatal(err)
+	}
+}
+
+// snakeGroup returns a group with one level passing rows left to right and right to left in turn.
+func snakeGroup(fieldSize, orders int) lineGameGroup {
+	path := make([]lineGameCell, 0, fieldSize*fieldSize)
+	for y := 0; y < fieldSize; y++ {
+		for i := 0; i < fieldSize; i++ {
+			x := i
+			if y%2 == 1 {
+				x = fieldSize - 1 - i
+			}
+			path = append(path, lineGameCell{X: x, Y: y})
+		}
+	}
+	answer := make([][]int, fieldSize)
+	for y := range answer {
+		answer[y] = make([]int, fieldSize)
+	}
+	for i := 0; i < len(path)-1; i++ {
+		cur, next := path[i], path[i+1]
+		switch {
+		case next.X > cur.X:
+			answer[cur.Y][cur.X] = 1
+		case next.X < cur.X:
+			answer[cur.Y][cur.X] = 3
+		default:
+			answer[cur.Y][cur.X] = 2
+		}
+	}
+	end := path[len(path)-1]
+	answer[end.Y][end.X] = 4
+	return lineGameGroup{
+		FieldSize: fieldSize,
+		Orders:    orders,
+		Levels: []lineGameLevel{
+			{
+				FieldSize: fieldSize,
+				StartCell: path[0],
+				EndCell:   end,
+				Order:     append([]lineGameCell{}, path[1:1+orders]...),
+				Blockers:  []lineGameCell{},
+				Answer:    answer,
+			},
+		},
+	}
+}
@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	http_errors "github.com/4units/mos-hack-game/back/pkg/http-errors"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"net/http"
+)
+
+var (
+	ErrBoosterInventoryUserNotExists = http_errors.NewSame("user does not exist", http.StatusNotFound)
+)
+
+type BoosterInventoryStorage struct {
+	pool *pgxpool.Pool
+	psql squirrel.StatementBuilderType
+}
+
+func NewBoosterInventoryStorage(pool *pgxpool.Pool) *BoosterInventoryStorage {
+	return &BoosterInventoryStorage{
+		pool: pool,
+		psql: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// GetBoosterInventory returns counts of boosters of the user by their ids.
+func (s *BoosterInventoryStorage) GetBoosterInventory(ctx context.Context, userID uuid.UUID) (map[string]int, error) {
+	q, args, err := s.psql.
+		Select("booster_id", "count").
+		From("user_boosters").
+		Where(squirrel.Eq{"user_id": userID}).
+		Where(squirrel.Gt{"count": 0}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build query: %w", err)
+	}
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	inventory := make(map[string]int)
+	for rows.Next() {
+		var (
+			boosterID string
+			count     int
+		)
+		if err = rows.Scan(&boosterID, &count); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		inventory[boosterID] = count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows err: %w", err)
+	}
+	return inventory, nil
+}
+
+// AddBoosters puts the boosters into the inventory of the user.
+func (s *BoosterInventoryStorage) AddBoosters(ctx context.Context, userID uuid.UUID, boosters map[string]int) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err = s.addBoosters(ctx, tx, userID, boosters); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// BuyBoosters spends soft currency of the user and puts the boosters into the inventory in one transaction.
+func (s *BoosterInventoryStorage) BuyBoosters(
+	ctx context.Context,
+	userID uuid.UUID,
+	price int,
+	boosters map[string]int,
+) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q, args, err := s.psql.
+		Update("user_balance").
+		Set("soft_currency", squirrel.Expr("soft_currency - ?", price)).
+		Where(squirrel.Eq{"user_id": userID}).
+		Where(squirrel.GtOrEq{"soft_currency": price}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build balance update: %w", err)
+	}
+	ct, err := tx.Exec(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("exec balance update: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return model.ErrNotEnoughSoftCurrency
+	}
+
+	if err = s.addBoosters(ctx, tx, userID, boosters); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func (s *BoosterInventoryStorage) addBoosters(
+	ctx context.Context,
+	tx pgx.Tx,
+	userID uuid.UUID,
+	boosters map[string]int,
+) error {
+	if len(boosters) == 0 {
+		return nil
+	}
+	insert := s.psql.
+		Insert("user_boosters").
+		Columns("user_id", "booster_id", "count")
+	for boosterID, count := range boosters {
+		insert = insert.Values(userID, boosterID, count)
+	}
+	q, args, err := insert.
+		Suffix(
+			"ON CONFLICT (user_id, booster_id) DO UPDATE SET count = user_boosters.count + EXCLUDED.count, " +
+				"updated_at = CURRENT_TIMESTAMP",
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build boosters insert: %w", err)
+	}
+	if _, err = tx.Exec(ctx, q, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrBoosterInventoryUserNotExists
+		}
+		return fmt.Errorf("exec boosters insert: %w", err)
+	}
+	return nil
+}
+
+// spendBooster takes one booster from the inventory of the user and returns the count left.
+func spendBooster(
+	ctx context.Context,
+	db queryRower,
+	psql squirrel.StatementBuilderType,
+	userID uuid.UUID,
+	boosterID string,
+) (int, error) {
+	q, args, err := psql.
+		Update("user_boosters").
+		Set("count", squirrel.Expr("count - 1")).
+		Set("updated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"user_id": userID, "booster_id": boosterID}).
+		Where(squirrel.Gt{"count": 0}).
+		Suffix("RETURNING count").
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build booster update: %w", err)
+	}
+	var count int
+	if err = db.QueryRow(ctx, q, args...).Scan(&count); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, model.ErrBoosterNotInInventory
+		}
+		return 0, fmt.Errorf("exec booster update: %w", err)
+	}
+	return count, nil
+}
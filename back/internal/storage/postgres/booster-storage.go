@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LineGameBoosterStorage struct {
+	pool *pgxpool.Pool
+	psql squirrel.StatementBuilderType
+}
+
+func NewLineGameBoosterStorage(pool *pgxpool.Pool) *LineGameBoosterStorage {
+	return &LineGameBoosterStorage{
+		pool: pool,
+		psql: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// UseBooster takes the booster from the inventory, counts its use during the level attempt and saves
+// the revealed hint in one transaction. A limit above zero caps uses of the booster per attempt.
+// It returns the uses of the booster during the attempt and the count left in the inventory.
+func (s *LineGameBoosterStorage) UseBooster(
+	ctx context.Context,
+	userID uuid.UUID,
+	attempt model.LineGameLevelAttempt,
+	boosterID string,
+	limit int,
+	hint *model.LineGameHint,
+) (int, int, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	conflict := "ON CONFLICT (user_id, group_code, level_num, passed_count, booster_id) " +
+		"DO UPDATE SET uses = line_game_booster_uses.uses + 1, updated_at = CURRENT_TIMESTAMP"
+	var conflictArgs []any
+	if limit > 0 {
+		conflict += " WHERE line_game_booster_uses.uses < ?"
+		conflictArgs = append(conflictArgs, limit)
+	}
+	q, args, err := s.psql.
+		Insert("line_game_booster_uses").
+		Columns("user_id", "group_code", "level_num", "passed_count", "booster_id", "uses").
+		Values(userID, string(attempt.GroupCode), attempt.LevelNum, attempt.PassedCount, boosterID, 1).
+		Suffix(conflict+" RETURNING uses", conflictArgs...).
+		ToSql()
+	if err != nil {
+		return 0, 0, fmt.Errorf("build uses insert: %w", err)
+	}
+	var uses int
+	if err = tx.QueryRow(ctx, q, args...).Scan(&uses); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, 0, model.ErrLineGameBoosterLimitExceeded
+		}
+		return 0, 0, fmt.Errorf("exec uses insert: %w", err)
+	}
+
+	count, err := spendBooster(ctx, tx, s.psql, userID, boosterID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if hint != nil {
+		added, err := addLevelHint(ctx, tx, s.psql, userID, attempt, *hint)
+		if err != nil {
+			return 0, 0, err
+		}
+		// the same hint is already revealed, so the booster is kept
+		if !added {
+			return 0, 0, model.ErrLineGameBoosterNotAvailable
+		}
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return 0, 0, fmt.Errorf("commit tx: %w", err)
+	}
+	return uses, count, nil
+}
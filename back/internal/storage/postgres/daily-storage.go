@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type LineGameDailyStorage struct {
+	pool *pgxpool.Pool
+	psql squirrel.StatementBuilderType
+}
+
+func NewLineGameDailyStorage(pool *pgxpool.Pool) *LineGameDailyStorage {
+	return &LineGameDailyStorage{
+		pool: pool,
+		psql: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (s *LineGameDailyStorage) GetLastDailyCompletion(
+	ctx context.Context,
+	userID uuid.UUID,
+) (model.LineGameDailyCompletion, error) {
+	q, args, err := s.psql.
+		Select("day", "time_since_start", "reward", "streak").
+		From("line_game_daily_completions").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("day DESC").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return model.LineGameDailyCompletion{}, fmt.Errorf("build query: %w", err)
+	}
+
+	var (
+		completion     model.LineGameDailyCompletion
+		timeSinceStart int
+	)
+	if err = s.pool.QueryRow(ctx, q, args...).Scan(
+		&completion.Day, &timeSinceStart, &completion.Reward, &completion.Streak,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.LineGameDailyCompletion{}, model.ErrUserHasNoDailyCompletion
+		}
+		return model.LineGameDailyCompletion{}, fmt.Errorf("exec query: %w", err)
+	}
+	completion.TimeSinceStart = time.Duration(timeSinceStart) * time.Second
+	return completion, nil
+}
+
+func (s *LineGameDailyStorage) AddDailyCompletion(
+	ctx context.Context,
+	userID uuid.UUID,
+	completion model.LineGameDailyCompletion,
+) error {
+	q, args, err := s.psql.
+		Insert("line_game_daily_completions").
+		Columns("user_id", "day", "time_since_start", "reward", "streak").
+		Values(
+			userID, completion.Day, int(completion.TimeSinceStart.Seconds()), completion.Reward, completion.Streak,
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert: %w", err)
+	}
+
+	if _, err = s.pool.Exec(ctx, q, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return model.ErrLineGameDailyAlreadyCompleted
+		}
+		return fmt.Errorf("exec insert: %w", err)
+	}
+	return nil
+}
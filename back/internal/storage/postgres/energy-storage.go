@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type EnergyStorage struct {
+	pool *pgxpool.Pool
+	psql squirrel.StatementBuilderType
+}
+
+func NewEnergyStorage(pool *pgxpool.Pool) *EnergyStorage {
+	return &EnergyStorage{
+		pool: pool,
+		psql: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (s *EnergyStorage) GetUserEnergy(ctx context.Context, userID uuid.UUID) (model.UserEnergy, error) {
+	q, args, err := s.psql.
+		Select("energy", "updated_at", "paid_passed_count").
+		From("user_energy").
+		Where(squirrel.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		return model.UserEnergy{}, fmt.Errorf("build query: %w", err)
+	}
+
+	var energy model.UserEnergy
+	if err = s.pool.QueryRow(ctx, q, args...).Scan(
+		&energy.Value, &energy.UpdatedAt, &energy.PaidPassedCount,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.UserEnergy{}, model.ErrEnergyNotExists
+		}
+		return model.UserEnergy{}, fmt.Errorf("exec query: %w", err)
+	}
+	return energy, nil
+}
+
+// BuyEnergyRefill raises the saved energy to the refilled value and spends the price in one transaction.
+// The energy is not refilled twice by concurrent purchases, the second one gets ErrEnergyIsFull.
+func (s *EnergyStorage) BuyEnergyRefill(
+	ctx context.Context,
+	userID uuid.UUID,
+	energy model.UserEnergy,
+	price int,
+) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q, args, err := s.psql.
+		Update("user_energy").
+		Set("energy", energy.Value).
+		Set("updated_at", energy.UpdatedAt.UTC()).
+		Where(squirrel.Eq{"user_id": userID}).
+		Where(squirrel.Lt{"energy": energy.Value}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build energy update: %w", err)
+	}
+	ct, err := tx.Exec(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("exec energy update: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return model.ErrEnergyIsFull
+	}
+
+	q, args, err = s.psql.
+		Update("user_balance").
+		Set("soft_currency", squirrel.Expr("soft_currency - ?", price)).
+		Where(squirrel.Eq{"user_id": userID}).
+		Where(squirrel.GtOrEq{"soft_currency": price}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build balance update: %w", err)
+	}
+	if ct, err = tx.Exec(ctx, q, args...); err != nil {
+		return fmt.Errorf("exec balance update: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return model.ErrNotEnoughSoftCurrency
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// SpendLevelEnergy takes the cost once for the passed count in one transaction. The row of the user is locked
+// while its energy is regenerated, so concurrent attempts are not paid from the same units.
+func (s *EnergyStorage) SpendLevelEnergy(
+	ctx context.Context,
+	userID uuid.UUID,
+	passedCount, cost int,
+	initial model.UserEnergy,
+	regenerate func(model.UserEnergy) model.UserEnergy,
+) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q, args, err := s.psql.
+		Insert("user_energy").
+		Columns("user_id", "energy", "updated_at", "paid_passed_count").
+		Values(userID, initial.Value, initial.UpdatedAt.UTC(), initial.PaidPassedCount).
+		Suffix("ON CONFLICT (user_id) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert: %w", err)
+	}
+	if _, err = tx.Exec(ctx, q, args...); err != nil {
+		return fmt.Errorf("exec insert: %w", err)
+	}
+
+	q, args, err = s.psql.
+		Select("energy", "updated_at", "paid_passed_count").
+		From("user_energy").
+		Where(squirrel.Eq{"user_id": userID}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build select: %w", err)
+	}
+	var energy model.UserEnergy
+	if err = tx.QueryRow(ctx, q, args...).Scan(&energy.Value, &energy.UpdatedAt, &energy.PaidPassedCount); err != nil {
+		return fmt.Errorf("exec select: %w", err)
+	}
+	if energy.PaidPassedCount == passedCount {
+		return nil
+	}
+	energy = regenerate(energy)
+	if energy.Value < cost {
+		return model.ErrNotEnoughEnergy
+	}
+
+	q, args, err = s.psql.
+		Update("user_energy").
+		Set("energy", energy.Value-cost).
+		Set("updated_at", energy.UpdatedAt.UTC()).
+		Set("paid_passed_count", passedCount).
+		Where(squirrel.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build update: %w", err)
+	}
+	if _, err = tx.Exec(ctx, q, args...); err != nil {
+		return fmt.Errorf("exec update: %w", err)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
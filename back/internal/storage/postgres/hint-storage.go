@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LineGameHintStorage struct {
+	pool *pgxpool.Pool
+	psql squirrel.StatementBuilderType
+}
+
+func NewLineGameHintStorage(pool *pgxpool.Pool) *LineGameHintStorage {
+	return &LineGameHintStorage{
+		pool: pool,
+		psql: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (s *LineGameHintStorage) GetLevelHints(
+	ctx context.Context,
+	userID uuid.UUID,
+	attempt model.LineGameLevelAttempt,
+) ([]model.LineGameHint, error) {
+	q, args, err := s.psql.
+		Select("hint_type", "kept_cells", "cells", "price").
+		From("line_game_hints").
+		Where(
+			squirrel.Eq{
+				"user_id":      userID,
+				"group_code":   string(attempt.GroupCode),
+				"level_num":    attempt.LevelNum,
+				"passed_count": attempt.PassedCount,
+			},
+		).
+		OrderBy("created_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build query: %w", err)
+	}
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	hints := make([]model.LineGameHint, 0)
+	for rows.Next() {
+		var (
+			hint     model.LineGameHint
+			hintType string
+			rawCells []byte
+			cells    []lineGameCell
+		)
+		if err = rows.Scan(&hintType, &hint.KeptCells, &rawCells, &hint.Price); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		if err = json.Unmarshal(rawCells, &cells); err != nil {
+			return nil, fmt.Errorf("unmarshal cells: %w", err)
+		}
+		hint.Type = model.LineGameHintType(hintType)
+		hint.Cells = make([]model.LineGameLevelCell, 0, len(cells))
+		for _, cell := range cells {
+			hint.Cells = append(hint.Cells, model.LineGameLevelCell{X: cell.X, Y: cell.Y})
+		}
+		hints = append(hints, hint)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows err: %w", err)
+	}
+	return hints, nil
+}
+
+// BuyLevelHint saves the hint and spends its price in one transaction. It returns false and spends nothing
+// when the same hint is already saved for the attempt, so concurrent purchases are paid once.
+func (s *LineGameHintStorage) BuyLevelHint(
+	ctx context.Context,
+	userID uuid.UUID,
+	attempt model.LineGameLevelAttempt,
+	hint model.LineGameHint,
+) (bool, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	added, err := addLevelHint(ctx, tx, s.psql, userID, attempt, hint)
+	if err != nil || !added {
+		return false, err
+	}
+	q, args, err := s.psql.
+		Update("user_balance").
+		Set("soft_currency", squirrel.Expr("soft_currency - ?", hint.Price)).
+		Where(squirrel.Eq{"user_id": userID}).
+		Where(squirrel.GtOrEq{"soft_currency": hint.Price}).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("build balance update: %w", err)
+	}
+	ct, err := tx.Exec(ctx, q, args...)
+	if err != nil {
+		return false, fmt.Errorf("exec balance update: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return false, model.ErrNotEnoughSoftCurrency
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit tx: %w", err)
+	}
+	return true, nil
+}
+
+// addLevelHint returns false when the same hint is already saved for the attempt
+func addLevelHint(
+	ctx context.Context,
+	db execer,
+	psql squirrel.StatementBuilderType,
+	userID uuid.UUID,
+	attempt model.LineGameLevelAttempt,
+	hint model.LineGameHint,
+) (bool, error) {
+	cells := make([]lineGameCell, 0, len(hint.Cells))
+	for _, cell := range hint.Cells {
+		cells = append(cells, lineGameCell{X: cell.X, Y: cell.Y})
+	}
+	rawCells, err := json.Marshal(cells)
+	if err != nil {
+		return false, fmt.Errorf("marshal cells: %w", err)
+	}
+	q, args, err := psql.
+		Insert("line_game_hints").
+		Columns("user_id", "group_code", "level_num", "passed_count", "hint_type", "kept_cells", "cells", "price").
+		Values(
+			userID, string(attempt.GroupCode), attempt.LevelNum, attempt.PassedCount,
+			string(hint.Type), hint.KeptCells, rawCells, hint.Price,
+		).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("build hint insert: %w", err)
+	}
+	ct, err := db.Exec(ctx, q, args...)
+	if err != nil {
+		return false, fmt.Errorf("exec hint insert: %w", err)
+	}
+	return ct.RowsAffected() > 0, nil
+}
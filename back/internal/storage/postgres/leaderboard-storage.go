@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LeaderboardStorage struct {
+	pool *pgxpool.Pool
+	psql squirrel.StatementBuilderType
+}
+
+func NewLeaderboardStorage(pool *pgxpool.Pool) *LeaderboardStorage {
+	return &LeaderboardStorage{
+		pool: pool,
+		psql: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// AddScore increases the score of the user and returns the new score.
+func (s *LeaderboardStorage) AddScore(
+	ctx context.Context,
+	key model.LeaderboardKey,
+	userID uuid.UUID,
+	delta int,
+) (int, error) {
+	return s.upsertScore(ctx, key, userID, delta, "leaderboard_scores.score + EXCLUDED.score")
+}
+
+// SetBestScore keeps the best of the saved and the given scores and returns it.
+func (s *LeaderboardStorage) SetBestScore(
+	ctx context.Context,
+	key model.LeaderboardKey,
+	userID uuid.UUID,
+	score int,
+) (int, error) {
+	best := "GREATEST(leaderboard_scores.score, EXCLUDED.score)"
+	if key.LowerIsBetter {
+		best = "LEAST(leaderboard_scores.score, EXCLUDED.score)"
+	}
+	return s.upsertScore(ctx, key, userID, score, best)
+}
+
+func (s *LeaderboardStorage) upsertScore(
+	ctx context.Context,
+	key model.LeaderboardKey,
+	userID uuid.UUID,
+	score int,
+	updatedScore string,
+) (int, error) {
+	q, args, err := s.psql.
+		Insert("leaderboard_scores").
+		Columns("board", "period", "user_id", "score").
+		Values(key.Board, key.Period, userID, score).
+		Suffix(
+			"ON CONFLICT (board, period, user_id) DO UPDATE SET score = " + updatedScore +
+				", updated_at = CURRENT_TIMESTAMP RETURNING score",
+		).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build insert: %w", err)
+	}
+	var newScore int
+	if err = s.pool.QueryRow(ctx, q, args...).Scan(&newScore); err != nil {
+		return 0, fmt.Errorf("exec insert: %w", err)
+	}
+	return newScore, nil
+}
+
+// GetTopScores returns best entries with ranks but without display names.
+func (s *LeaderboardStorage) GetTopScores(
+	ctx context.Context,
+	key model.LeaderboardKey,
+	limit int,
+) ([]model.LeaderboardEntry, error) {
+	return s.getScores(ctx, key, uint64(limit))
+}
+
+// GetAllScores returns all entries of the board with ranks but without display names.
+func (s *LeaderboardStorage) GetAllScores(
+	ctx context.Context,
+	key model.LeaderboardKey,
+) ([]model.LeaderboardEntry, error) {
+	return s.getScores(ctx, key, 0)
+}
+
+func (s *LeaderboardStorage) getScores(
+	ctx context.Context,
+	key model.LeaderboardKey,
+	limit uint64,
+) ([]model.LeaderboardEntry, error) {
+	order := "score DESC"
+	if key.LowerIsBetter {
+		order = "score ASC"
+	}
+	builder := s.psql.
+		Select("user_id", "score").
+		From("leaderboard_scores").
+		Where(squirrel.Eq{"board": key.Board, "period": key.Period}).
+		OrderBy(order, "updated_at")
+	if limit > 0 {
+		builder = builder.Limit(limit)
+	}
+	q, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build query: %w", err)
+	}
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]model.LeaderboardEntry, 0)
+	for rows.Next() {
+		var entry model.LeaderboardEntry
+		if err = rows.Scan(&entry.UserID, &entry.Score); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows err: %w", err)
+	}
+	model.RankLeaderboardEntries(entries)
+	return entries, nil
+}
+
+// GetUserScore returns the entry of the user with a rank but without a display name.
+func (s *LeaderboardStorage) GetUserScore(
+	ctx context.Context,
+	key model.LeaderboardKey,
+	userID uuid.UUID,
+) (model.LeaderboardEntry, error) {
+	better := "better.score > own.score"
+	if key.LowerIsBetter {
+		better = "better.score < own.score"
+	}
+	q, args, err := s.psql.
+		Select("own.score", "COUNT(better.user_id)").
+		From("leaderboard_scores own").
+		LeftJoin(
+			"leaderboard_scores better ON better.board = own.board AND better.period = own.period AND " + better,
+		).
+		Where(squirrel.Eq{"own.board": key.Board, "own.period": key.Period, "own.user_id": userID}).
+		GroupBy("own.score").
+		ToSql()
+	if err != nil {
+		return model.LeaderboardEntry{}, fmt.Errorf("build query: %w", err)
+	}
+	entry := model.LeaderboardEntry{UserID: userID}
+	if err = s.pool.QueryRow(ctx, q, args...).Scan(&entry.Score, &entry.Rank); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.LeaderboardEntry{}, model.ErrLeaderboardHasNoUserScore
+		}
+		return model.LeaderboardEntry{}, fmt.Errorf("exec query: %w", err)
+	}
+	entry.Rank++
+	return entry, nil
+}
+
+// GetDisplayNames returns names of the users shown on leaderboards.
+func (s *LeaderboardStorage) GetDisplayNames(
+	ctx context.Context,
+	userIDs []uuid.UUID,
+) (map[uuid.UUID]string, error) {
+	names := make(map[uuid.UUID]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return names, nil
+	}
+	q, args, err := s.psql.
+		Select("u.user_id", "ep.email").
+		From("users u").
+		LeftJoin("email_passes ep ON u.user_id = ep.user_id").
+		Where(squirrel.Eq{"u.user_id": userIDs}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build query: %w", err)
+	}
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			userID uuid.UUID
+			email  sql.NullString
+		)
+		if err = rows.Scan(&userID, &email); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		names[userID] = model.LeaderboardDisplayName(userID, email.String)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows err: %w", err)
+	}
+	return names, nil
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type LineGameLevelAttemptStorage struct {
+	pool *pgxpool.Pool
+	psql squirrel.StatementBuilderType
+}
+
+func NewLineGameLevelAttemptStorage(pool *pgxpool.Pool) *LineGameLevelAttemptStorage {
+	return &LineGameLevelAttemptStorage{
+		pool: pool,
+		psql: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// StartLevelAttempt saves the start of the attempt with the passed count, a repeated start keeps the first one.
+func (s *LineGameLevelAttemptStorage) StartLevelAttempt(
+	ctx context.Context,
+	userID uuid.UUID,
+	passedCount int,
+	startedAt time.Time,
+) error {
+	q, args, err := s.psql.
+		Insert("line_game_level_attempts").
+		Columns("user_id", "passed_count", "started_at").
+		Values(userID, passedCount, startedAt.UTC()).
+		Suffix("ON CONFLICT (user_id, passed_count) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert: %w", err)
+	}
+	if _, err = s.pool.Exec(ctx, q, args...); err != nil {
+		return fmt.Errorf("exec insert: %w", err)
+	}
+	return nil
+}
+
+func (s *LineGameLevelAttemptStorage) GetLevelAttemptStart(
+	ctx context.Context,
+	userID uuid.UUID,
+	passedCount int,
+) (time.Time, error) {
+	q, args, err := s.psql.
+		Select("started_at").
+		From("line_game_level_attempts").
+		Where(squirrel.Eq{"user_id": userID, "passed_count": passedCount}).
+		ToSql()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("build query: %w", err)
+	}
+	var startedAt time.Time
+	if err = s.pool.QueryRow(ctx, q, args...).Scan(&startedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, model.ErrLineGameLevelNotStarted
+		}
+		return time.Time{}, fmt.Errorf("exec query: %w", err)
+	}
+	return startedAt, nil
+}
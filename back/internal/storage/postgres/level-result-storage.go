@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type LineGameLevelResultStorage struct {
+	pool *pgxpool.Pool
+	psql squirrel.StatementBuilderType
+}
+
+func NewLineGameLevelResultStorage(pool *pgxpool.Pool) *LineGameLevelResultStorage {
+	return &LineGameLevelResultStorage{
+		pool: pool,
+		psql: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// AddLevelResult saves the result of the completion with the passed count, a repeated completion replaces it.
+func (s *LineGameLevelResultStorage) AddLevelResult(
+	ctx context.Context,
+	userID uuid.UUID,
+	passedCount int,
+	result model.LineGameLevelResult,
+) error {
+	q, args, err := s.psql.
+		Insert("line_game_level_results").
+		Columns(
+			"user_id", "passed_count", "group_code", "level_num", "cells",
+			"time_since_start", "hints_count", "policy", "progression",
+		).
+		Values(
+			userID, passedCount, result.GroupCode, result.LevelNum, result.Cells,
+			int(result.TimeSinceStart.Seconds()), result.HintsCount, result.Policy, result.Progression,
+		).
+		Suffix(
+			"ON CONFLICT (user_id, passed_count) DO UPDATE SET " +
+				"group_code = EXCLUDED.group_code, level_num = EXCLUDED.level_num, cells = EXCLUDED.cells, " +
+				"time_since_start = EXCLUDED.time_since_start, hints_count = EXCLUDED.hints_count, " +
+				"policy = EXCLUDED.policy, progression = EXCLUDED.progression, completed_at = CURRENT_TIMESTAMP",
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert: %w", err)
+	}
+	if _, err = s.pool.Exec(ctx, q, args...); err != nil {
+		return fmt.Errorf("exec insert: %w", err)
+	}
+	return nil
+}
+
+// GetRecentLevelResults returns the last results of the user in the group, the newest result is first.
+func (s *LineGameLevelResultStorage) GetRecentLevelResults(
+	ctx context.Context,
+	userID uuid.UUID,
+	groupCode model.LineGameLevelGroupCode,
+	limit int,
+) ([]model.LineGameLevelResult, error) {
+	q, args, err := s.psql.
+		Select("group_code", "level_num", "cells", "time_since_start", "hints_count", "policy", "progression").
+		From("line_game_level_results").
+		Where(squirrel.Eq{"user_id": userID, "group_code": groupCode}).
+		OrderBy("passed_count DESC").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build query: %w", err)
+	}
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]model.LineGameLevelResult, 0, limit)
+	for rows.Next() {
+		var (
+			result         model.LineGameLevelResult
+			timeSinceStart int
+		)
+		if err = rows.Scan(
+			&result.GroupCode, &result.LevelNum, &result.Cells, &timeSinceStart,
+			&result.HintsCount, &result.Policy, &result.Progression,
+		); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		result.TimeSinceStart = time.Duration(timeSinceStart) * time.Second
+		results = append(results, result)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows err: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,509 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	logs "github.com/4units/mos-hack-game/back/pkg/logging"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type lineGameGroup struct {
+	FieldSize int             `json:"field_size"`
+	Orders    int             `json:"orders"`
+	Blockers  int             `json:"blockers"`
+	Levels    []lineGameLevel `json:"levels"`
+}
+
+type lineGameLevel struct {
+	FieldSize int            `json:"field_size"`
+	StartCell lineGameCell   `json:"start_cell"`
+	EndCell   lineGameCell   `json:"end_cell"`
+	Order     []lineGameCell `json:"order"`
+	Blockers  []lineGameCell `json:"blockers"`
+	// Answer is answers double slice with number-side quality:
+	// 0 - up, 1 - right, 2 - down, 3 - left, 4 - finish, 5 - block
+	Answer [][]int `json:"answer"`
+}
+
+type lineGameCell struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// LineGameLevelStorage serves published level groups from the in-memory catalog
+// which is loaded from the database on start, after publication and on changes made by other instances.
+type LineGameLevelStorage struct {
+	pool    *pgxpool.Pool
+	psql    squirrel.StatementBuilderType
+	catalog atomic.Pointer[model.LineGameCatalog]
+	// reloadMu serializes reloads from the watcher and from publication, so a slower reload which read
+	// groups before the publication does not replace the newer catalog
+	reloadMu     sync.Mutex
+	catalogState string
+}
+
+func NewLineGameLevelStorage(ctx context.Context, pool *pgxpool.Pool) (*LineGameLevelStorage, error) {
+	storage := &LineGameLevelStorage{
+		pool: pool,
+		psql: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+	if _, err := storage.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return storage, nil
+}
+
+// Watch polls the published groups and loads the catalog again when they are changed.
+func (s *LineGameLevelStorage) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := s.Reload(ctx)
+			if err != nil {
+				logs.Error("failed to reload line game levels", err)
+				continue
+			}
+			if reloaded {
+				slog.Info(
+					"line game levels are reloaded", slog.Int("groups", s.catalog.Load().GroupsCount()),
+				)
+			}
+		}
+	}
+}
+
+// Reload loads the catalog again if published groups are changed since the last call.
+func (s *LineGameLevelStorage) Reload(ctx context.Context) (bool, error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	state, err := s.getPublishedState(ctx)
+	if err != nil {
+		return false, err
+	}
+	if s.catalog.Load() != nil && s.catalogState == state {
+		return false, nil
+	}
+	groups, err := s.getPublishedGroups(ctx)
+	if err != nil {
+		return false, err
+	}
+	catalog, err := model.NewLineGameCatalog(groups)
+	if err != nil {
+		return false, fmt.Errorf("failed to build catalog: %w", err)
+	}
+	s.catalog.Store(catalog)
+	s.catalogState = state
+	return true, nil
+}
+
+func (s *LineGameLevelStorage) GetStartGroupCode(_ context.Context) (model.LineGameLevelGroupCode, error) {
+	groupCode, ok := s.catalog.Load().StartGroupCode()
+	if !ok {
+		return "", model.ErrLineGameNoFileWithLevelGroups
+	}
+	return groupCode, nil
+}
+
+func (s *LineGameLevelStorage) GetLevel(
+	_ context.Context,
+	groupCode model.LineGameLevelGroupCode,
+	levelNum int,
+) (model.LineGameLevel, error) {
+	return s.catalog.Load().Level(groupCode, levelNum)
+}
+
+func (s *LineGameLevelStorage) GetNextLevel(
+	_ context.Context,
+	currentGroupCode model.LineGameLevelGroupCode,
+	currentLevelNum int,
+) (model.LineGameLevelGroupCode, int, error) {
+	return s.catalog.Load().NextLevel(currentGroupCode, currentLevelNum)
+}
+
+func (s *LineGameLevelStorage) GetNextGroupLevel(
+	_ context.Context,
+	currentGroupCode model.LineGameLevelGroupCode,
+) (model.LineGameLevelGroupCode, int, error) {
+	return s.catalog.Load().NextGroupLevel(currentGroupCode)
+}
+
+func (s *LineGameLevelStorage) GetClosestLowOrDefaultLevel(
+	_ context.Context,
+	groupCode model.LineGameLevelGroupCode,
+	num int,
+) (model.LineGameLevelGroupCode, int, error) {
+	closestCode, closestNum, ok := s.catalog.Load().ClosestLevel(groupCode, num)
+	if !ok {
+		return "", 0, model.ErrLineGameNoFileWithLevelGroups
+	}
+	return closestCode, closestNum, nil
+}
+
+func (s *LineGameLevelStorage) SaveGroupDraft(
+	ctx context.Context,
+	userID uuid.UUID,
+	group model.LineGameLevelGroup,
+) error {
+	content, err := json.Marshal(newLineGameGroup(group))
+	if err != nil {
+		return fmt.Errorf("marshal group: %w", err)
+	}
+	q, args, err := s.psql.
+		Insert("line_game_level_group_drafts").
+		Columns("group_code", "content", "uploaded_by", "uploaded_at").
+		Values(string(group.GetCode()), content, userID, squirrel.Expr("CURRENT_TIMESTAMP")).
+		Suffix(
+			"ON CONFLICT (group_code) DO UPDATE SET content = EXCLUDED.content, " +
+				"uploaded_by = EXCLUDED.uploaded_by, uploaded_at = EXCLUDED.uploaded_at",
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert: %w", err)
+	}
+	if _, err = s.pool.Exec(ctx, q, args...); err != nil {
+		return fmt.Errorf("exec insert: %w", err)
+	}
+	return nil
+}
+
+func (s *LineGameLevelStorage) GetGroupDraft(
+	ctx context.Context,
+	groupCode model.LineGameLevelGroupCode,
+) (model.LineGameLevelGroup, error) {
+	return s.getGroupDraft(ctx, s.pool, groupCode)
+}
+
+func (s *LineGameLevelStorage) GetPublishedGroup(
+	_ context.Context,
+	groupCode model.LineGameLevelGroupCode,
+) (model.LineGameLevelGroup, error) {
+	group, ok := s.catalog.Load().Group(groupCode)
+	if !ok {
+		return model.LineGameLevelGroup{}, model.ErrLineGameNotExistsLevelInStorage
+	}
+	return group, nil
+}
+
+// PublishGroup replaces published levels of the group with its draft and reloads the catalog.
+func (s *LineGameLevelStorage) PublishGroup(
+	ctx context.Context,
+	userID uuid.UUID,
+	groupCode model.LineGameLevelGroupCode,
+) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	group, err := s.getGroupDraft(ctx, tx, groupCode)
+	if err != nil {
+		return err
+	}
+
+	q, args, err := s.psql.
+		Insert("line_game_level_groups").
+		Columns("group_code", "field_size", "orders", "blockers", "published_by", "published_at").
+		Values(
+			string(groupCode), group.FieldSize, group.Order, group.Blockers, userID,
+			squirrel.Expr("CURRENT_TIMESTAMP"),
+		).
+		Suffix(
+			"ON CONFLICT (group_code) DO UPDATE SET published_by = EXCLUDED.published_by, " +
+				"published_at = EXCLUDED.published_at",
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build groups insert: %w", err)
+	}
+	if _, err = tx.Exec(ctx, q, args...); err != nil {
+		return fmt.Errorf("exec groups insert: %w", err)
+	}
+
+	q, args, err = s.psql.
+		Delete("line_game_levels").
+		Where(squirrel.Eq{"group_code": string(groupCode)}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build levels delete: %w", err)
+	}
+	if _, err = tx.Exec(ctx, q, args...); err != nil {
+		return fmt.Errorf("exec levels delete: %w", err)
+	}
+
+	insert := s.psql.
+		Insert("line_game_levels").
+		Columns("group_code", "level_num", "level")
+	for i, level := range group.Levels {
+		content, err := json.Marshal(newLineGameLevel(level))
+		if err != nil {
+			return fmt.Errorf("marshal level: %w", err)
+		}
+		insert = insert.Values(string(groupCode), i, content)
+	}
+	q, args, err = insert.ToSql()
+	if err != nil {
+		return fmt.Errorf("build levels insert: %w", err)
+	}
+	if _, err = tx.Exec(ctx, q, args...); err != nil {
+		return fmt.Errorf("exec levels insert: %w", err)
+	}
+
+	q, args, err = s.psql.
+		Delete("line_game_level_group_drafts").
+		Where(squirrel.Eq{"group_code": string(groupCode)}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build draft delete: %w", err)
+	}
+	if _, err = tx.Exec(ctx, q, args...); err != nil {
+		return fmt.Errorf("exec draft delete: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	if _, err = s.Reload(ctx); err != nil {
+		return fmt.Errorf("failed to reload catalog: %w", err)
+	}
+	return nil
+}
+
+func (s *LineGameLevelStorage) GetGroupsInfo(ctx context.Context) ([]model.LineGameLevelGroupInfo, error) {
+	q, args, err := s.psql.
+		Select("group_code", "jsonb_array_length(content->'levels')", "uploaded_at").
+		From("line_game_level_group_drafts").
+		OrderBy("group_code COLLATE \"C\"").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build query: %w", err)
+	}
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	var infoList []model.LineGameLevelGroupInfo
+	for rows.Next() {
+		var (
+			info      model.LineGameLevelGroupInfo
+			groupCode string
+		)
+		if err = rows.Scan(&groupCode, &info.LevelsCount, &info.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		info.Code = model.LineGameLevelGroupCode(groupCode)
+		info.Status = model.LineGameLevelGroupDraft
+		infoList = append(infoList, info)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows err: %w", err)
+	}
+
+	q, args, err = s.psql.
+		Select("g.group_code", "COUNT(l.level_num)", "g.published_at").
+		From("line_game_level_groups g").
+		LeftJoin("line_game_levels l ON l.group_code = g.group_code").
+		GroupBy("g.group_code", "g.published_at").
+		OrderBy("g.group_code COLLATE \"C\"").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build query: %w", err)
+	}
+	rows, err = s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			info      model.LineGameLevelGroupInfo
+			groupCode string
+		)
+		if err = rows.Scan(&groupCode, &info.LevelsCount, &info.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		info.Code = model.LineGameLevelGroupCode(groupCode)
+		info.Status = model.LineGameLevelGroupPublished
+		infoList = append(infoList, info)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows err: %w", err)
+	}
+	return infoList, nil
+}
+
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+func (s *LineGameLevelStorage) getGroupDraft(
+	ctx context.Context,
+	db queryRower,
+	groupCode model.LineGameLevelGroupCode,
+) (model.LineGameLevelGroup, error) {
+	q, args, err := s.psql.
+		Select("content").
+		From("line_game_level_group_drafts").
+		Where(squirrel.Eq{"group_code": string(groupCode)}).
+		ToSql()
+	if err != nil {
+		return model.LineGameLevelGroup{}, fmt.Errorf("build query: %w", err)
+	}
+	var content []byte
+	if err = db.QueryRow(ctx, q, args...).Scan(&content); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.LineGameLevelGroup{}, model.ErrLineGameGroupDraftDoesNotExist
+		}
+		return model.LineGameLevelGroup{}, fmt.Errorf("exec query: %w", err)
+	}
+	var group lineGameGroup
+	if err = json.Unmarshal(content, &group); err != nil {
+		return model.LineGameLevelGroup{}, fmt.Errorf("unmarshal group: %w", err)
+	}
+	return group.toModel(), nil
+}
+
+// getPublishedState describes published groups to detect changes made by any instance.
+func (s *LineGameLevelStorage) getPublishedState(ctx context.Context) (string, error) {
+	q, args, err := s.psql.
+		Select("COUNT(*)", "COALESCE(MAX(published_at), 'epoch')").
+		From("line_game_level_groups").
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("build query: %w", err)
+	}
+	var (
+		count       int
+		publishedAt time.Time
+	)
+	if err = s.pool.QueryRow(ctx, q, args...).Scan(&count, &publishedAt); err != nil {
+		return "", fmt.Errorf("exec query: %w", err)
+	}
+	return fmt.Sprintf("%v:%v", count, publishedAt.UnixNano()), nil
+}
+
+func (s *LineGameLevelStorage) getPublishedGroups(ctx context.Context) ([]model.LineGameLevelGroup, error) {
+	q, args, err := s.psql.
+		Select("g.group_code", "g.field_size", "g.orders", "g.blockers", "l.level").
+		From("line_game_level_groups g").
+		Join("line_game_levels l ON l.group_code = g.group_code").
+		OrderBy("g.group_code COLLATE \"C\"", "l.level_num").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build query: %w", err)
+	}
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []model.LineGameLevelGroup
+	for rows.Next() {
+		var (
+			groupCode string
+			group     model.LineGameLevelGroup
+			rawLevel  []byte
+			level     lineGameLevel
+		)
+		if err = rows.Scan(&groupCode, &group.FieldSize, &group.Order, &group.Blockers, &rawLevel); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		if err = json.Unmarshal(rawLevel, &level); err != nil {
+			return nil, fmt.Errorf("unmarshal level of group %s: %w", groupCode, err)
+		}
+		if len(groups) == 0 || groups[len(groups)-1].GetCode() != group.GetCode() {
+			groups = append(groups, group)
+		}
+		last := &groups[len(groups)-1]
+		last.Levels = append(last.Levels, level.toModel())
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows err: %w", err)
+	}
+	return groups, nil
+}
+
+func newLineGameGroup(group model.LineGameLevelGroup) lineGameGroup {
+	rawGroup := lineGameGroup{
+		FieldSize: group.FieldSize,
+		Orders:    group.Order,
+		Blockers:  group.Blockers,
+		Levels:    make([]lineGameLevel, 0, len(group.Levels)),
+	}
+	for _, level := range group.Levels {
+		rawGroup.Levels = append(rawGroup.Levels, newLineGameLevel(level))
+	}
+	return rawGroup
+}
+
+func newLineGameLevel(level model.LineGameLevel) lineGameLevel {
+	rawLevel := lineGameLevel{
+		FieldSize: level.FieldSize,
+		StartCell: lineGameCell{X: level.Start.X, Y: level.Start.Y},
+		EndCell:   lineGameCell{X: level.End.X, Y: level.End.Y},
+		Order:     make([]lineGameCell, 0, len(level.Order)),
+		Blockers:  make([]lineGameCell, 0, len(level.Blockers)),
+		Answer:    level.Answer,
+	}
+	for _, cell := range level.Order {
+		rawLevel.Order = append(rawLevel.Order, lineGameCell{X: cell.X, Y: cell.Y})
+	}
+	for _, cell := range level.Blockers {
+		rawLevel.Blockers = append(rawLevel.Blockers, lineGameCell{X: cell.X, Y: cell.Y})
+	}
+	return rawLevel
+}
+
+func (g lineGameGroup) toModel() model.LineGameLevelGroup {
+	group := model.LineGameLevelGroup{
+		FieldSize: g.FieldSize,
+		Order:     g.Orders,
+		Blockers:  g.Blockers,
+		Levels:    make([]model.LineGameLevel, 0, len(g.Levels)),
+	}
+	for _, level := range g.Levels {
+		group.Levels = append(group.Levels, level.toModel())
+	}
+	return group
+}
+
+func (l lineGameLevel) toModel() model.LineGameLevel {
+	level := model.LineGameLevel{
+		FieldSize: l.FieldSize,
+		Start:     model.LineGameLevelCell{X: l.StartCell.X, Y: l.StartCell.Y},
+		End:       model.LineGameLevelCell{X: l.EndCell.X, Y: l.EndCell.Y},
+		Order:     make([]model.LineGameLevelCell, 0, len(l.Order)),
+		Blockers:  make([]model.LineGameLevelCell, 0, len(l.Blockers)),
+		Answer:    l.Answer,
+	}
+	for _, cell := range l.Order {
+		level.Order = append(level.Order, model.LineGameLevelCell{X: cell.X, Y: cell.Y})
+	}
+	for _, cell := range l.Blockers {
+		level.Blockers = append(level.Blockers, model.LineGameLevelCell{X: cell.X, Y: cell.Y})
+	}
+	return level
+}
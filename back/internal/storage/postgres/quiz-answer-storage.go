@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type QuizAnswerStorage struct {
+	pool *pgxpool.Pool
+	psql squirrel.StatementBuilderType
+}
+
+func NewQuizAnswerStorage(pool *pgxpool.Pool) *QuizAnswerStorage {
+	return &QuizAnswerStorage{
+		pool: pool,
+		psql: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// quizAnswerTimeExpr is a time in ms since the quiz was shown to the user last time. Answers given
+// later than in 30 minutes are not timed, the user has left the quiz then.
+const quizAnswerTimeExpr = `(
+	SELECT (EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - MAX(shown_at)) * 1000)::BIGINT
+	FROM quiz_shows
+	WHERE user_id = ? AND quiz_id = ? AND shown_at > CURRENT_TIMESTAMP - INTERVAL '30 minutes'
+)`
+
+// AddQuizShow records that the quiz was given to the user, the answer time is counted from it.
+func (s *QuizAnswerStorage) AddQuizShow(ctx context.Context, userID, quizID uuid.UUID) error {
+	q, args, err := s.psql.
+		Insert("quiz_shows").
+		Columns("user_id", "quiz_id").
+		Values(userID, quizID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert: %w", err)
+	}
+	if _, err = s.pool.Exec(ctx, q, args...); err != nil {
+		return fmt.Errorf("exec insert: %w", err)
+	}
+	return nil
+}
+
+// AddQuizAnswer saves the answer and returns true when it is the first correct answer of the user to the quiz.
+// The unique index of rewarded answers makes concurrent correct answers rewarded only once.
+func (s *QuizAnswerStorage) AddQuizAnswer(ctx context.Context, userID uuid.UUID, answer model.QuizAnswer) (bool, error) {
+	return addQuizAnswer(ctx, s.pool, s.psql, userID, answer)
+}
+
+type queryExecer interface {
+	queryRower
+	execer
+}
+
+func addQuizAnswer(
+	ctx context.Context,
+	db queryExecer,
+	psql squirrel.StatementBuilderType,
+	userID uuid.UUID,
+	answer model.QuizAnswer,
+) (bool, error) {
+	answerTime := squirrel.Expr(quizAnswerTimeExpr, userID, answer.QuizID)
+	// options are saved only for quizzes with several options in the answer, others have NULL
+	var answerOptions []byte
+	if answer.Answer.Options != nil {
+		var err error
+		if answerOptions, err = json.Marshal(answer.Answer.Options); err != nil {
+			return false, fmt.Errorf("marshal answer options: %w", err)
+		}
+	}
+	if answer.Correct {
+		q, args, err := psql.
+			Insert("quiz_answers").
+			Columns(
+				"user_id", "quiz_id", "answer", "answer_options", "answer_number", "correct", "rewarded",
+				"answer_time_ms",
+			).
+			Values(
+				userID, answer.QuizID, answer.Answer.Option, answerOptions, answer.Answer.Number, true, true,
+				answerTime,
+			).
+			Suffix("ON CONFLICT (user_id, quiz_id) WHERE rewarded DO NOTHING RETURNING answer_id").
+			ToSql()
+		if err != nil {
+			return false, fmt.Errorf("build insert: %w", err)
+		}
+		var answerID int64
+		err = db.QueryRow(ctx, q, args...).Scan(&answerID)
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return false, fmt.Errorf("exec insert: %w", err)
+		}
+	}
+	q, args, err := psql.
+		Insert("quiz_answers").
+		Columns("user_id", "quiz_id", "answer", "answer_options", "answer_number", "correct", "answer_time_ms").
+		Values(
+			userID, answer.QuizID, answer.Answer.Option, answerOptions, answer.Answer.Number, answer.Correct,
+			answerTime,
+		).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("build insert: %w", err)
+	}
+	if _, err = db.Exec(ctx, q, args...); err != nil {
+		return false, fmt.Errorf("exec insert: %w", err)
+	}
+	return false, nil
+}
+
+// CountQuizAnswers returns a count of saved answers of the user to the quiz.
+func (s *QuizAnswerStorage) CountQuizAnswers(ctx context.Context, userID, quizID uuid.UUID) (int, error) {
+	q, args, err := s.psql.
+		Select("COUNT(*)").
+		From("quiz_answers").
+		Where(squirrel.Eq{"user_id": userID, "quiz_id": quizID}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build query: %w", err)
+	}
+	var count int
+	if err = s.pool.QueryRow(ctx, q, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("exec query: %w", err)
+	}
+	return count, nil
+}
+
+// IsQuizRewarded returns true when an answer of the user to the quiz was rewarded.
+func (s *QuizAnswerStorage) IsQuizRewarded(ctx context.Context, userID, quizID uuid.UUID) (bool, error) {
+	q, args, err := s.psql.
+		Select("COUNT(*) > 0").
+		From("quiz_answers").
+		Where(squirrel.Eq{"user_id": userID, "quiz_id": quizID, "rewarded": true}).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("build query: %w", err)
+	}
+	var rewarded bool
+	if err = s.pool.QueryRow(ctx, q, args...).Scan(&rewarded); err != nil {
+		return false, fmt.Errorf("exec query: %w", err)
+	}
+	return rewarded, nil
+}
+
+// GetAnsweredQuizIDs returns ids of quizzes answered by the user at least once.
+func (s *QuizAnswerStorage) GetAnsweredQuizIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	q, args, err := s.psql.
+		Select("DISTINCT quiz_id").
+		From("quiz_answers").
+		Where(squirrel.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build query: %w", err)
+	}
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	quizIDs := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var quizID uuid.UUID
+		if err = rows.Scan(&quizID); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		quizIDs = append(quizIDs, quizID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows err: %w", err)
+	}
+	return quizIDs, nil
+}
+
+// GetQuizAnswers returns the last answers of the user, the newest answer is first.
+func (s *QuizAnswerStorage) GetQuizAnswers(ctx context.Context, userID uuid.UUID, limit int) ([]model.QuizAnswer, error) {
+	q, args, err := s.psql.
+		Select("quiz_id", "answer", "answer_options", "answer_number", "correct", "rewarded", "answered_at").
+		From("quiz_answers").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("answered_at DESC", "answer_id DESC").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build query: %w", err)
+	}
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	answers := make([]model.QuizAnswer, 0)
+	for rows.Next() {
+		var (
+			answer        model.QuizAnswer
+			answerOptions []byte
+		)
+		if err = rows.Scan(
+			&answer.QuizID, &answer.Answer.Option, &answerOptions, &answer.Answer.Number, &answer.Correct,
+			&answer.Rewarded, &answer.AnsweredAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		if answerOptions != nil {
+			if err = json.Unmarshal(answerOptions, &answer.Answer.Options); err != nil {
+				return nil, fmt.Errorf("unmarshal answer options: %w", err)
+			}
+		}
+		answers = append(answers, answer)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows err: %w", err)
+	}
+	return answers, nil
+}
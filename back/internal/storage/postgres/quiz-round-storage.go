@@ -0,0 +1,251 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type QuizRoundStorage struct {
+	pool *pgxpool.Pool
+	psql squirrel.StatementBuilderType
+}
+
+func NewQuizRoundStorage(pool *pgxpool.Pool) *QuizRoundStorage {
+	return &QuizRoundStorage{
+		pool: pool,
+		psql: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+type quizRoundAnswerJSON struct {
+	QuizID uuid.UUID `json:"quiz_id"`
+	// Answer is the chosen option, answers to quizzes of other types are in Options and Number
+	Answer   int      `json:"answer"`
+	Options  []int    `json:"options,omitempty"`
+	Number   *float64 `json:"number,omitempty"`
+	TimedOut bool     `json:"timed_out"`
+	Correct  bool     `json:"correct"`
+	TimeMs   int64    `json:"time_ms"`
+	Reward   int      `json:"reward"`
+}
+
+// AddQuizRound saves a new round and returns its id, the unique index allows one round in progress per user.
+func (s *QuizRoundStorage) AddQuizRound(ctx context.Context, round model.QuizRound) (uuid.UUID, error) {
+	quizIDsJSON, err := json.Marshal(round.QuizIDs)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("marshal quiz ids: %w", err)
+	}
+	q, args, err := s.psql.
+		Insert("quiz_rounds").
+		Columns(
+			"user_id", "category", "difficulty", "quiz_ids", "question_time_limit", "question_started_at",
+			"started_at",
+		).
+		Values(
+			round.UserID, round.Category, round.Difficulty, quizIDsJSON, int(round.QuestionTimeLimit.Seconds()),
+			round.QuestionStartedAt, round.StartedAt,
+		).
+		Suffix("RETURNING round_id").
+		ToSql()
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("build insert: %w", err)
+	}
+	var roundID uuid.UUID
+	if err = s.pool.QueryRow(ctx, q, args...).Scan(&roundID); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return uuid.Nil, model.ErrQuizRoundInProgress
+		}
+		return uuid.Nil, fmt.Errorf("exec insert: %w", err)
+	}
+	return roundID, nil
+}
+
+// GetQuizRoundInProgress returns the round of the user which is not finished.
+func (s *QuizRoundStorage) GetQuizRoundInProgress(ctx context.Context, userID uuid.UUID) (model.QuizRound, error) {
+	q, args, err := s.psql.
+		Select(
+			"round_id", "user_id", "category", "difficulty", "quiz_ids", "answers", "question_time_limit",
+			"question_started_at", "started_at", "finished_at", "reward",
+		).
+		From("quiz_rounds").
+		Where(squirrel.Eq{"user_id": userID, "finished_at": nil}).
+		ToSql()
+	if err != nil {
+		return model.QuizRound{}, fmt.Errorf("build query: %w", err)
+	}
+	var (
+		round             model.QuizRound
+		rawQuizIDs        []byte
+		rawAnswers        []byte
+		questionTimeLimit int
+		finishedAt        sql.NullTime
+	)
+	if err = s.pool.QueryRow(ctx, q, args...).Scan(
+		&round.ID, &round.UserID, &round.Category, &round.Difficulty, &rawQuizIDs, &rawAnswers,
+		&questionTimeLimit, &round.QuestionStartedAt, &round.StartedAt, &finishedAt, &round.Reward,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.QuizRound{}, model.ErrQuizRoundNotExists
+		}
+		return model.QuizRound{}, fmt.Errorf("exec query: %w", err)
+	}
+	round.QuestionTimeLimit = time.Duration(questionTimeLimit) * time.Second
+	round.FinishedAt = finishedAt.Time
+	if err = json.Unmarshal(rawQuizIDs, &round.QuizIDs); err != nil {
+		return model.QuizRound{}, fmt.Errorf("unmarshal quiz ids: %w", err)
+	}
+	var answers []quizRoundAnswerJSON
+	if err = json.Unmarshal(rawAnswers, &answers); err != nil {
+		return model.QuizRound{}, fmt.Errorf("unmarshal answers: %w", err)
+	}
+	round.Answers = make([]model.QuizRoundAnswer, 0, len(answers))
+	for _, answer := range answers {
+		round.Answers = append(
+			round.Answers, model.QuizRoundAnswer{
+				QuizID: answer.QuizID,
+				Answer: model.QuizAnswerValue{
+					Option:  answer.Answer,
+					Options: answer.Options,
+					Number:  answer.Number,
+				},
+				TimedOut: answer.TimedOut,
+				Correct:  answer.Correct,
+				Time:     time.Duration(answer.TimeMs) * time.Millisecond,
+				Reward:   answer.Reward,
+			},
+		)
+	}
+	return round, nil
+}
+
+// UpdateQuizRound saves answers of the round in progress. The round is updated only when it still has
+// the count of answers which it had when it was read, otherwise model.ErrQuizRoundChanged is returned.
+// The reward of the finished round is credited to the balance in the same transaction.
+func (s *QuizRoundStorage) UpdateQuizRound(ctx context.Context, round model.QuizRound, answeredBefore int) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err = s.updateQuizRound(ctx, tx, round, answeredBefore); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// AnswerQuizRound adds the answer to the quiz history and saves the round completed with it in one transaction,
+// so the answer is not rewarded in the history when the round was changed by a concurrent request.
+func (s *QuizRoundStorage) AnswerQuizRound(
+	ctx context.Context,
+	userID uuid.UUID,
+	answer model.QuizAnswer,
+	answeredBefore int,
+	complete func(rewarded bool) model.QuizRound,
+) (bool, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rewarded, err := addQuizAnswer(ctx, tx, s.psql, userID, answer)
+	if err != nil {
+		return false, err
+	}
+	if err = s.updateQuizRound(ctx, tx, complete(rewarded), answeredBefore); err != nil {
+		return false, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit tx: %w", err)
+	}
+	return rewarded, nil
+}
+
+// updateQuizRound saves the round when it has the answers count and credits the reward of the finished round.
+func (s *QuizRoundStorage) updateQuizRound(
+	ctx context.Context,
+	tx pgx.Tx,
+	round model.QuizRound,
+	answeredBefore int,
+) error {
+	answers := make([]quizRoundAnswerJSON, 0, len(round.Answers))
+	for _, answer := range round.Answers {
+		answers = append(
+			answers, quizRoundAnswerJSON{
+				QuizID:   answer.QuizID,
+				Answer:   answer.Answer.Option,
+				Options:  answer.Answer.Options,
+				Number:   answer.Answer.Number,
+				TimedOut: answer.TimedOut,
+				Correct:  answer.Correct,
+				TimeMs:   answer.Time.Milliseconds(),
+				Reward:   answer.Reward,
+			},
+		)
+	}
+	answersJSON, err := json.Marshal(answers)
+	if err != nil {
+		return fmt.Errorf("marshal answers: %w", err)
+	}
+	var finishedAt *time.Time
+	if !round.FinishedAt.IsZero() {
+		finishedAt = &round.FinishedAt
+	}
+	q, args, err := s.psql.
+		Update("quiz_rounds").
+		SetMap(
+			map[string]any{
+				"answers":             answersJSON,
+				"question_started_at": round.QuestionStartedAt,
+				"finished_at":         finishedAt,
+				"reward":              round.Reward,
+			},
+		).
+		Where(squirrel.Eq{"round_id": round.ID, "finished_at": nil}).
+		Where("jsonb_array_length(answers) = ?", answeredBefore).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build update: %w", err)
+	}
+	ct, err := tx.Exec(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("exec update: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return model.ErrQuizRoundChanged
+	}
+	if finishedAt == nil || round.Reward == 0 {
+		return nil
+	}
+
+	q, args, err = s.psql.
+		Update("user_balance").
+		Set("soft_currency", squirrel.Expr("soft_currency + ?", round.Reward)).
+		Where(squirrel.Eq{"user_id": round.UserID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build balance update: %w", err)
+	}
+	if ct, err = tx.Exec(ctx, q, args...); err != nil {
+		return fmt.Errorf("exec balance update: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return model.ErrBalanceNotExists
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+const (
+	quizShowsStatsJoin = "(SELECT quiz_id, COUNT(*) AS shown FROM quiz_shows GROUP BY quiz_id) AS shows " +
+		"USING (quiz_id)"
+	quizAnswersStatsJoin = "(SELECT quiz_id, COUNT(*) AS answered, " +
+		"COUNT(*) FILTER (WHERE correct) AS answered_correct, AVG(answer_time_ms)::FLOAT8 AS average_time_ms " +
+		"FROM quiz_answers GROUP BY quiz_id) AS stats USING (quiz_id)"
+	// quizAnswerOptionsFrom has chosen options of answers, options of multiple choice answers are counted
+	// one by one, the order of ordering answers is not counted
+	quizAnswerOptionsFrom = "(SELECT quiz_id, answer AS option FROM quiz_answers WHERE answer > 0 " +
+		"UNION ALL SELECT quiz_id, jsonb_array_elements(answer_options)::INT FROM quiz_answers " +
+		"JOIN quiz USING (quiz_id) WHERE type = 'multiple_choice') AS options"
+)
+
+// quizStatsOrder are SQL expressions of quiz stats sorts
+var quizStatsOrder = map[model.QuizStatsSort]string{
+	model.QuizStatsSortCorrectRate: "COALESCE(stats.answered_correct, 0)::FLOAT8 / NULLIF(stats.answered, 0)",
+	model.QuizStatsSortAverageTime: "stats.average_time_ms",
+	model.QuizStatsSortAnswers:     "COALESCE(stats.answered, 0)",
+	model.QuizStatsSortShown:       "COALESCE(shows.shown, 0)",
+}
+
+// quizStatsRow scans stats columns after columns of the quiz, so scanQuiz is used for stats rows
+type quizStatsRow struct {
+	pgx.Row
+	stats []any
+}
+
+func (r quizStatsRow) Scan(dest ...any) error {
+	return r.Row.Scan(append(dest, r.stats...)...)
+}
+
+// GetQuizStats returns a page of quizzes with stats of their shows and answers. Quizzes without answers
+// are the last ones by the correct rate and the average time in both orders.
+func (s *QuizAnswerStorage) GetQuizStats(
+	ctx context.Context,
+	search model.QuizStatsSearch,
+) (model.QuizStatsPage, error) {
+	query := s.psql.
+		Select().
+		From("quiz").
+		LeftJoin(quizShowsStatsJoin).
+		LeftJoin(quizAnswersStatsJoin)
+	if search.Category != "" {
+		query = query.Where(squirrel.Eq{"category": search.Category})
+	}
+	if search.Status != "" {
+		query = query.Where(squirrel.Eq{"status": search.Status})
+	}
+	if search.MinAnswers > 0 {
+		query = query.Where("COALESCE(stats.answered, 0) >= ?", search.MinAnswers)
+	}
+
+	q, args, err := query.Columns("COUNT(*)").ToSql()
+	if err != nil {
+		return model.QuizStatsPage{}, fmt.Errorf("build count query: %w", err)
+	}
+	var page model.QuizStatsPage
+	if err = s.pool.QueryRow(ctx, q, args...).Scan(&page.Total); err != nil {
+		return model.QuizStatsPage{}, fmt.Errorf("exec count query: %w", err)
+	}
+
+	order, ok := quizStatsOrder[search.Sort]
+	if !ok {
+		order = quizStatsOrder[model.QuizStatsSortCorrectRate]
+	}
+	direction := "ASC"
+	if search.Desc {
+		direction = "DESC"
+	}
+	q, args, err = query.
+		Columns(quizColumns...).
+		Columns(
+			"COALESCE(shows.shown, 0)", "COALESCE(stats.answered, 0)", "COALESCE(stats.answered_correct, 0)",
+			"stats.average_time_ms",
+		).
+		OrderBy(fmt.Sprintf("%s %s NULLS LAST", order, direction), "created_at DESC", "quiz_id").
+		Limit(uint64(search.Limit)).
+		Offset(uint64(search.Offset)).
+		ToSql()
+	if err != nil {
+		return model.QuizStatsPage{}, fmt.Errorf("build query: %w", err)
+	}
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return model.QuizStatsPage{}, fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	page.Stats = make([]model.QuizStats, 0)
+	for rows.Next() {
+		var (
+			stats         model.QuizStats
+			averageTimeMs *float64
+		)
+		stats.Quiz, err = scanQuiz(
+			quizStatsRow{
+				Row:   rows,
+				stats: []any{&stats.Shown, &stats.Answers, &stats.Correct, &averageTimeMs},
+			},
+		)
+		if err != nil {
+			return model.QuizStatsPage{}, err
+		}
+		if averageTimeMs != nil {
+			stats.AverageTime = time.Duration(*averageTimeMs * float64(time.Millisecond))
+		}
+		stats.AnswerCounts = make([]int, len(stats.Quiz.Answers))
+		page.Stats = append(page.Stats, stats)
+	}
+	if err = rows.Err(); err != nil {
+		return model.QuizStatsPage{}, fmt.Errorf("rows err: %w", err)
+	}
+	if err = s.addQuizAnswerCounts(ctx, page.Stats); err != nil {
+		return model.QuizStatsPage{}, err
+	}
+	return page, nil
+}
+
+// addQuizAnswerCounts counts answers by options, answers which are not options of the quiz are not counted.
+func (s *QuizAnswerStorage) addQuizAnswerCounts(ctx context.Context, stats []model.QuizStats) error {
+	if len(stats) == 0 {
+		return nil
+	}
+	statsIndexes := make(map[uuid.UUID]int, len(stats))
+	quizIDs := make([]uuid.UUID, 0, len(stats))
+	for i, quizStats := range stats {
+		statsIndexes[quizStats.Quiz.ID] = i
+		quizIDs = append(quizIDs, quizStats.Quiz.ID)
+	}
+	q, args, err := s.psql.
+		Select("quiz_id", "option", "COUNT(*)").
+		From(quizAnswerOptionsFrom).
+		Where(squirrel.Eq{"quiz_id": quizIDs}).
+		GroupBy("quiz_id", "option").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build query: %w", err)
+	}
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			quizID uuid.UUID
+			option int
+			count  int
+		)
+		if err = rows.Scan(&quizID, &option, &count); err != nil {
+			return fmt.Errorf("scan row: %w", err)
+		}
+		answerCounts := stats[statsIndexes[quizID]].AnswerCounts
+		if option >= 1 && option <= len(answerCounts) {
+			answerCounts[option-1] = count
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("rows err: %w", err)
+	}
+	return nil
+}
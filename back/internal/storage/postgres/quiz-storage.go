@@ -12,6 +12,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"net/http"
+	"strings"
 )
 
 var (
@@ -30,10 +31,35 @@ func NewQuizStorage(pool *pgxpool.Pool) *QuizStorage {
 	}
 }
 
-func (q *QuizStorage) GetAllQuiz(ctx context.Context) ([]model.Quiz, error) {
+var quizColumns = []string{
+	"quiz_id", "question", "answers", "correct_answer", "info_link", "answer_description",
+	"category", "difficulty", "tags", "status", "author_id", "created_at", "version", "type", "correct_answers",
+	"numeric_answer", "numeric_tolerance",
+}
+
+// quizContentJSON is a snapshot of quiz contents saved in the edit history
+type quizContentJSON struct {
+	// Type is empty in versions saved before quiz types, they are single choice quizzes
+	Type              model.QuizType `json:"type,omitempty"`
+	Question          string         `json:"question"`
+	Answers           []string       `json:"answers"`
+	CorrectAnswer     int            `json:"correct_answer"`
+	CorrectAnswers    []int          `json:"correct_answers,omitempty"`
+	NumericAnswer     float64        `json:"numeric_answer,omitempty"`
+	NumericTolerance  float64        `json:"numeric_tolerance,omitempty"`
+	InfoLink          string         `json:"info_link"`
+	AnswerDescription string         `json:"answer_description"`
+	Category          string         `json:"category"`
+	Difficulty        string         `json:"difficulty"`
+	Tags              []string       `json:"tags"`
+}
+
+// GetQuizIndex returns ids of published quizzes with their categories and difficulties without their contents.
+func (q *QuizStorage) GetQuizIndex(ctx context.Context) ([]model.QuizIndexEntry, error) {
 	sql, args, err := q.psql.
-		Select("quiz_id", "question", "answers", "correct_answer", "info_link", "answer_description").
+		Select("quiz_id", "category", "difficulty").
 		From("quiz").
+		Where(squirrel.Eq{"status": model.QuizStatusPublished}).
 		OrderBy("quiz_id").
 		ToSql()
 	if err != nil {
@@ -46,32 +72,24 @@ func (q *QuizStorage) GetAllQuiz(ctx context.Context) ([]model.Quiz, error) {
 	}
 	defer rows.Close()
 
-	var quizList []model.Quiz
+	entries := make([]model.QuizIndexEntry, 0)
 	for rows.Next() {
-		var (
-			quiz   model.Quiz
-			rawAns []byte
-		)
-		if err = rows.Scan(
-			&quiz.ID, &quiz.Question, &rawAns, &quiz.CorrectAnswer, &quiz.InfoLink, &quiz.AnswerDescription,
-		); err != nil {
+		var entry model.QuizIndexEntry
+		if err = rows.Scan(&entry.ID, &entry.Category, &entry.Difficulty); err != nil {
 			return nil, fmt.Errorf("scan row: %w", err)
 		}
-		if err = json.Unmarshal(rawAns, &quiz.Answers); err != nil {
-			return nil, fmt.Errorf("unmarshal answers: %w", err)
-		}
-		quizList = append(quizList, quiz)
+		entries = append(entries, entry)
 	}
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("rows err: %w", err)
 	}
 
-	return quizList, nil
+	return entries, nil
 }
 
 func (q *QuizStorage) GetQuizByID(ctx context.Context, quizID uuid.UUID) (model.Quiz, error) {
 	sql, args, err := q.psql.
-		Select("quiz_id", "question", "answers", "correct_answer", "info_link", "answer_description").
+		Select(quizColumns...).
 		From("quiz").
 		Where(squirrel.Eq{"quiz_id": quizID}).
 		ToSql()
@@ -79,89 +97,547 @@ func (q *QuizStorage) GetQuizByID(ctx context.Context, quizID uuid.UUID) (model.
 		return model.Quiz{}, fmt.Errorf("build query: %w", err)
 	}
 
+	quiz, err := scanQuiz(q.pool.QueryRow(ctx, sql, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Quiz{}, ErrQuizDoesNotExist
+		}
+		return model.Quiz{}, err
+	}
+	return quiz, nil
+}
+
+// GetQuizzesByStatus returns quizzes with the status, the oldest quiz is first.
+func (q *QuizStorage) GetQuizzesByStatus(
+	ctx context.Context,
+	status model.QuizStatus,
+	limit int,
+) ([]model.Quiz, error) {
+	sql, args, err := q.psql.
+		Select(quizColumns...).
+		From("quiz").
+		Where(squirrel.Eq{"status": status}).
+		OrderBy("created_at", "quiz_id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build query: %w", err)
+	}
+	rows, err := q.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	quizzes := make([]model.Quiz, 0)
+	for rows.Next() {
+		quiz, err := scanQuiz(rows)
+		if err != nil {
+			return nil, err
+		}
+		quizzes = append(quizzes, quiz)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows err: %w", err)
+	}
+	return quizzes, nil
+}
+
+// SearchQuizzes returns a page of quizzes matching the search, the newest quiz is first.
+func (q *QuizStorage) SearchQuizzes(ctx context.Context, search model.QuizSearch) (model.QuizPage, error) {
+	where := squirrel.And{}
+	if search.Text != "" {
+		pattern := "%" + quizSearchEscaper.Replace(search.Text) + "%"
+		where = append(
+			where, squirrel.Or{
+				squirrel.ILike{"question": pattern},
+				squirrel.ILike{"answer_description": pattern},
+			},
+		)
+	}
+	if search.Category != "" {
+		where = append(where, squirrel.Eq{"category": search.Category})
+	}
+	if search.Status != "" {
+		where = append(where, squirrel.Eq{"status": search.Status})
+	}
+	if search.AuthorID != uuid.Nil {
+		where = append(where, squirrel.Eq{"author_id": search.AuthorID})
+	}
+
+	sql, args, err := q.psql.
+		Select("COUNT(*)").
+		From("quiz").
+		Where(where).
+		ToSql()
+	if err != nil {
+		return model.QuizPage{}, fmt.Errorf("build count query: %w", err)
+	}
+	page := model.QuizPage{Quizzes: make([]model.Quiz, 0)}
+	if err = q.pool.QueryRow(ctx, sql, args...).Scan(&page.Total); err != nil {
+		return model.QuizPage{}, fmt.Errorf("exec count query: %w", err)
+	}
+	if page.Total <= search.Offset {
+		return page, nil
+	}
+
+	sql, args, err = q.psql.
+		Select(quizColumns...).
+		From("quiz").
+		Where(where).
+		OrderBy("created_at DESC", "quiz_id").
+		Limit(uint64(search.Limit)).
+		Offset(uint64(search.Offset)).
+		ToSql()
+	if err != nil {
+		return model.QuizPage{}, fmt.Errorf("build query: %w", err)
+	}
+	rows, err := q.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return model.QuizPage{}, fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		quiz, err := scanQuiz(rows)
+		if err != nil {
+			return model.QuizPage{}, err
+		}
+		page.Quizzes = append(page.Quizzes, quiz)
+	}
+	if err = rows.Err(); err != nil {
+		return model.QuizPage{}, fmt.Errorf("rows err: %w", err)
+	}
+	return page, nil
+}
+
+// quizSearchEscaper makes wildcards of LIKE patterns in the search text plain characters
+var quizSearchEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func scanQuiz(row pgx.Row) (model.Quiz, error) {
 	var (
-		quiz   model.Quiz
-		rawAns []byte
+		quiz              model.Quiz
+		rawAns            []byte
+		rawTags           []byte
+		rawCorrectAnswers []byte
+		authorID          uuid.NullUUID
 	)
-	if err = q.pool.QueryRow(ctx, sql, args...).Scan(
+	if err := row.Scan(
 		&quiz.ID, &quiz.Question, &rawAns, &quiz.CorrectAnswer, &quiz.InfoLink, &quiz.AnswerDescription,
+		&quiz.Category, &quiz.Difficulty, &rawTags, &quiz.Status, &authorID, &quiz.CreatedAt, &quiz.Version,
+		&quiz.Type, &rawCorrectAnswers, &quiz.NumericAnswer, &quiz.NumericTolerance,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return model.Quiz{}, ErrQuizDoesNotExist
+			return model.Quiz{}, err
 		}
-		return model.Quiz{}, fmt.Errorf("exec query: %w", err)
+		return model.Quiz{}, fmt.Errorf("scan row: %w", err)
 	}
-	if err = json.Unmarshal(rawAns, &quiz.Answers); err != nil {
+	quiz.AuthorID = authorID.UUID
+	if err := json.Unmarshal(rawAns, &quiz.Answers); err != nil {
 		return model.Quiz{}, fmt.Errorf("unmarshal answers: %w", err)
 	}
-
+	if err := json.Unmarshal(rawTags, &quiz.Tags); err != nil {
+		return model.Quiz{}, fmt.Errorf("unmarshal tags: %w", err)
+	}
+	if err := json.Unmarshal(rawCorrectAnswers, &quiz.CorrectAnswers); err != nil {
+		return model.Quiz{}, fmt.Errorf("unmarshal correct answers: %w", err)
+	}
 	return quiz, nil
 }
 
-func (q *QuizStorage) AddQuiz(
-	ctx context.Context,
-	question string,
-	answers []string,
-	correctAnswer int,
-	linkInfo string,
-	answerDescription string,
-) error {
-	ansJSON, err := json.Marshal(answers)
+// AddQuiz saves the quiz with its first status transition and version made by the author
+// and returns the id of the quiz.
+func (q *QuizStorage) AddQuiz(ctx context.Context, quiz model.Quiz) (uuid.UUID, error) {
+	quizIDs, err := q.AddQuizzes(ctx, []model.Quiz{quiz})
 	if err != nil {
-		return fmt.Errorf("marshal answers: %w", err)
+		return uuid.Nil, err
+	}
+	return quizIDs[0], nil
+}
+
+// AddQuizzes saves all quizzes in one transaction like AddQuiz and returns their ids in the same order.
+func (q *QuizStorage) AddQuizzes(ctx context.Context, quizzes []model.Quiz) ([]uuid.UUID, error) {
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	quizIDs := make([]uuid.UUID, 0, len(quizzes))
+	for _, quiz := range quizzes {
+		quizID, err := q.addQuiz(ctx, tx, quiz)
+		if err != nil {
+			return nil, err
+		}
+		quizIDs = append(quizIDs, quizID)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return quizIDs, nil
+}
+
+func (q *QuizStorage) addQuiz(ctx context.Context, tx pgx.Tx, quiz model.Quiz) (uuid.UUID, error) {
+	ansJSON, err := json.Marshal(quiz.Answers)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("marshal answers: %w", err)
+	}
+	tagsJSON, err := marshalQuizList("tags", quiz.Tags)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	correctAnswersJSON, err := marshalQuizList("correct answers", quiz.CorrectAnswers)
+	if err != nil {
+		return uuid.Nil, err
 	}
 
 	sql, args, err := q.psql.
 		Insert("quiz").
-		Columns("question", "correct_answer", "answers", "info_link", "answer_description").
-		Values(question, correctAnswer, ansJSON, linkInfo, answerDescription).
+		Columns(
+			"question", "correct_answer", "answers", "info_link", "answer_description",
+			"category", "difficulty", "tags", "status", "author_id", "normalized_question",
+			"type", "correct_answers", "numeric_answer", "numeric_tolerance",
+		).
+		Values(
+			quiz.Question, quiz.CorrectAnswer, ansJSON, quiz.InfoLink, quiz.AnswerDescription,
+			quiz.Category, quiz.Difficulty, tagsJSON, quiz.Status, quiz.AuthorID,
+			model.NormalizeQuizQuestion(quiz.Question),
+			quiz.TypeOrDefault(), correctAnswersJSON, quiz.NumericAnswer, quiz.NumericTolerance,
+		).
+		Suffix("RETURNING quiz_id").
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("build insert: %w", err)
+		return uuid.Nil, fmt.Errorf("build insert: %w", err)
 	}
 
-	if _, err = q.pool.Exec(ctx, sql, args...); err != nil {
-		return fmt.Errorf("exec insert: %w", err)
+	var quizID uuid.UUID
+	if err = tx.QueryRow(ctx, sql, args...).Scan(&quizID); err != nil {
+		return uuid.Nil, fmt.Errorf("exec insert: %w", err)
 	}
-	return nil
+	if err = q.addQuizStatusTransition(
+		ctx, tx, model.QuizStatusTransition{
+			QuizID: quizID,
+			To:     quiz.Status,
+			UserID: quiz.AuthorID,
+		},
+	); err != nil {
+		return uuid.Nil, err
+	}
+	if err = q.addQuizVersion(ctx, tx, quizID, 1, quiz, quiz.AuthorID); err != nil {
+		return uuid.Nil, err
+	}
+	return quizID, nil
 }
-func (q *QuizStorage) UpdateQuiz(
+
+// GetQuizIDsByQuestions returns ids of not archived quizzes by their normalized questions.
+func (q *QuizStorage) GetQuizIDsByQuestions(
 	ctx context.Context,
-	quizID uuid.UUID,
-	question string,
-	answers []string,
-	correctAnswer int,
-	linkInfo string,
-	answerDescription string,
-) error {
-	json, err := json.Marshal(answers)
+	normalizedQuestions []string,
+) (map[string]uuid.UUID, error) {
+	quizIDs := make(map[string]uuid.UUID, len(normalizedQuestions))
+	if len(normalizedQuestions) == 0 {
+		return quizIDs, nil
+	}
+	sql, args, err := q.psql.
+		Select("normalized_question", "quiz_id").
+		From("quiz").
+		Where(squirrel.Eq{"normalized_question": normalizedQuestions}).
+		Where(squirrel.NotEq{"status": model.QuizStatusArchived}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build query: %w", err)
+	}
+	rows, err := q.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			question string
+			quizID   uuid.UUID
+		)
+		if err = rows.Scan(&question, &quizID); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		quizIDs[question] = quizID
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows err: %w", err)
+	}
+	return quizIDs, nil
+}
+
+// UpdateQuiz saves contents of the quiz as its next version made by the editor,
+// the status and the author are changed only by other methods. The quiz is updated only when it still has
+// the read status and version, otherwise model.ErrQuizChanged is returned.
+func (q *QuizStorage) UpdateQuiz(ctx context.Context, quiz model.Quiz, editorID uuid.UUID) error {
+	ansJSON, err := json.Marshal(quiz.Answers)
 	if err != nil {
 		return fmt.Errorf("marshal answers: %w", err)
 	}
+	tagsJSON, err := marshalQuizList("tags", quiz.Tags)
+	if err != nil {
+		return err
+	}
+	correctAnswersJSON, err := marshalQuizList("correct answers", quiz.CorrectAnswers)
+	if err != nil {
+		return err
+	}
 
 	sql, args, err := q.psql.
 		Update("quiz").
 		SetMap(
 			map[string]any{
-				"question":           question,
-				"correct_answer":     correctAnswer,
-				"answers":            json,
-				"info_link":          linkInfo,
-				"answer_description": answerDescription,
+				"question":            quiz.Question,
+				"correct_answer":      quiz.CorrectAnswer,
+				"answers":             ansJSON,
+				"info_link":           quiz.InfoLink,
+				"answer_description":  quiz.AnswerDescription,
+				"category":            quiz.Category,
+				"difficulty":          quiz.Difficulty,
+				"tags":                tagsJSON,
+				"version":             squirrel.Expr("version + 1"),
+				"normalized_question": model.NormalizeQuizQuestion(quiz.Question),
+				"type":                quiz.TypeOrDefault(),
+				"correct_answers":     correctAnswersJSON,
+				"numeric_answer":      quiz.NumericAnswer,
+				"numeric_tolerance":   quiz.NumericTolerance,
 			},
 		).
+		Where(squirrel.Eq{"quiz_id": quiz.ID, "status": quiz.Status, "version": quiz.Version}).
+		Suffix("RETURNING version").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build update: %w", err)
+	}
+
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var version int
+	if err = tx.QueryRow(ctx, sql, args...).Scan(&version); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ErrQuizChanged
+		}
+		return fmt.Errorf("exec update: %w", err)
+	}
+	if err = q.addQuizVersion(ctx, tx, quiz.ID, version, quiz, editorID); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func (q *QuizStorage) addQuizVersion(
+	ctx context.Context,
+	tx pgx.Tx,
+	quizID uuid.UUID,
+	version int,
+	quiz model.Quiz,
+	editorID uuid.UUID,
+) error {
+	contentJSON, err := json.Marshal(
+		quizContentJSON{
+			Type:              quiz.TypeOrDefault(),
+			Question:          quiz.Question,
+			Answers:           quiz.Answers,
+			CorrectAnswer:     quiz.CorrectAnswer,
+			CorrectAnswers:    quiz.CorrectAnswers,
+			NumericAnswer:     quiz.NumericAnswer,
+			NumericTolerance:  quiz.NumericTolerance,
+			InfoLink:          quiz.InfoLink,
+			AnswerDescription: quiz.AnswerDescription,
+			Category:          quiz.Category,
+			Difficulty:        quiz.Difficulty,
+			Tags:              quiz.Tags,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("marshal quiz content: %w", err)
+	}
+	sql, args, err := q.psql.
+		Insert("quiz_versions").
+		Columns("quiz_id", "version", "content", "user_id").
+		Values(quizID, version, contentJSON, editorID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build version insert: %w", err)
+	}
+	if _, err = tx.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("exec version insert: %w", err)
+	}
+	return nil
+}
+
+// GetQuizVersions returns all saved versions of the quiz, the newest version is first.
+func (q *QuizStorage) GetQuizVersions(ctx context.Context, quizID uuid.UUID) ([]model.QuizVersion, error) {
+	sql, args, err := q.psql.
+		Select("version", "content", "user_id", "created_at").
+		From("quiz_versions").
 		Where(squirrel.Eq{"quiz_id": quizID}).
+		OrderBy("version DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build query: %w", err)
+	}
+	rows, err := q.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]model.QuizVersion, 0)
+	for rows.Next() {
+		var (
+			version    model.QuizVersion
+			rawContent []byte
+			editorID   uuid.NullUUID
+			content    quizContentJSON
+		)
+		if err = rows.Scan(&version.Version, &rawContent, &editorID, &version.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		if err = json.Unmarshal(rawContent, &content); err != nil {
+			return nil, fmt.Errorf("unmarshal quiz content: %w", err)
+		}
+		version.EditorID = editorID.UUID
+		if content.Type == "" {
+			content.Type = model.QuizTypeSingleChoice
+		}
+		version.Quiz = model.Quiz{
+			ID:                quizID,
+			Type:              content.Type,
+			Question:          content.Question,
+			Answers:           content.Answers,
+			CorrectAnswer:     content.CorrectAnswer,
+			CorrectAnswers:    content.CorrectAnswers,
+			NumericAnswer:     content.NumericAnswer,
+			NumericTolerance:  content.NumericTolerance,
+			InfoLink:          content.InfoLink,
+			AnswerDescription: content.AnswerDescription,
+			Category:          content.Category,
+			Difficulty:        content.Difficulty,
+			Tags:              content.Tags,
+			Version:           version.Version,
+		}
+		versions = append(versions, version)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows err: %w", err)
+	}
+	return versions, nil
+}
+
+// ChangeQuizStatus sets the new status of the quiz and records the transition in one transaction.
+// The status is changed only when the quiz still has the previous status, otherwise
+// model.ErrQuizStatusChanged is returned.
+func (q *QuizStorage) ChangeQuizStatus(ctx context.Context, transition model.QuizStatusTransition) error {
+	sql, args, err := q.psql.
+		Update("quiz").
+		Set("status", transition.To).
+		Where(squirrel.Eq{"quiz_id": transition.QuizID, "status": transition.From}).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("build update: %w", err)
 	}
 
-	ct, err := q.pool.Exec(ctx, sql, args...)
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ct, err := tx.Exec(ctx, sql, args...)
 	if err != nil {
 		return fmt.Errorf("exec update: %w", err)
 	}
 	if ct.RowsAffected() == 0 {
-		return ErrQuizDoesNotExist
+		return model.ErrQuizStatusChanged
+	}
+	if err = q.addQuizStatusTransition(ctx, tx, transition); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func (q *QuizStorage) addQuizStatusTransition(
+	ctx context.Context,
+	tx pgx.Tx,
+	transition model.QuizStatusTransition,
+) error {
+	sql, args, err := q.psql.
+		Insert("quiz_status_transitions").
+		Columns("quiz_id", "from_status", "to_status", "user_id", "comment").
+		Values(transition.QuizID, transition.From, transition.To, transition.UserID, transition.Comment).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build transition insert: %w", err)
+	}
+	if _, err = tx.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("exec transition insert: %w", err)
 	}
 	return nil
 }
+
+// GetQuizStatusTransitions returns all status changes of the quiz, the oldest change is first.
+func (q *QuizStorage) GetQuizStatusTransitions(
+	ctx context.Context,
+	quizID uuid.UUID,
+) ([]model.QuizStatusTransition, error) {
+	sql, args, err := q.psql.
+		Select("quiz_id", "from_status", "to_status", "user_id", "comment", "created_at").
+		From("quiz_status_transitions").
+		Where(squirrel.Eq{"quiz_id": quizID}).
+		OrderBy("created_at", "transition_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build query: %w", err)
+	}
+	rows, err := q.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	transitions := make([]model.QuizStatusTransition, 0)
+	for rows.Next() {
+		var (
+			transition model.QuizStatusTransition
+			userID     uuid.NullUUID
+		)
+		if err = rows.Scan(
+			&transition.QuizID, &transition.From, &transition.To, &userID, &transition.Comment,
+			&transition.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		transition.UserID = userID.UUID
+		transitions = append(transitions, transition)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows err: %w", err)
+	}
+	return transitions, nil
+}
+
+// marshalQuizList marshals nil lists as empty ones for NOT NULL columns
+func marshalQuizList[T any](name string, list []T) ([]byte, error) {
+	if list == nil {
+		list = []T{}
+	}
+	listJSON, err := json.Marshal(list)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s: %w", name, err)
+	}
+	return listJSON, nil
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type LineGameStarStorage struct {
+	pool *pgxpool.Pool
+	psql squirrel.StatementBuilderType
+}
+
+func NewLineGameStarStorage(pool *pgxpool.Pool) *LineGameStarStorage {
+	return &LineGameStarStorage{
+		pool: pool,
+		psql: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// SaveLevelStars keeps the best count of stars and the best time of the level.
+// It returns the count of stars added over the previous best of the level.
+func (s *LineGameStarStorage) SaveLevelStars(
+	ctx context.Context,
+	userID uuid.UUID,
+	stars model.LineGameLevelStars,
+) (int, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	bestTime := int(stars.BestTime.Seconds())
+	// the row is created without stars first, so concurrent completions wait for each other on its lock
+	q, args, err := s.psql.
+		Insert("line_game_level_stars").
+		Columns("user_id", "group_code", "level_num", "stars", "best_time").
+		Values(userID, string(stars.GroupCode), stars.LevelNum, 0, bestTime).
+		Suffix("ON CONFLICT (user_id, group_code, level_num) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build insert: %w", err)
+	}
+	if _, err = tx.Exec(ctx, q, args...); err != nil {
+		return 0, fmt.Errorf("exec insert: %w", err)
+	}
+	where := squirrel.Eq{"user_id": userID, "group_code": string(stars.GroupCode), "level_num": stars.LevelNum}
+	q, args, err = s.psql.
+		Select("stars").
+		From("line_game_level_stars").
+		Where(where).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build query: %w", err)
+	}
+	var prevStars int
+	if err = tx.QueryRow(ctx, q, args...).Scan(&prevStars); err != nil {
+		return 0, fmt.Errorf("exec query: %w", err)
+	}
+	q, args, err = s.psql.
+		Update("line_game_level_stars").
+		Set("stars", squirrel.Expr("GREATEST(stars, ?)", stars.Stars)).
+		Set("best_time", squirrel.Expr("LEAST(best_time, ?)", bestTime)).
+		Set("updated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(where).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build update: %w", err)
+	}
+	if _, err = tx.Exec(ctx, q, args...); err != nil {
+		return 0, fmt.Errorf("exec update: %w", err)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+	return max(stars.Stars-prevStars, 0), nil
+}
+
+// GetLevelStars returns ratings of completed levels of the group or of all groups when the code is empty.
+func (s *LineGameStarStorage) GetLevelStars(
+	ctx context.Context,
+	userID uuid.UUID,
+	groupCode model.LineGameLevelGroupCode,
+) ([]model.LineGameLevelStars, error) {
+	builder := s.psql.
+		Select("group_code", "level_num", "stars", "best_time").
+		From("line_game_level_stars").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("group_code", "level_num")
+	if groupCode != "" {
+		builder = builder.Where(squirrel.Eq{"group_code": string(groupCode)})
+	}
+	q, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build query: %w", err)
+	}
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec query: %w", err)
+	}
+	defer rows.Close()
+
+	levels := make([]model.LineGameLevelStars, 0)
+	for rows.Next() {
+		var (
+			stars    model.LineGameLevelStars
+			bestTime int
+		)
+		if err = rows.Scan(&stars.GroupCode, &stars.LevelNum, &stars.Stars, &bestTime); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		stars.BestTime = time.Duration(bestTime) * time.Second
+		levels = append(levels, stars)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows err: %w", err)
+	}
+	return levels, nil
+}
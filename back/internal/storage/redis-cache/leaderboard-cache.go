@@ -0,0 +1,143 @@
+package redis_cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/config"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+// setIfCachedScript updates the score only in a loaded board, so a partial board is never read.
+var setIfCachedScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return redis.call("ZADD", KEYS[1], ARGV[1], ARGV[2])
+end
+return 0
+`)
+
+func NewClient(ctx context.Context, cfg config.Redis) (*redis.Client, error) {
+	opts, err := redis.ParseURL(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parse redis url: %w", err)
+	}
+	client := redis.NewClient(opts)
+	if err = client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("ping redis: %w", err)
+	}
+	return client, nil
+}
+
+// LeaderboardCache keeps boards in sorted sets. A board is loaded from the database on the first read
+// and expires after ttl, so changes missed while loading are fixed by the next load.
+type LeaderboardCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewLeaderboardCache(client *redis.Client, ttl time.Duration) *LeaderboardCache {
+	return &LeaderboardCache{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func (c *LeaderboardCache) SetScore(ctx context.Context, key model.LeaderboardKey, userID uuid.UUID, score int) error {
+	if err := setIfCachedScript.Run(ctx, c.client, []string{redisKey(key)}, score, userID.String()).Err(); err != nil {
+		return fmt.Errorf("run set score script: %w", err)
+	}
+	return nil
+}
+
+func (c *LeaderboardCache) LoadScores(ctx context.Context, key model.LeaderboardKey, entries []model.LeaderboardEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	members := make([]redis.Z, 0, len(entries))
+	for _, entry := range entries {
+		members = append(members, redis.Z{Score: float64(entry.Score), Member: entry.UserID.String()})
+	}
+	pipe := c.client.TxPipeline()
+	pipe.Del(ctx, redisKey(key))
+	pipe.ZAdd(ctx, redisKey(key), members...)
+	pipe.Expire(ctx, redisKey(key), c.ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("exec load pipeline: %w", err)
+	}
+	return nil
+}
+
+// GetTopScores returns best entries with ranks, false is returned when the board is not loaded.
+func (c *LeaderboardCache) GetTopScores(
+	ctx context.Context,
+	key model.LeaderboardKey,
+	limit int,
+) ([]model.LeaderboardEntry, bool, error) {
+	rangeArgs := redis.ZRangeArgs{
+		Key:   redisKey(key),
+		Start: 0,
+		Stop:  limit - 1,
+		Rev:   !key.LowerIsBetter,
+	}
+	members, err := c.client.ZRangeArgsWithScores(ctx, rangeArgs).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("get range: %w", err)
+	}
+	if len(members) == 0 {
+		return nil, false, nil
+	}
+	entries := make([]model.LeaderboardEntry, 0, len(members))
+	for _, member := range members {
+		userID, err := uuid.Parse(member.Member.(string))
+		if err != nil {
+			return nil, false, fmt.Errorf("parse member: %w", err)
+		}
+		entries = append(entries, model.LeaderboardEntry{UserID: userID, Score: int(member.Score)})
+	}
+	model.RankLeaderboardEntries(entries)
+	return entries, true, nil
+}
+
+// GetUserScore returns the entry of the user with a rank, false is returned when the board is not loaded.
+func (c *LeaderboardCache) GetUserScore(
+	ctx context.Context,
+	key model.LeaderboardKey,
+	userID uuid.UUID,
+) (model.LeaderboardEntry, bool, error) {
+	pipe := c.client.Pipeline()
+	existsCmd := pipe.Exists(ctx, redisKey(key))
+	scoreCmd := pipe.ZScore(ctx, redisKey(key), userID.String())
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return model.LeaderboardEntry{}, false, fmt.Errorf("exec score pipeline: %w", err)
+	}
+	if existsCmd.Val() == 0 {
+		return model.LeaderboardEntry{}, false, nil
+	}
+	score, err := scoreCmd.Result()
+	if errors.Is(err, redis.Nil) {
+		return model.LeaderboardEntry{}, true, model.ErrLeaderboardHasNoUserScore
+	}
+	if err != nil {
+		return model.LeaderboardEntry{}, false, fmt.Errorf("get score: %w", err)
+	}
+	minScore, maxScore := "("+strconv.Itoa(int(score)), "+inf"
+	if key.LowerIsBetter {
+		minScore, maxScore = "-inf", "("+strconv.Itoa(int(score))
+	}
+	better, err := c.client.ZCount(ctx, redisKey(key), minScore, maxScore).Result()
+	if err != nil {
+		return model.LeaderboardEntry{}, false, fmt.Errorf("count better scores: %w", err)
+	}
+	return model.LeaderboardEntry{Rank: int(better) + 1, UserID: userID, Score: int(score)}, true, nil
+}
+
+// redisKeyPrefix is changed with units of scores, boards cached with old keys expire by ttl
+const redisKeyPrefix = "leaderboard:v2:"
+
+func redisKey(key model.LeaderboardKey) string {
+	return redisKeyPrefix + key.Board + ":" + key.Period
+}
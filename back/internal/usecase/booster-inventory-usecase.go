@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+)
+
+type BoosterInventoryStorage interface {
+	GetBoosterInventory(ctx context.Context, userID uuid.UUID) (map[string]int, error)
+	AddBoosters(ctx context.Context, userID uuid.UUID, boosters map[string]int) error
+	// BuyBoosters must spend soft currency and add boosters atomically
+	BuyBoosters(ctx context.Context, userID uuid.UUID, price int, boosters map[string]int) error
+}
+
+type BoosterInventoryUsecaseDeps struct {
+	BoosterInventoryStorage BoosterInventoryStorage
+	BalanceUsecase          *BalanceUsecase
+	UserUsecase             *UserUsecase
+	BoostersConfigProvider
+}
+
+type BoosterInventoryUsecase struct {
+	BoosterInventoryUsecaseDeps
+}
+
+func NewBoosterInventoryUsecase(deps BoosterInventoryUsecaseDeps) *BoosterInventoryUsecase {
+	return &BoosterInventoryUsecase{
+		BoosterInventoryUsecaseDeps: deps,
+	}
+}
+
+// GetInventory returns counts of every booster of the catalog in the user inventory.
+func (b *BoosterInventoryUsecase) GetInventory(ctx context.Context, userID uuid.UUID) ([]model.BoosterStack, error) {
+	inventory, err := b.BoosterInventoryStorage.GetBoosterInventory(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booster inventory: %w", err)
+	}
+	items := b.BoostersConfig().Items
+	stacks := make([]model.BoosterStack, 0, len(items))
+	for _, booster := range items {
+		stacks = append(
+			stacks, model.BoosterStack{
+				BoosterID: booster.ID,
+				Effect:    booster.Effect,
+				Count:     inventory[booster.ID],
+			},
+		)
+	}
+	return stacks, nil
+}
+
+// BuyBundle spends soft currency on the bundle and puts its boosters into the user inventory.
+func (b *BoosterInventoryUsecase) BuyBundle(
+	ctx context.Context,
+	userID uuid.UUID,
+	bundleID string,
+) ([]model.BoosterStack, error) {
+	boostersCfg := b.BoostersConfig()
+	bundle, ok := boostersCfg.Bundle(bundleID)
+	if !ok {
+		return nil, model.ErrBoosterBundleNotExists
+	}
+	if _, ok = boostersCfg.Booster(bundle.BoosterID); !ok {
+		return nil, model.ErrLineGameBoosterNotExists
+	}
+	// the balance is created on the first request, so it is requested before the purchase
+	if _, err := b.BalanceUsecase.GetUserBalance(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	if err := b.BoosterInventoryStorage.BuyBoosters(
+		ctx, userID, bundle.Price, map[string]int{bundle.BoosterID: bundle.Count},
+	); err != nil {
+		return nil, fmt.Errorf("failed to buy boosters: %w", err)
+	}
+	return b.GetInventory(ctx, userID)
+}
+
+// GiftBoosters puts boosters into the inventory of the user by an admin.
+func (b *BoosterInventoryUsecase) GiftBoosters(
+	ctx context.Context,
+	adminID uuid.UUID,
+	userID uuid.UUID,
+	boosterID string,
+	count int,
+) ([]model.BoosterStack, error) {
+	if err := b.UserUsecase.CheckUserAnyRole(
+		ctx, adminID, []model.Role{
+			model.RoleAdmin,
+		},
+	); err != nil {
+		return nil, err
+	}
+	if _, ok := b.BoostersConfig().Booster(boosterID); !ok {
+		return nil, model.ErrLineGameBoosterNotExists
+	}
+	if err := b.GrantBoosters(ctx, userID, map[string]int{boosterID: count}); err != nil {
+		return nil, err
+	}
+	return b.GetInventory(ctx, userID)
+}
+
+// GrantBoosters puts rewarded boosters into the user inventory.
+func (b *BoosterInventoryUsecase) GrantBoosters(ctx context.Context, userID uuid.UUID, boosters map[string]int) error {
+	if len(boosters) == 0 {
+		return nil
+	}
+	if err := b.BoosterInventoryStorage.AddBoosters(ctx, userID, boosters); err != nil {
+		return fmt.Errorf("failed to add boosters: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/4units/mos-hack-game/back/config"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"testing"
+)
+
+type boosterInventoryStorageStub struct {
+	balance   *balanceStorageStub
+	inventory map[string]int
+}
+
+func (b *boosterInventoryStorageStub) GetBoosterInventory(_ context.Context, _ uuid.UUID) (map[string]int, error) {
+	return b.inventory, nil
+}
+
+func (b *boosterInventoryStorageStub) AddBoosters(_ context.Context, _ uuid.UUID, boosters map[string]int) error {
+	if b.inventory == nil {
+		b.inventory = make(map[string]int)
+	}
+	for boosterID, count := range boosters {
+		b.inventory[boosterID] += count
+	}
+	return nil
+}
+
+func (b *boosterInventoryStorageStub) BuyBoosters(
+	ctx context.Context, userID uuid.UUID, price int, boosters map[string]int,
+) error {
+	if b.balance.softCurrency < price {
+		return model.ErrNotEnoughSoftCurrency
+	}
+	b.balance.softCurrency -= price
+	return b.AddBoosters(ctx, userID, boosters)
+}
+
+// userStorageStub implements only roles of users
+type userStorageStub struct {
+	UserStorage
+	roles map[uuid.UUID][]model.Role
+}
+
+func (u *userStorageStub) GetUserRolesByID(_ context.Context, id uuid.UUID) ([]model.Role, error) {
+	return u.roles[id], nil
+}
+
+func TestBoosterInventoryUsecase(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	adminID := uuid.New()
+	balance := &balanceStorageStub{softCurrency: 500}
+	storage := &boosterInventoryStorageStub{balance: balance}
+	usecase := NewBoosterInventoryUsecase(
+		BoosterInventoryUsecaseDeps{
+			BoosterInventoryStorage: storage,
+			BalanceUsecase:          NewBalanceUsecase(BalanceUsecaseDeps{BalanceStorage: balance}),
+			UserUsecase: New(
+				UserUsecaseDeps{
+					UserStorage: &userStorageStub{
+						roles: map[uuid.UUID][]model.Role{adminID: {model.RoleAdmin}},
+					},
+				},
+			),
+			BoostersConfigProvider: &boostersConfigStub{
+				cfg: config.Boosters{
+					Items: []config.Booster{
+						{ID: "undo", Effect: config.BoosterUndo},
+						{ID: "skip", Effect: config.BoosterSkipLevel},
+					},
+					Bundles: []config.BoosterBundle{
+						{ID: "undo_10", BoosterID: "undo", Count: 10, Price: 300},
+					},
+				},
+			},
+		},
+	)
+
+	if _, err := usecase.BuyBundle(ctx, userID, "unknown"); !errors.Is(err, model.ErrBoosterBundleNotExists) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrBoosterBundleNotExists, err)
+	}
+	inventory, err := usecase.BuyBundle(ctx, userID, "undo_10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inventory) != 2 || inventory[0].Count != 10 || inventory[1].Count != 0 {
+		t.Errorf("Wrong inventory. Expected 10 undo and 0 skip, got %v\n", inventory)
+	}
+	if _, err = usecase.BuyBundle(ctx, userID, "undo_10"); !errors.Is(err, model.ErrNotEnoughSoftCurrency) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrNotEnoughSoftCurrency, err)
+	}
+	if balance.softCurrency != 200 || storage.inventory["undo"] != 10 {
+		t.Errorf(
+			"Wrong purchase. Expected 200 balance and 10 undo, got %v and %v\n",
+			balance.softCurrency, storage.inventory["undo"],
+		)
+	}
+
+	if _, err = usecase.GiftBoosters(ctx, userID, userID, "skip", 1); !errors.Is(err, model.ErrUserRoleHasNoAccess) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrUserRoleHasNoAccess, err)
+	}
+	if _, err = usecase.GiftBoosters(ctx, adminID, userID, "unknown", 1); !errors.Is(
+		err, model.ErrLineGameBoosterNotExists,
+	) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrLineGameBoosterNotExists, err)
+	}
+	if inventory, err = usecase.GiftBoosters(ctx, adminID, userID, "skip", 2); err != nil {
+		t.Fatal(err)
+	}
+	if inventory[1].Count != 2 {
+		t.Errorf("Wrong gifted boosters. Expected 2, got %v\n", inventory[1].Count)
+	}
+}
@@ -13,6 +13,8 @@ type ConifgUsecase struct {
 	priceConfig    *config.ItemsPrice
 	quizConfig     *config.Quiz
 	balanceConfig  *config.Balance
+	energyConfig   *config.Energy
+	boostersConfig *config.Boosters
 }
 
 func NewConifgUsecase(userUsecase *UserUsecase, cfg config.Config) *ConifgUsecase {
@@ -22,6 +24,8 @@ func NewConifgUsecase(userUsecase *UserUsecase, cfg config.Config) *ConifgUsecas
 		priceConfig:    &cfg.Game.ItemsPrice,
 		quizConfig:     &cfg.Game.Quiz,
 		balanceConfig:  &cfg.Game.Balance,
+		energyConfig:   &cfg.Game.Energy,
+		boostersConfig: &cfg.Game.Boosters,
 	}
 }
 
@@ -73,6 +77,30 @@ func (c *ConifgUsecase) UpdateQuizConfig(ctx context.Context, userID uuid.UUID,
 	return nil
 }
 
+func (c *ConifgUsecase) UpdateEnergyConfig(ctx context.Context, userID uuid.UUID, cfg config.Energy) error {
+	if err := c.userUsecase.CheckUserAnyRole(
+		ctx, userID, []model.Role{
+			model.RoleAdmin,
+		},
+	); err != nil {
+		return err
+	}
+	c.energyConfig = &cfg
+	return nil
+}
+
+func (c *ConifgUsecase) UpdateBoostersConfig(ctx context.Context, userID uuid.UUID, cfg config.Boosters) error {
+	if err := c.userUsecase.CheckUserAnyRole(
+		ctx, userID, []model.Role{
+			model.RoleAdmin,
+		},
+	); err != nil {
+		return err
+	}
+	c.boostersConfig = &cfg
+	return nil
+}
+
 func (c *ConifgUsecase) QuizConfig() *config.Quiz {
 	return c.quizConfig
 }
@@ -88,3 +116,11 @@ func (c *ConifgUsecase) PriceConifg() *config.ItemsPrice {
 func (c *ConifgUsecase) BalanceConfig() *config.Balance {
 	return c.balanceConfig
 }
+
+func (c *ConifgUsecase) EnergyConfig() *config.Energy {
+	return c.energyConfig
+}
+
+func (c *ConifgUsecase) BoostersConfig() *config.Boosters {
+	return c.boostersConfig
+}
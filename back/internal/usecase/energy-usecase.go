@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/config"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+type EnergyStorage interface {
+	GetUserEnergy(ctx context.Context, userID uuid.UUID) (model.UserEnergy, error)
+	// SpendLevelEnergy must take the cost once for the passed count atomically with the energy regenerated
+	// by the func, a user without energy starts from the initial one. It returns ErrNotEnoughEnergy
+	// when the regenerated energy is lower than the cost.
+	SpendLevelEnergy(
+		ctx context.Context,
+		userID uuid.UUID,
+		passedCount, cost int,
+		initial model.UserEnergy,
+		regenerate func(model.UserEnergy) model.UserEnergy,
+	) error
+	// BuyEnergyRefill must raise the saved energy and spend soft currency atomically,
+	// it returns ErrEnergyIsFull when the saved energy is not lower than the refilled one
+	BuyEnergyRefill(ctx context.Context, userID uuid.UUID, energy model.UserEnergy, price int) error
+}
+
+type EnergyConfigProvider interface {
+	EnergyConfig() *config.Energy
+}
+
+type EnergyUsecaseDeps struct {
+	EnergyStorage  EnergyStorage
+	BalanceUsecase *BalanceUsecase
+	EnergyConfigProvider
+	PriceConifgProvider
+}
+
+type EnergyUsecase struct {
+	EnergyUsecaseDeps
+	now func() time.Time
+}
+
+func NewEnergyUsecase(deps EnergyUsecaseDeps) *EnergyUsecase {
+	return &EnergyUsecase{
+		EnergyUsecaseDeps: deps,
+		now:               time.Now,
+	}
+}
+
+// GetEnergy returns the energy of the user with units regenerated till now.
+func (e *EnergyUsecase) GetEnergy(ctx context.Context, userID uuid.UUID) (model.EnergyStatus, error) {
+	energyCfg := *e.EnergyConfig()
+	energy, err := e.getEnergy(ctx, userID, energyCfg)
+	if err != nil {
+		return model.EnergyStatus{}, err
+	}
+	return newEnergyStatus(energy, energyCfg), nil
+}
+
+// SpendLevelEnergy takes the level cost once for the attempt of the level. It does nothing when
+// the energy is disabled.
+func (e *EnergyUsecase) SpendLevelEnergy(ctx context.Context, userID uuid.UUID, passedCount int) error {
+	energyCfg := *e.EnergyConfig()
+	if !energyCfg.Enabled {
+		return nil
+	}
+	now := e.now()
+	if err := e.EnergyStorage.SpendLevelEnergy(
+		ctx, userID, passedCount, energyCfg.LevelCost, newUserEnergy(energyCfg, now),
+		func(energy model.UserEnergy) model.UserEnergy {
+			return energy.Regenerate(energyCfg.Max, energyCfg.RegenInterval(), now)
+		},
+	); err != nil {
+		return fmt.Errorf("failed to spend energy: %w", err)
+	}
+	return nil
+}
+
+// BuyRefill fills the energy up to the max for soft currency.
+func (e *EnergyUsecase) BuyRefill(ctx context.Context, userID uuid.UUID) (model.EnergyStatus, error) {
+	energyCfg := *e.EnergyConfig()
+	energy, err := e.getEnergy(ctx, userID, energyCfg)
+	if err != nil {
+		return model.EnergyStatus{}, err
+	}
+	if energy.Value >= energyCfg.Max {
+		return model.EnergyStatus{}, model.ErrEnergyIsFull
+	}
+	// the balance is created on the first request, so it is requested before the purchase
+	if _, err = e.BalanceUsecase.GetUserBalance(ctx, userID); err != nil {
+		return model.EnergyStatus{}, fmt.Errorf("failed to get balance: %w", err)
+	}
+	energy.Value = energyCfg.Max
+	energy.UpdatedAt = e.now()
+	if err = e.EnergyStorage.BuyEnergyRefill(ctx, userID, energy, e.PriceConifg().EnergyRefillPrice); err != nil {
+		return model.EnergyStatus{}, err
+	}
+	return newEnergyStatus(energy, energyCfg), nil
+}
+
+func (e *EnergyUsecase) getEnergy(
+	ctx context.Context,
+	userID uuid.UUID,
+	energyCfg config.Energy,
+) (model.UserEnergy, error) {
+	now := e.now()
+	energy, err := e.EnergyStorage.GetUserEnergy(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, model.ErrEnergyNotExists) {
+			return model.UserEnergy{}, fmt.Errorf("failed to get energy: %w", err)
+		}
+		energy = newUserEnergy(energyCfg, now)
+	}
+	return energy.Regenerate(energyCfg.Max, energyCfg.RegenInterval(), now), nil
+}
+
+// newUserEnergy returns the full energy of a user who has not paid for any level attempt.
+func newUserEnergy(energyCfg config.Energy, now time.Time) model.UserEnergy {
+	return model.UserEnergy{Value: energyCfg.Max, UpdatedAt: now, PaidPassedCount: -1}
+}
+
+func newEnergyStatus(energy model.UserEnergy, energyCfg config.Energy) model.EnergyStatus {
+	return model.EnergyStatus{
+		Enabled:      energyCfg.Enabled,
+		Value:        energy.Value,
+		Max:          energyCfg.Max,
+		LevelCost:    energyCfg.LevelCost,
+		NextRefillAt: energy.NextRefillAt(energyCfg.Max, energyCfg.RegenInterval()),
+	}
+}
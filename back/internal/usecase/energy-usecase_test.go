@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/4units/mos-hack-game/back/config"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+type energyStorageStub struct {
+	energy map[uuid.UUID]model.UserEnergy
+	// balance pays for refills
+	balance *balanceStorageStub
+}
+
+func (e *energyStorageStub) GetUserEnergy(_ context.Context, userID uuid.UUID) (model.UserEnergy, error) {
+	energy, ok := e.energy[userID]
+	if !ok {
+		return model.UserEnergy{}, model.ErrEnergyNotExists
+	}
+	return energy, nil
+}
+
+func (e *energyStorageStub) SpendLevelEnergy(
+	_ context.Context,
+	userID uuid.UUID,
+	passedCount, cost int,
+	initial model.UserEnergy,
+	regenerate func(model.UserEnergy) model.UserEnergy,
+) error {
+	energy, ok := e.energy[userID]
+	if !ok {
+		energy = initial
+	}
+	if energy.PaidPassedCount == passedCount {
+		return nil
+	}
+	energy = regenerate(energy)
+	if energy.Value < cost {
+		return model.ErrNotEnoughEnergy
+	}
+	energy.Value -= cost
+	energy.PaidPassedCount = passedCount
+	e.energy[userID] = energy
+	return nil
+}
+
+func (e *energyStorageStub) BuyEnergyRefill(
+	_ context.Context, userID uuid.UUID, energy model.UserEnergy, price int,
+) error {
+	saved, ok := e.energy[userID]
+	if !ok || saved.Value >= energy.Value {
+		return model.ErrEnergyIsFull
+	}
+	if e.balance.softCurrency < price {
+		return model.ErrNotEnoughSoftCurrency
+	}
+	e.balance.softCurrency -= price
+	e.energy[userID] = energy
+	return nil
+}
+
+type energyConfigStub struct {
+	cfg config.Energy
+}
+
+func (e *energyConfigStub) EnergyConfig() *config.Energy {
+	return &e.cfg
+}
+
+func newEnergyUsecaseStub(cfg config.Energy) *EnergyUsecase {
+	return NewEnergyUsecase(
+		EnergyUsecaseDeps{
+			EnergyStorage:        &energyStorageStub{energy: make(map[uuid.UUID]model.UserEnergy)},
+			EnergyConfigProvider: &energyConfigStub{cfg: cfg},
+		},
+	)
+}
+
+func TestEnergyUsecase_SpendLevelEnergy(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Date(2025, 10, 19, 12, 0, 0, 0, time.UTC)
+	usecase := newEnergyUsecaseStub(config.Energy{Enabled: true, Max: 2, LevelCost: 1, RegenSeconds: 600})
+	usecase.now = func() time.Time { return now }
+
+	for passedCount := range 2 {
+		// the second request of the same attempt is free
+		for range 2 {
+			if err := usecase.SpendLevelEnergy(ctx, userID, passedCount); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := usecase.SpendLevelEnergy(ctx, userID, 2); !errors.Is(err, model.ErrNotEnoughEnergy) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrNotEnoughEnergy, err)
+	}
+	status, err := usecase.GetEnergy(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Value != 0 || !status.NextRefillAt.Equal(now.Add(10*time.Minute)) {
+		t.Errorf("Wrong energy. Expected 0 with refill at %v, got %+v\n", now.Add(10*time.Minute), status)
+	}
+
+	now = now.Add(15 * time.Minute)
+	status, err = usecase.GetEnergy(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Value != 1 || !status.NextRefillAt.Equal(now.Add(5*time.Minute)) {
+		t.Errorf("Wrong regenerated energy. Expected 1 with refill at %v, got %+v\n", now.Add(5*time.Minute), status)
+	}
+	if err = usecase.SpendLevelEnergy(ctx, userID, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(time.Hour)
+	status, err = usecase.GetEnergy(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Value != 2 || !status.NextRefillAt.IsZero() {
+		t.Errorf("Wrong regenerated energy. Expected full energy without refill time, got %+v\n", status)
+	}
+}
+
+func TestEnergyUsecase_SpendLevelEnergy_Disabled(t *testing.T) {
+	usecase := newEnergyUsecaseStub(config.Energy{Max: 1, LevelCost: 1})
+	for passedCount := range 3 {
+		if err := usecase.SpendLevelEnergy(context.Background(), uuid.New(), passedCount); err != nil {
+			t.Errorf("Wrong result. Expected no error for disabled energy, got %v\n", err)
+		}
+	}
+}
+
+func TestEnergyUsecase_BuyRefill(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	balance := &balanceStorageStub{}
+	usecase := newEnergyUsecaseStub(config.Energy{Enabled: true, Max: 3, LevelCost: 2, RegenSeconds: 600})
+	usecase.BalanceUsecase = NewBalanceUsecase(BalanceUsecaseDeps{BalanceStorage: balance})
+	usecase.EnergyStorage.(*energyStorageStub).balance = balance
+	usecase.PriceConifgProvider = &priceConfigStub{cfg: config.ItemsPrice{EnergyRefillPrice: 150}}
+	if err := usecase.BalanceUsecase.AddSoftCurrency(ctx, userID, 200); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := usecase.BuyRefill(ctx, userID); !errors.Is(err, model.ErrEnergyIsFull) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrEnergyIsFull, err)
+	}
+	if err := usecase.SpendLevelEnergy(ctx, userID, 0); err != nil {
+		t.Fatal(err)
+	}
+	status, err := usecase.BuyRefill(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Value != 3 {
+		t.Errorf("Wrong energy. Expected 3, got %v\n", status.Value)
+	}
+	softCurrency, err := usecase.BalanceUsecase.GetSoftCurrency(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if softCurrency != 50 {
+		t.Errorf("Wrong soft currency. Expected 50, got %v\n", softCurrency)
+	}
+	// a concurrent refill of the same energy finds the saved energy full and is not paid
+	if err = usecase.EnergyStorage.BuyEnergyRefill(ctx, userID, model.UserEnergy{Value: 3}, 150); !errors.Is(
+		err, model.ErrEnergyIsFull,
+	) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrEnergyIsFull, err)
+	}
+	if balance.softCurrency != 50 {
+		t.Errorf("Wrong soft currency. Expected 50 after the refused refill, got %v\n", balance.softCurrency)
+	}
+}
@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	http_errors "github.com/4units/mos-hack-game/back/pkg/http-errors"
+	logs "github.com/4units/mos-hack-game/back/pkg/logging"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrUnknownLeaderboard = http_errors.NewSame("unknown leaderboard", http.StatusBadRequest)
+)
+
+type LeaderboardStorage interface {
+	AddScore(ctx context.Context, key model.LeaderboardKey, userID uuid.UUID, delta int) (int, error)
+	SetBestScore(ctx context.Context, key model.LeaderboardKey, userID uuid.UUID, score int) (int, error)
+	GetTopScores(ctx context.Context, key model.LeaderboardKey, limit int) ([]model.LeaderboardEntry, error)
+	GetAllScores(ctx context.Context, key model.LeaderboardKey) ([]model.LeaderboardEntry, error)
+	GetUserScore(ctx context.Context, key model.LeaderboardKey, userID uuid.UUID) (model.LeaderboardEntry, error)
+	GetDisplayNames(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]string, error)
+}
+
+// LeaderboardCache returns false when the board is not loaded into the cache
+type LeaderboardCache interface {
+	SetScore(ctx context.Context, key model.LeaderboardKey, userID uuid.UUID, score int) error
+	LoadScores(ctx context.Context, key model.LeaderboardKey, entries []model.LeaderboardEntry) error
+	GetTopScores(ctx context.Context, key model.LeaderboardKey, limit int) ([]model.LeaderboardEntry, bool, error)
+	GetUserScore(ctx context.Context, key model.LeaderboardKey, userID uuid.UUID) (model.LeaderboardEntry, bool, error)
+}
+
+type LeaderboardUsecaseDeps struct {
+	LeaderboardStorage LeaderboardStorage
+	// LeaderboardCache is nil when redis is not configured
+	LeaderboardCache LeaderboardCache
+	LineGameConifgProvider
+}
+
+type LeaderboardUsecase struct {
+	LeaderboardUsecaseDeps
+	now func() time.Time
+}
+
+func NewLeaderboardUsecase(deps LeaderboardUsecaseDeps) *LeaderboardUsecase {
+	return &LeaderboardUsecase{
+		LeaderboardUsecaseDeps: deps,
+		now:                    time.Now,
+	}
+}
+
+var recordedPeriods = []model.LeaderboardPeriod{model.LeaderboardGlobal, model.LeaderboardWeekly}
+
+// RecordLevelTime keeps the best time of the level in milliseconds, so close times are not tied.
+func (l *LeaderboardUsecase) RecordLevelTime(
+	ctx context.Context,
+	userID uuid.UUID,
+	groupCode model.LineGameLevelGroupCode,
+	levelNum int,
+	timeSinceStart time.Duration,
+) error {
+	now := l.now()
+	for _, period := range recordedPeriods {
+		key := model.NewLevelTimeLeaderboardKey(groupCode, levelNum, period, now)
+		if err := l.setBestScore(ctx, key, userID, int(timeSinceStart.Milliseconds())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *LeaderboardUsecase) RecordDailyTime(
+	ctx context.Context,
+	userID uuid.UUID,
+	day time.Time,
+	timeSinceStart time.Duration,
+) error {
+	return l.setBestScore(ctx, model.NewDailyTimeLeaderboardKey(day), userID, int(timeSinceStart.Milliseconds()))
+}
+
+func (l *LeaderboardUsecase) AddStars(ctx context.Context, userID uuid.UUID, stars int) error {
+	return l.addScore(ctx, model.LeaderboardStars, userID, stars)
+}
+
+func (l *LeaderboardUsecase) AddQuizCorrectAnswer(ctx context.Context, userID uuid.UUID) error {
+	return l.addScore(ctx, model.LeaderboardQuiz, userID, 1)
+}
+
+func (l *LeaderboardUsecase) addScore(
+	ctx context.Context,
+	metric model.LeaderboardMetric,
+	userID uuid.UUID,
+	delta int,
+) error {
+	now := l.now()
+	for _, period := range recordedPeriods {
+		key := model.NewLeaderboardKey(metric, period, now)
+		score, err := l.LeaderboardStorage.AddScore(ctx, key, userID, delta)
+		if err != nil {
+			return fmt.Errorf("failed to add %s score: %w", key.Board, err)
+		}
+		l.cacheScore(ctx, key, userID, score)
+	}
+	return nil
+}
+
+func (l *LeaderboardUsecase) setBestScore(
+	ctx context.Context,
+	key model.LeaderboardKey,
+	userID uuid.UUID,
+	score int,
+) error {
+	best, err := l.LeaderboardStorage.SetBestScore(ctx, key, userID, score)
+	if err != nil {
+		return fmt.Errorf("failed to set %s score: %w", key.Board, err)
+	}
+	l.cacheScore(ctx, key, userID, best)
+	return nil
+}
+
+func (l *LeaderboardUsecase) cacheScore(ctx context.Context, key model.LeaderboardKey, userID uuid.UUID, score int) {
+	if l.LeaderboardCache == nil {
+		return
+	}
+	if err := l.LeaderboardCache.SetScore(ctx, key, userID, score); err != nil {
+		logs.Error("failed to cache leaderboard score", err, slog.String("board", key.Board))
+	}
+}
+
+// GetLeaderboard returns best entries of the board and the entry of the user.
+func (l *LeaderboardUsecase) GetLeaderboard(
+	ctx context.Context,
+	userID uuid.UUID,
+	query model.LeaderboardQuery,
+) (model.Leaderboard, error) {
+	key, err := l.leaderboardKey(query)
+	if err != nil {
+		return model.Leaderboard{}, err
+	}
+	entries, err := l.getTopScores(ctx, key, query.Limit)
+	if err != nil {
+		return model.Leaderboard{}, err
+	}
+	board := model.Leaderboard{Key: key, Entries: entries}
+	userEntry, err := l.getUserScore(ctx, key, userID)
+	if err == nil {
+		board.User = &userEntry
+	} else if !errors.Is(err, model.ErrLeaderboardHasNoUserScore) {
+		return model.Leaderboard{}, err
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(entries)+1)
+	for _, entry := range entries {
+		userIDs = append(userIDs, entry.UserID)
+	}
+	if board.User != nil {
+		userIDs = append(userIDs, userID)
+	}
+	names, err := l.LeaderboardStorage.GetDisplayNames(ctx, userIDs)
+	if err != nil {
+		return model.Leaderboard{}, fmt.Errorf("failed to get display names: %w", err)
+	}
+	for i := range board.Entries {
+		board.Entries[i].DisplayName = names[board.Entries[i].UserID]
+	}
+	if board.User != nil {
+		board.User.DisplayName = names[userID]
+	}
+	return board, nil
+}
+
+func (l *LeaderboardUsecase) leaderboardKey(query model.LeaderboardQuery) (model.LeaderboardKey, error) {
+	now := l.now()
+	if query.Metric == model.LeaderboardDailyTime {
+		return model.NewDailyTimeLeaderboardKey(dailyDay(l.LineGameConifg().Daily, now)), nil
+	}
+	if query.Period != model.LeaderboardGlobal && query.Period != model.LeaderboardWeekly {
+		return model.LeaderboardKey{}, ErrUnknownLeaderboard
+	}
+	switch query.Metric {
+	case model.LeaderboardLevelTime:
+		if query.GroupCode == "" || query.GroupCode == model.LineGameEndlessGroupCode {
+			return model.LeaderboardKey{}, ErrUnknownLeaderboard
+		}
+		return model.NewLevelTimeLeaderboardKey(query.GroupCode, query.LevelNum, query.Period, now), nil
+	case model.LeaderboardStars, model.LeaderboardQuiz:
+		return model.NewLeaderboardKey(query.Metric, query.Period, now), nil
+	default:
+		return model.LeaderboardKey{}, ErrUnknownLeaderboard
+	}
+}
+
+func (l *LeaderboardUsecase) getTopScores(
+	ctx context.Context,
+	key model.LeaderboardKey,
+	limit int,
+) ([]model.LeaderboardEntry, error) {
+	if l.LeaderboardCache != nil {
+		entries, cached, err := l.LeaderboardCache.GetTopScores(ctx, key, limit)
+		if err == nil && cached {
+			return entries, nil
+		}
+		if err != nil {
+			logs.Error("failed to get cached leaderboard", err, slog.String("board", key.Board))
+		} else {
+			allEntries, err := l.LeaderboardStorage.GetAllScores(ctx, key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get all scores: %w", err)
+			}
+			if err = l.LeaderboardCache.LoadScores(ctx, key, allEntries); err != nil {
+				logs.Error("failed to load leaderboard into cache", err, slog.String("board", key.Board))
+			}
+			return allEntries[:min(limit, len(allEntries))], nil
+		}
+	}
+	entries, err := l.LeaderboardStorage.GetTopScores(ctx, key, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top scores: %w", err)
+	}
+	return entries, nil
+}
+
+func (l *LeaderboardUsecase) getUserScore(
+	ctx context.Context,
+	key model.LeaderboardKey,
+	userID uuid.UUID,
+) (model.LeaderboardEntry, error) {
+	if l.LeaderboardCache != nil {
+		entry, cached, err := l.LeaderboardCache.GetUserScore(ctx, key, userID)
+		if cached {
+			return entry, err
+		}
+		if err != nil {
+			logs.Error("failed to get cached leaderboard score", err, slog.String("board", key.Board))
+		}
+	}
+	entry, err := l.LeaderboardStorage.GetUserScore(ctx, key, userID)
+	if err != nil && !errors.Is(err, model.ErrLeaderboardHasNoUserScore) {
+		return model.LeaderboardEntry{}, fmt.Errorf("failed to get user score: %w", err)
+	}
+	return entry, err
+}
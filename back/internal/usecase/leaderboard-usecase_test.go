@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"github.com/4units/mos-hack-game/back/config"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+type leaderboardStorageStub struct {
+	scores map[model.LeaderboardKey]map[uuid.UUID]int
+}
+
+func newLeaderboardStorageStub() *leaderboardStorageStub {
+	return &leaderboardStorageStub{scores: make(map[model.LeaderboardKey]map[uuid.UUID]int)}
+}
+
+func (l *leaderboardStorageStub) board(key model.LeaderboardKey) map[uuid.UUID]int {
+	if l.scores[key] == nil {
+		l.scores[key] = make(map[uuid.UUID]int)
+	}
+	return l.scores[key]
+}
+
+func (l *leaderboardStorageStub) AddScore(
+	_ context.Context, key model.LeaderboardKey, userID uuid.UUID, delta int,
+) (int, error) {
+	l.board(key)[userID] += delta
+	return l.board(key)[userID], nil
+}
+
+func (l *leaderboardStorageStub) SetBestScore(
+	_ context.Context, key model.LeaderboardKey, userID uuid.UUID, score int,
+) (int, error) {
+	board := l.board(key)
+	if old, ok := board[userID]; ok {
+		if key.LowerIsBetter {
+			score = min(old, score)
+		} else {
+			score = max(old, score)
+		}
+	}
+	board[userID] = score
+	return score, nil
+}
+
+func (l *leaderboardStorageStub) GetTopScores(
+	ctx context.Context, key model.LeaderboardKey, limit int,
+) ([]model.LeaderboardEntry, error) {
+	entries, err := l.GetAllScores(ctx, key)
+	return entries[:min(limit, len(entries))], err
+}
+
+func (l *leaderboardStorageStub) GetAllScores(
+	_ context.Context, key model.LeaderboardKey,
+) ([]model.LeaderboardEntry, error) {
+	entries := make([]model.LeaderboardEntry, 0)
+	for userID, score := range l.scores[key] {
+		entries = append(entries, model.LeaderboardEntry{UserID: userID, Score: score})
+	}
+	slices.SortFunc(
+		entries, func(a, b model.LeaderboardEntry) int {
+			if key.LowerIsBetter {
+				return cmp.Compare(a.Score, b.Score)
+			}
+			return cmp.Compare(b.Score, a.Score)
+		},
+	)
+	model.RankLeaderboardEntries(entries)
+	return entries, nil
+}
+
+func (l *leaderboardStorageStub) GetUserScore(
+	ctx context.Context, key model.LeaderboardKey, userID uuid.UUID,
+) (model.LeaderboardEntry, error) {
+	entries, _ := l.GetAllScores(ctx, key)
+	for _, entry := range entries {
+		if entry.UserID == userID {
+			return entry, nil
+		}
+	}
+	return model.LeaderboardEntry{}, model.ErrLeaderboardHasNoUserScore
+}
+
+func (l *leaderboardStorageStub) GetDisplayNames(
+	_ context.Context, userIDs []uuid.UUID,
+) (map[uuid.UUID]string, error) {
+	names := make(map[uuid.UUID]string, len(userIDs))
+	for _, userID := range userIDs {
+		names[userID] = userID.String()
+	}
+	return names, nil
+}
+
+type leaderboardCacheStub struct {
+	boards map[model.LeaderboardKey][]model.LeaderboardEntry
+}
+
+func (l *leaderboardCacheStub) SetScore(context.Context, model.LeaderboardKey, uuid.UUID, int) error {
+	return nil
+}
+
+func (l *leaderboardCacheStub) LoadScores(
+	_ context.Context, key model.LeaderboardKey, entries []model.LeaderboardEntry,
+) error {
+	l.boards[key] = slices.Clone(entries)
+	return nil
+}
+
+func (l *leaderboardCacheStub) GetTopScores(
+	_ context.Context, key model.LeaderboardKey, limit int,
+) ([]model.LeaderboardEntry, bool, error) {
+	entries, ok := l.boards[key]
+	return entries[:min(limit, len(entries))], ok, nil
+}
+
+func (l *leaderboardCacheStub) GetUserScore(
+	_ context.Context, key model.LeaderboardKey, userID uuid.UUID,
+) (model.LeaderboardEntry, bool, error) {
+	entries, ok := l.boards[key]
+	if !ok {
+		return model.LeaderboardEntry{}, false, nil
+	}
+	for _, entry := range entries {
+		if entry.UserID == userID {
+			return entry, true, nil
+		}
+	}
+	return model.LeaderboardEntry{}, true, model.ErrLeaderboardHasNoUserScore
+}
+
+func newLeaderboardUsecaseStub() *LeaderboardUsecase {
+	return NewLeaderboardUsecase(
+		LeaderboardUsecaseDeps{
+			LeaderboardStorage:     newLeaderboardStorageStub(),
+			LineGameConifgProvider: &lineGameConfigStub{},
+		},
+	)
+}
+
+func TestLeaderboardUsecase_RecordLevelTime(t *testing.T) {
+	ctx := context.Background()
+	usecase := newLeaderboardUsecaseStub()
+	users := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	times := [][]int{{40, 30}, {25}, {30, 50}}
+	for i, userTimes := range times {
+		for _, seconds := range userTimes {
+			if err := usecase.RecordLevelTime(ctx, users[i], "4_0_0", 1, time.Duration(seconds)*time.Second); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for _, period := range []model.LeaderboardPeriod{model.LeaderboardGlobal, model.LeaderboardWeekly} {
+		board, err := usecase.GetLeaderboard(
+			ctx, users[2], model.LeaderboardQuery{
+				Metric:    model.LeaderboardLevelTime,
+				Period:    period,
+				GroupCode: "4_0_0",
+				LevelNum:  1,
+				Limit:     2,
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(board.Entries) != 2 || board.Entries[0].UserID != users[1] || board.Entries[0].Score != 25000 {
+			t.Errorf("Wrong %v leaderboard entries. Expected the fastest user first, got %+v\n", period, board.Entries)
+		}
+		if board.User == nil || board.User.Rank != 2 || board.User.Score != 30000 {
+			t.Errorf("Wrong %v user entry. Expected rank 2 with the best time 30s, got %+v\n", period, board.User)
+		}
+		if board.User != nil && board.User.DisplayName != users[2].String() {
+			t.Errorf("Wrong display name. Expected %v, got %v\n", users[2], board.User.DisplayName)
+		}
+	}
+}
+
+func TestLeaderboardUsecase_RecordLevelTime_SubSecond(t *testing.T) {
+	ctx := context.Background()
+	usecase := newLeaderboardUsecaseStub()
+	users := []uuid.UUID{uuid.New(), uuid.New()}
+	times := []time.Duration{700 * time.Millisecond, 450 * time.Millisecond}
+	for i, userTime := range times {
+		if err := usecase.RecordLevelTime(ctx, users[i], "4_0_0", 1, userTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	board, err := usecase.GetLeaderboard(
+		ctx, users[0], model.LeaderboardQuery{
+			Metric:    model.LeaderboardLevelTime,
+			Period:    model.LeaderboardGlobal,
+			GroupCode: "4_0_0",
+			LevelNum:  1,
+			Limit:     2,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(board.Entries) != 2 || board.Entries[0].Score != 450 || board.User == nil || board.User.Rank != 2 {
+		t.Errorf("Wrong leaderboard. Expected sub-second times ranked without ties, got %+v\n", board)
+	}
+}
+
+func TestLeaderboardUsecase_GetLeaderboard_Cache(t *testing.T) {
+	ctx := context.Background()
+	cache := &leaderboardCacheStub{boards: make(map[model.LeaderboardKey][]model.LeaderboardEntry)}
+	usecase := NewLeaderboardUsecase(
+		LeaderboardUsecaseDeps{
+			LeaderboardStorage:     newLeaderboardStorageStub(),
+			LeaderboardCache:       cache,
+			LineGameConifgProvider: &lineGameConfigStub{},
+		},
+	)
+	userID := uuid.New()
+	for range 3 {
+		if err := usecase.AddQuizCorrectAnswer(ctx, userID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	query := model.LeaderboardQuery{Metric: model.LeaderboardQuiz, Period: model.LeaderboardWeekly, Limit: 10}
+	board, err := usecase.GetLeaderboard(ctx, uuid.New(), query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if board.User != nil {
+		t.Errorf("Wrong user entry. Expected nil for user without score, got %+v\n", board.User)
+	}
+	cached, ok := cache.boards[board.Key]
+	if !ok {
+		t.Fatalf("Wrong cache state. Expected loaded board %+v\n", board.Key)
+	}
+	expected := []model.LeaderboardEntry{{Rank: 1, UserID: userID, Score: 3}}
+	if !reflect.DeepEqual(cached, expected) {
+		t.Errorf("Wrong cached entries. Expected %+v, got %+v\n", expected, cached)
+	}
+}
+
+func TestLeaderboardUsecase_GetLeaderboard_Unknown(t *testing.T) {
+	usecase := newLeaderboardUsecaseStub()
+	queries := []model.LeaderboardQuery{
+		{Metric: "coins", Period: model.LeaderboardGlobal},
+		{Metric: model.LeaderboardStars, Period: model.LeaderboardDaily},
+		{Metric: model.LeaderboardLevelTime, Period: model.LeaderboardGlobal},
+		{Metric: model.LeaderboardLevelTime, Period: model.LeaderboardGlobal, GroupCode: model.LineGameEndlessGroupCode},
+	}
+	for _, query := range queries {
+		if _, err := usecase.GetLeaderboard(context.Background(), uuid.New(), query); !errors.Is(err, ErrUnknownLeaderboard) {
+			t.Errorf("Wrong error for %+v. Expected %v, got %v\n", query, ErrUnknownLeaderboard, err)
+		}
+	}
+}
+
+func TestLeaderboardUsecase_DailyTimeBoard(t *testing.T) {
+	usecase := NewLeaderboardUsecase(
+		LeaderboardUsecaseDeps{
+			LeaderboardStorage: newLeaderboardStorageStub(),
+			LineGameConifgProvider: &lineGameConfigStub{
+				cfg: config.LineGame{Daily: config.LineGameDaily{UTCOffset: 3}},
+			},
+		},
+	)
+	usecase.now = func() time.Time { return time.Date(2025, 10, 19, 22, 0, 0, 0, time.UTC) }
+	key, err := usecase.leaderboardKey(model.LeaderboardQuery{Metric: model.LeaderboardDailyTime})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Period != "2025-10-20" || !key.LowerIsBetter {
+		t.Errorf("Wrong daily board key. Expected the next day in the daily time zone, got %+v\n", key)
+	}
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	http_errors "github.com/4units/mos-hack-game/back/pkg/http-errors"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+// maxLineGameGroupCodeLen is the size of group code columns in the database
+const maxLineGameGroupCodeLen = 10
+
+var (
+	ErrLineGameLevelsNotEditable = http_errors.NewSame(
+		"line game levels storage is not editable",
+		http.StatusConflict,
+	)
+)
+
+type LineLevelEditor interface {
+	SaveGroupDraft(ctx context.Context, userID uuid.UUID, group model.LineGameLevelGroup) error
+	GetGroupDraft(ctx context.Context, groupCode model.LineGameLevelGroupCode) (model.LineGameLevelGroup, error)
+	GetPublishedGroup(ctx context.Context, groupCode model.LineGameLevelGroupCode) (model.LineGameLevelGroup, error)
+	PublishGroup(ctx context.Context, userID uuid.UUID, groupCode model.LineGameLevelGroupCode) error
+	GetGroupsInfo(ctx context.Context) ([]model.LineGameLevelGroupInfo, error)
+}
+
+type LineGameAdminUsecaseDeps struct {
+	// LineLevelEditor is nil when levels are stored in files
+	LineLevelEditor LineLevelEditor
+	UserUsecase     *UserUsecase
+}
+
+type LineGameAdminUsecase struct {
+	LineGameAdminUsecaseDeps
+}
+
+func NewLineGameAdminUsecase(deps LineGameAdminUsecaseDeps) *LineGameAdminUsecase {
+	return &LineGameAdminUsecase{LineGameAdminUsecaseDeps: deps}
+}
+
+// UploadGroup validates every level of the group against its answer and saves the group as a draft
+// when all levels are correct.
+func (l *LineGameAdminUsecase) UploadGroup(
+	ctx context.Context,
+	userID uuid.UUID,
+	group model.LineGameLevelGroup,
+) ([]model.LineGameLevelValidation, bool, error) {
+	if err := l.checkAccess(ctx, userID); err != nil {
+		return nil, false, err
+	}
+	if err := group.ValidateParams(); err != nil {
+		return nil, false, http_errors.NewSame("line game group is invalid: "+err.Error(), http.StatusBadRequest)
+	}
+	if len(group.GetCode()) > maxLineGameGroupCodeLen {
+		return nil, false, http_errors.NewSame("line game group code is too long", http.StatusBadRequest)
+	}
+	report := make([]model.LineGameLevelValidation, 0, len(group.Levels))
+	valid := true
+	for i := range group.Levels {
+		err := group.ValidateLevel(i)
+		if err != nil {
+			valid = false
+		}
+		report = append(report, model.LineGameLevelValidation{LevelNum: i, Err: err})
+	}
+	if !valid {
+		return report, false, nil
+	}
+	if err := l.LineLevelEditor.SaveGroupDraft(ctx, userID, group); err != nil {
+		return nil, false, fmt.Errorf("failed to save group draft: %w", err)
+	}
+	return report, true, nil
+}
+
+// GetGroupPreview returns the draft of the group or the published group when there is no draft.
+func (l *LineGameAdminUsecase) GetGroupPreview(
+	ctx context.Context,
+	userID uuid.UUID,
+	groupCode model.LineGameLevelGroupCode,
+) (model.LineGameLevelGroup, model.LineGameLevelGroupStatus, error) {
+	if err := l.checkAccess(ctx, userID); err != nil {
+		return model.LineGameLevelGroup{}, "", err
+	}
+	group, err := l.LineLevelEditor.GetGroupDraft(ctx, groupCode)
+	if err == nil {
+		return group, model.LineGameLevelGroupDraft, nil
+	}
+	if !errors.Is(err, model.ErrLineGameGroupDraftDoesNotExist) {
+		return model.LineGameLevelGroup{}, "", fmt.Errorf("failed to get group draft: %w", err)
+	}
+	group, err = l.LineLevelEditor.GetPublishedGroup(ctx, groupCode)
+	if err != nil {
+		if errors.Is(err, model.ErrLineGameNotExistsLevelInStorage) {
+			return model.LineGameLevelGroup{}, "", model.ErrLineGameGroupDraftDoesNotExist
+		}
+		return model.LineGameLevelGroup{}, "", fmt.Errorf("failed to get published group: %w", err)
+	}
+	return group, model.LineGameLevelGroupPublished, nil
+}
+
+func (l *LineGameAdminUsecase) PublishGroup(
+	ctx context.Context,
+	userID uuid.UUID,
+	groupCode model.LineGameLevelGroupCode,
+) error {
+	if err := l.checkAccess(ctx, userID); err != nil {
+		return err
+	}
+	if err := l.LineLevelEditor.PublishGroup(ctx, userID, groupCode); err != nil {
+		return fmt.Errorf("failed to publish group %s: %w", groupCode, err)
+	}
+	return nil
+}
+
+func (l *LineGameAdminUsecase) GetGroupsInfo(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]model.LineGameLevelGroupInfo, error) {
+	if err := l.checkAccess(ctx, userID); err != nil {
+		return nil, err
+	}
+	infoList, err := l.LineLevelEditor.GetGroupsInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups info: %w", err)
+	}
+	return infoList, nil
+}
+
+func (l *LineGameAdminUsecase) checkAccess(ctx context.Context, userID uuid.UUID) error {
+	if err := l.UserUsecase.CheckUserAnyRole(
+		ctx, userID, []model.Role{
+			model.RoleAdmin,
+		},
+	); err != nil {
+		return err
+	}
+	if l.LineLevelEditor == nil {
+		return ErrLineGameLevelsNotEditable
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"testing"
+)
+
+type lineLevelEditorStub struct {
+	draftErr  error
+	published model.LineGameLevelGroup
+}
+
+func (l *lineLevelEditorStub) SaveGroupDraft(_ context.Context, _ uuid.UUID, _ model.LineGameLevelGroup) error {
+	return nil
+}
+
+func (l *lineLevelEditorStub) GetGroupDraft(
+	_ context.Context,
+	_ model.LineGameLevelGroupCode,
+) (model.LineGameLevelGroup, error) {
+	return model.LineGameLevelGroup{}, l.draftErr
+}
+
+func (l *lineLevelEditorStub) GetPublishedGroup(
+	_ context.Context,
+	_ model.LineGameLevelGroupCode,
+) (model.LineGameLevelGroup, error) {
+	return l.published, nil
+}
+
+func (l *lineLevelEditorStub) PublishGroup(_ context.Context, _ uuid.UUID, _ model.LineGameLevelGroupCode) error {
+	return nil
+}
+
+func (l *lineLevelEditorStub) GetGroupsInfo(_ context.Context) ([]model.LineGameLevelGroupInfo, error) {
+	return nil, nil
+}
+
+func TestLineGameAdminUsecase_GetGroupPreview(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+	errDB := errors.New("connection refused")
+	tests := []struct {
+		name           string
+		draftErr       error
+		expectedStatus model.LineGameLevelGroupStatus
+		expectedErr    error
+	}{
+		{"no draft", model.ErrLineGameGroupDraftDoesNotExist, model.LineGameLevelGroupPublished, nil},
+		{"failed draft lookup", errDB, "", errDB},
+	}
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				usecase := NewLineGameAdminUsecase(
+					LineGameAdminUsecaseDeps{
+						LineLevelEditor: &lineLevelEditorStub{
+							draftErr:  test.draftErr,
+							published: model.LineGameLevelGroup{FieldSize: 5},
+						},
+						UserUsecase: New(
+							UserUsecaseDeps{
+								UserStorage: &userStorageStub{
+									roles: map[uuid.UUID][]model.Role{adminID: {model.RoleAdmin}},
+								},
+							},
+						),
+					},
+				)
+				_, status, err := usecase.GetGroupPreview(ctx, adminID, "5_0_0")
+				if !errors.Is(err, test.expectedErr) {
+					t.Fatalf("Wrong error. Expected %v, got %v\n", test.expectedErr, err)
+				}
+				if status != test.expectedStatus {
+					t.Errorf("Wrong status. Expected %v, got %v\n", test.expectedStatus, status)
+				}
+			},
+		)
+	}
+}
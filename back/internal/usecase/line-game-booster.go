@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/config"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+)
+
+type LineGameBoosterStorage interface {
+	UseBooster(
+		ctx context.Context,
+		userID uuid.UUID,
+		attempt model.LineGameLevelAttempt,
+		boosterID string,
+		limit int,
+		hint *model.LineGameHint,
+	) (int, int, error)
+}
+
+type BoostersConfigProvider interface {
+	BoostersConfig() *config.Boosters
+}
+
+// UseBooster takes the booster from the user inventory for the current level attempt and applies its effect.
+// The skip level booster moves the user to the next level without a reward, the reveal checkpoint
+// booster continues the path up to the next order cell like the checkpoint hint.
+func (l *LineGameUsecase) UseBooster(
+	ctx context.Context,
+	userID uuid.UUID,
+	boosterID string,
+	path []model.LineGameLevelCell,
+) (model.LineGameBoosterUse, error) {
+	booster, ok := l.BoostersConfig().Booster(boosterID)
+	if !ok {
+		return model.LineGameBoosterUse{}, model.ErrLineGameBoosterNotExists
+	}
+	level, attempt, err := l.getUserLevel(ctx, userID)
+	if err != nil {
+		return model.LineGameBoosterUse{}, fmt.Errorf("failed to get user level: %w", err)
+	}
+
+	use := model.LineGameBoosterUse{
+		BoosterID: booster.ID,
+		Effect:    booster.Effect,
+		UsesLeft:  -1,
+	}
+	// effects are prepared before the booster is spent, so an invalid request costs nothing
+	var (
+		nextGroupCode model.LineGameLevelGroupCode
+		nextLevelNum  int
+	)
+	switch booster.Effect {
+	case config.BoosterSkipLevel:
+		nextGroupCode, nextLevelNum, err = l.getNextLevel(
+			ctx, userID, attempt.GroupCode, attempt.LevelNum, model.LineGameProgressionNext,
+		)
+		if err != nil {
+			return model.LineGameBoosterUse{}, fmt.Errorf("failed to get next level: %w", err)
+		}
+		if nextGroupCode == attempt.GroupCode && nextLevelNum == attempt.LevelNum {
+			return model.LineGameBoosterUse{}, model.ErrLineGameBoosterNotAvailable
+		}
+	case config.BoosterRevealCheckpoint:
+		hint, err := l.buildLevelHint(level, model.LineGameHintCheckpoint, path)
+		if err != nil {
+			return model.LineGameBoosterUse{}, err
+		}
+		use.Hint = &hint
+	}
+
+	// the limit is checked and the booster is spent together with the revealed hint, so concurrent
+	// uses can not exceed the limit and a spent booster always keeps its hint
+	uses, inventoryLeft, err := l.LineGameBoosterStorage.UseBooster(
+		ctx, userID, attempt, booster.ID, booster.LimitPerLevel, use.Hint,
+	)
+	if err != nil {
+		return model.LineGameBoosterUse{}, fmt.Errorf("failed to use booster: %w", err)
+	}
+	use.InventoryLeft = inventoryLeft
+	if booster.LimitPerLevel > 0 {
+		use.UsesLeft = booster.LimitPerLevel - uses
+	}
+	if booster.Effect == config.BoosterSkipLevel {
+		if err = l.LineGameProgressStorage.UpdateUserLineGameLevel(
+			ctx, userID, nextGroupCode, attempt.PassedCount+1, nextLevelNum,
+		); err != nil {
+			return model.LineGameBoosterUse{}, fmt.Errorf("failed to update next level: %w", err)
+		}
+	}
+	return use, nil
+}
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/4units/mos-hack-game/back/config"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"testing"
+)
+
+type boosterUseKey struct {
+	attempt   model.LineGameLevelAttempt
+	boosterID string
+}
+
+// boosterStorageStub uses boosters of the inventory stub and saves hints into the hint stub
+type boosterStorageStub struct {
+	uses      map[boosterUseKey]int
+	inventory *boosterInventoryStorageStub
+	hints     *hintStorageStub
+}
+
+func (b *boosterStorageStub) UseBooster(
+	ctx context.Context,
+	userID uuid.UUID,
+	attempt model.LineGameLevelAttempt,
+	boosterID string,
+	limit int,
+	hint *model.LineGameHint,
+) (int, int, error) {
+	key := boosterUseKey{attempt, boosterID}
+	if limit > 0 && b.uses[key] >= limit {
+		return 0, 0, model.ErrLineGameBoosterLimitExceeded
+	}
+	if b.inventory.inventory[boosterID] == 0 {
+		return 0, 0, model.ErrBoosterNotInInventory
+	}
+	if hint != nil {
+		for _, saved := range b.hints.hints[attempt] {
+			if saved.Reveals(*hint) {
+				return 0, 0, model.ErrLineGameBoosterNotAvailable
+			}
+		}
+		if err := b.hints.AddLevelHint(ctx, userID, attempt, *hint); err != nil {
+			return 0, 0, err
+		}
+	}
+	if b.uses == nil {
+		b.uses = make(map[boosterUseKey]int)
+	}
+	b.uses[key]++
+	b.inventory.inventory[boosterID]--
+	return b.uses[key], b.inventory.inventory[boosterID], nil
+}
+
+type boostersConfigStub struct {
+	cfg config.Boosters
+}
+
+func (b *boostersConfigStub) BoostersConfig() *config.Boosters {
+	return &b.cfg
+}
+
+func TestLineGameUsecase_UseBooster(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	level := model.LineGameLevel{
+		FieldSize: 2,
+		Start:     model.LineGameLevelCell{X: 0, Y: 0},
+		End:       model.LineGameLevelCell{X: 0, Y: 1},
+		Answer:    [][]int{{1, 2}, {4, 3}},
+	}
+	progress := &progressStorageStub{groupCode: "2_0_0", levelNum: 0, passedCount: 3}
+	balance := &balanceStorageStub{softCurrency: 500}
+	inventory := &boosterInventoryStorageStub{
+		balance:   balance,
+		inventory: map[string]int{"undo": 1, "reveal": 2, "skip": 1},
+	}
+	hints := &hintStorageStub{}
+	usecase := NewLineGameUsecase(
+		LineGameUsecaseDeps{
+			LineGameLevelStorage: &levelStorageStub{
+				levels: map[model.LineGameLevelGroupCode][]model.LineGameLevel{"2_0_0": {level, level}},
+			},
+			LineGameProgressStorage:    progress,
+			LineGameHintStorage:        hints,
+			LineGameLevelResultStorage: &levelResultStorageStub{},
+			LineGameStarStorage:        &starStorageStub{},
+			LineGameBoosterStorage:     &boosterStorageStub{inventory: inventory, hints: hints},
+			EnergyUsecase:              newEnergyUsecaseStub(config.Energy{}),
+			BalanceUsecase:             NewBalanceUsecase(BalanceUsecaseDeps{BalanceStorage: balance}),
+			LeaderboardUsecase:         newLeaderboardUsecaseStub(),
+			BoosterInventoryUsecase: NewBoosterInventoryUsecase(
+				BoosterInventoryUsecaseDeps{BoosterInventoryStorage: inventory},
+			),
+			LineGameConifgProvider: &lineGameConfigStub{},
+			PriceConifgProvider:    &priceConfigStub{},
+			BoostersConfigProvider: &boostersConfigStub{
+				cfg: config.Boosters{
+					Items: []config.Booster{
+						{ID: "undo", Effect: config.BoosterUndo},
+						{ID: "reveal", Effect: config.BoosterRevealCheckpoint, LimitPerLevel: 2},
+						{ID: "skip", Effect: config.BoosterSkipLevel, LimitPerLevel: 1},
+					},
+				},
+			},
+		},
+	)
+
+	if _, err := usecase.UseBooster(ctx, userID, "unknown", nil); !errors.Is(err, model.ErrLineGameBoosterNotExists) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrLineGameBoosterNotExists, err)
+	}
+
+	use, err := usecase.UseBooster(ctx, userID, "undo", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if use.UsesLeft != -1 || use.InventoryLeft != 0 || use.Hint != nil {
+		t.Errorf("Wrong undo use. Expected unlimited uses without hint, got %v %v\n", use.UsesLeft, use.Hint)
+	}
+	if _, err = usecase.UseBooster(ctx, userID, "undo", nil); !errors.Is(err, model.ErrBoosterNotInInventory) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrBoosterNotInInventory, err)
+	}
+
+	use, err = usecase.UseBooster(ctx, userID, "reveal", []model.LineGameLevelCell{{X: 0, Y: 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if use.UsesLeft != 1 || use.Hint == nil || len(use.Hint.Cells) != 3 {
+		t.Errorf("Wrong reveal use. Expected 1 use left and 3 cells, got %v %v\n", use.UsesLeft, use.Hint)
+	}
+	attempt := model.LineGameLevelAttempt{GroupCode: "2_0_0", PassedCount: 3}
+	if len(hints.hints[attempt]) != 1 {
+		t.Errorf("Wrong level hints. Expected the revealed checkpoint, got %v\n", hints.hints[attempt])
+	}
+	// the same checkpoint is already revealed, so the booster stays in the inventory
+	if _, err = usecase.UseBooster(ctx, userID, "reveal", nil); !errors.Is(err, model.ErrLineGameBoosterNotAvailable) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrLineGameBoosterNotAvailable, err)
+	}
+	if inventory.inventory["reveal"] != 1 {
+		t.Errorf("Wrong inventory. Expected 1 reveal, got %v\n", inventory.inventory["reveal"])
+	}
+	use, err = usecase.UseBooster(ctx, userID, "reveal", []model.LineGameLevelCell{{X: 0, Y: 0}, {X: 1, Y: 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if use.UsesLeft != 0 || use.InventoryLeft != 0 || len(hints.hints[attempt]) != 2 {
+		t.Errorf(
+			"Wrong reveal use. Expected 0 uses left and 2 saved hints, got %v and %v\n",
+			use.UsesLeft, hints.hints[attempt],
+		)
+	}
+	if _, err = usecase.UseBooster(ctx, userID, "reveal", nil); !errors.Is(err, model.ErrLineGameBoosterLimitExceeded) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrLineGameBoosterLimitExceeded, err)
+	}
+
+	if _, err = usecase.UseBooster(ctx, userID, "skip", nil); err != nil {
+		t.Fatal(err)
+	}
+	if progress.levelNum != 1 || progress.passedCount != 4 {
+		t.Errorf(
+			"Wrong progress. Expected level 1 and passed count 4, got %v and %v\n",
+			progress.levelNum, progress.passedCount,
+		)
+	}
+	if balance.softCurrency != 500 || inventory.inventory["reveal"] != 0 || inventory.inventory["skip"] != 0 {
+		t.Errorf("Wrong spending. Expected boosters from the inventory, got %v\n", inventory.inventory)
+	}
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/config"
+	line_solver "github.com/4units/mos-hack-game/back/internal/line-solver"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	logs "github.com/4units/mos-hack-game/back/pkg/logging"
+	"github.com/google/uuid"
+	"hash/fnv"
+	"time"
+)
+
+type LineGameDailyStorage interface {
+	GetLastDailyCompletion(ctx context.Context, userID uuid.UUID) (model.LineGameDailyCompletion, error)
+	AddDailyCompletion(ctx context.Context, userID uuid.UUID, completion model.LineGameDailyCompletion) error
+}
+
+type LineGameDailyUsecaseDeps struct {
+	LineGameDailyStorage    LineGameDailyStorage
+	BalanceUsecase          *BalanceUsecase
+	LeaderboardUsecase      *LeaderboardUsecase
+	BoosterInventoryUsecase *BoosterInventoryUsecase
+	LineGameConifgProvider
+}
+
+type LineGameDailyUsecase struct {
+	LineGameDailyUsecaseDeps
+	now func() time.Time
+}
+
+func NewLineGameDailyUsecase(deps LineGameDailyUsecaseDeps) *LineGameDailyUsecase {
+	return &LineGameDailyUsecase{
+		LineGameDailyUsecaseDeps: deps,
+		now:                      time.Now,
+	}
+}
+
+func (l *LineGameDailyUsecase) GetDaily(ctx context.Context, userID uuid.UUID) (model.LineGameDaily, error) {
+	dailyCfg := l.LineGameConifg().Daily
+	if !dailyCfg.Enabled {
+		return model.LineGameDaily{}, model.ErrLineGameDailyDisabled
+	}
+	day := l.today(dailyCfg)
+	level, err := generateDailyLevel(dailyCfg, day)
+	if err != nil {
+		return model.LineGameDaily{}, err
+	}
+	last, err := l.getLastCompletion(ctx, userID)
+	if err != nil {
+		return model.LineGameDaily{}, err
+	}
+	daily := model.LineGameDaily{
+		Day:       day,
+		Level:     level,
+		Completed: last.Day.Equal(day),
+	}
+	if daily.Completed || last.Day.Equal(day.AddDate(0, 0, -1)) {
+		daily.Streak = last.Streak
+	}
+	return daily, nil
+}
+
+// TryCompleteDaily checks the answer for the level of the current day and rewards the user once a day.
+func (l *LineGameDailyUsecase) TryCompleteDaily(
+	ctx context.Context,
+	userID uuid.UUID,
+	answer [][]int,
+	timeSinceStart time.Duration,
+) (model.LineGameDailyCompletion, error) {
+	dailyCfg := l.LineGameConifg().Daily
+	if !dailyCfg.Enabled {
+		return model.LineGameDailyCompletion{}, model.ErrLineGameDailyDisabled
+	}
+	day := l.today(dailyCfg)
+	level, err := generateDailyLevel(dailyCfg, day)
+	if err != nil {
+		return model.LineGameDailyCompletion{}, err
+	}
+	if err = level.CheckAnswer(answer); err != nil {
+		return model.LineGameDailyCompletion{}, err
+	}
+	last, err := l.getLastCompletion(ctx, userID)
+	if err != nil {
+		return model.LineGameDailyCompletion{}, err
+	}
+	if last.Day.Equal(day) {
+		return model.LineGameDailyCompletion{}, model.ErrLineGameDailyAlreadyCompleted
+	}
+	completion := model.LineGameDailyCompletion{
+		Day:            day,
+		TimeSinceStart: timeSinceStart,
+		Streak:         1,
+	}
+	if last.Day.Equal(day.AddDate(0, 0, -1)) {
+		completion.Streak = last.Streak + 1
+	}
+	rewardCfg := rewardByTime(dailyCfg.RewardsConditions, timeSinceStart)
+	completion.Reward = rewardCfg.SoftCurrency +
+		min((completion.Streak-1)*dailyCfg.StreakBonus, dailyCfg.MaxStreakBonus)
+	completion.RewardBoosters = rewardCfg.Boosters
+	if err = l.LineGameDailyStorage.AddDailyCompletion(ctx, userID, completion); err != nil {
+		return model.LineGameDailyCompletion{}, fmt.Errorf("failed to add daily completion: %w", err)
+	}
+	if err = l.BalanceUsecase.AddSoftCurrency(ctx, userID, completion.Reward); err != nil {
+		return model.LineGameDailyCompletion{}, fmt.Errorf("failed to update soft currency balance: %w", err)
+	}
+	if err = l.BoosterInventoryUsecase.GrantBoosters(ctx, userID, completion.RewardBoosters); err != nil {
+		return model.LineGameDailyCompletion{}, err
+	}
+	if err = l.LeaderboardUsecase.RecordDailyTime(ctx, userID, day, timeSinceStart); err != nil {
+		logs.Error("failed to record daily time", err)
+	}
+	return completion, nil
+}
+
+func (l *LineGameDailyUsecase) getLastCompletion(
+	ctx context.Context,
+	userID uuid.UUID,
+) (model.LineGameDailyCompletion, error) {
+	last, err := l.LineGameDailyStorage.GetLastDailyCompletion(ctx, userID)
+	if err != nil && !errors.Is(err, model.ErrUserHasNoDailyCompletion) {
+		return model.LineGameDailyCompletion{}, fmt.Errorf("failed to get last daily completion: %w", err)
+	}
+	return last, nil
+}
+
+func (l *LineGameDailyUsecase) today(dailyCfg config.LineGameDaily) time.Time {
+	return dailyDay(dailyCfg, l.now())
+}
+
+// dailyDay returns the date of the day in the configured time zone as midnight in UTC.
+func dailyDay(dailyCfg config.LineGameDaily, now time.Time) time.Time {
+	now = now.UTC().Add(time.Duration(dailyCfg.UTCOffset) * time.Hour)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func generateDailyLevel(dailyCfg config.LineGameDaily, day time.Time) (model.LineGameLevel, error) {
+	hash := fnv.New64a()
+	hash.Write([]byte("daily " + day.Format(time.DateOnly)))
+	level, err := line_solver.Generate(hash.Sum64(), dailyCfg.FieldSize, dailyCfg.Orders, dailyCfg.Blockers)
+	if err != nil {
+		return model.LineGameLevel{}, fmt.Errorf("failed to generate daily level: %w", err)
+	}
+	return level, nil
+}
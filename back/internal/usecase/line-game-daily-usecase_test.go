@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/4units/mos-hack-game/back/config"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type dailyStorageStub struct {
+	completions []model.LineGameDailyCompletion
+}
+
+func (d *dailyStorageStub) GetLastDailyCompletion(
+	_ context.Context, _ uuid.UUID,
+) (model.LineGameDailyCompletion, error) {
+	if len(d.completions) == 0 {
+		return model.LineGameDailyCompletion{}, model.ErrUserHasNoDailyCompletion
+	}
+	return d.completions[len(d.completions)-1], nil
+}
+
+func (d *dailyStorageStub) AddDailyCompletion(
+	_ context.Context, _ uuid.UUID, completion model.LineGameDailyCompletion,
+) error {
+	d.completions = append(d.completions, completion)
+	return nil
+}
+
+func TestLineGameDailyUsecase_TryCompleteDaily(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	balance := &balanceStorageStub{}
+	usecase := NewLineGameDailyUsecase(
+		LineGameDailyUsecaseDeps{
+			LineGameDailyStorage: &dailyStorageStub{},
+			BalanceUsecase:       NewBalanceUsecase(BalanceUsecaseDeps{BalanceStorage: balance}),
+			LeaderboardUsecase:   newLeaderboardUsecaseStub(),
+			LineGameConifgProvider: &lineGameConfigStub{
+				cfg: config.LineGame{
+					Daily: config.LineGameDaily{
+						Enabled:   true,
+						FieldSize: 5,
+						Orders:    2,
+						Blockers:  1,
+						UTCOffset: 3,
+						RewardsConditions: []config.LineGameRewardCondition{
+							{MaxTime: 30, Reward: config.LineGameReward{SoftCurrency: 100}},
+						},
+						StreakBonus:    10,
+						MaxStreakBonus: 15,
+					},
+				},
+			},
+		},
+	)
+	tests := []struct {
+		name           string
+		now            time.Time
+		expectedDay    string
+		expectedStreak int
+		expectedReward int
+		expectedErr    error
+	}{
+		{"first day", time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), "2025-03-01", 1, 100, nil},
+		{"same day", time.Date(2025, 3, 1, 20, 0, 0, 0, time.UTC), "2025-03-01", 1, 0, model.ErrLineGameDailyAlreadyCompleted},
+		{"next day in offset", time.Date(2025, 3, 1, 21, 0, 0, 0, time.UTC), "2025-03-02", 2, 110, nil},
+		{"third day", time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC), "2025-03-03", 3, 115, nil},
+		{"missed day", time.Date(2025, 3, 5, 9, 0, 0, 0, time.UTC), "2025-03-05", 1, 100, nil},
+	}
+	for _, test := range tests {
+		usecase.now = func() time.Time { return test.now }
+		daily, err := usecase.GetDaily(ctx, userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if day := daily.Day.Format(time.DateOnly); day != test.expectedDay {
+			t.Errorf("Wrong day in %v. Expected %v, got %v\n", test.name, test.expectedDay, day)
+		}
+		completion, err := usecase.TryCompleteDaily(ctx, userID, daily.Level.Answer, time.Second)
+		if !errors.Is(err, test.expectedErr) {
+			t.Fatalf("Wrong error in %v. Expected %v, got %v\n", test.name, test.expectedErr, err)
+		}
+		if err != nil {
+			continue
+		}
+		if completion.Streak != test.expectedStreak || completion.Reward != test.expectedReward {
+			t.Errorf(
+				"Wrong completion in %v. Expected streak %v and reward %v, got %v and %v\n",
+				test.name, test.expectedStreak, test.expectedReward, completion.Streak, completion.Reward,
+			)
+		}
+	}
+	if balance.softCurrency != 425 {
+		t.Errorf("Wrong balance. Expected 425, got %v\n", balance.softCurrency)
+	}
+}
+
+func TestLineGameDailyUsecase_GetDaily_SameForUsers(t *testing.T) {
+	ctx := context.Background()
+	usecase := NewLineGameDailyUsecase(
+		LineGameDailyUsecaseDeps{
+			LineGameDailyStorage: &dailyStorageStub{},
+			LineGameConifgProvider: &lineGameConfigStub{
+				cfg: config.LineGame{
+					Daily: config.LineGameDaily{Enabled: true, FieldSize: 6, Orders: 3, Blockers: 2},
+				},
+			},
+		},
+	)
+	first, err := usecase.GetDaily(ctx, uuid.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := usecase.GetDaily(ctx, uuid.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first.Level, second.Level) {
+		t.Errorf("Wrong daily level. Expected the same level for different users\n")
+	}
+}
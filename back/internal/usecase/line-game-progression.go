@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"github.com/4units/mos-hack-game/back/config"
+	"github.com/4units/mos-hack-game/back/internal/model"
+)
+
+// LineGameProgressionPolicy decides where the user goes after the completed level.
+type LineGameProgressionPolicy interface {
+	Name() string
+	// Decide gets recent results of the user in the group of the completed level, the newest result is first.
+	Decide(results []model.LineGameLevelResult) model.LineGameProgression
+}
+
+// LinearProgressionPolicy plays every level of every group in order.
+type LinearProgressionPolicy struct{}
+
+func (LinearProgressionPolicy) Name() string {
+	return config.LineGameProgressionLinear
+}
+
+func (LinearProgressionPolicy) Decide([]model.LineGameLevelResult) model.LineGameProgression {
+	return model.LineGameProgressionNext
+}
+
+// AdaptiveProgressionPolicy skips the rest of the group when the recent levels are completed fast
+// without hints and repeats the group when most of them are completed slowly or with hints.
+type AdaptiveProgressionPolicy struct {
+	cfg config.LineGameProgression
+}
+
+func NewAdaptiveProgressionPolicy(cfg config.LineGameProgression) AdaptiveProgressionPolicy {
+	return AdaptiveProgressionPolicy{cfg: cfg}
+}
+
+func (AdaptiveProgressionPolicy) Name() string {
+	return config.LineGameProgressionAdaptive
+}
+
+func (a AdaptiveProgressionPolicy) Decide(results []model.LineGameLevelResult) model.LineGameProgression {
+	if a.cfg.RecentLevels <= 0 || len(results) < a.cfg.RecentLevels {
+		return model.LineGameProgressionNext
+	}
+	fast, slow := 0, 0
+	for _, result := range results[:a.cfg.RecentLevels] {
+		switch {
+		case result.HintsCount == 0 && result.CellTime() <= a.cfg.FastCellTime:
+			fast++
+		case result.HintsCount > 0 || result.CellTime() >= a.cfg.SlowCellTime:
+			slow++
+		}
+	}
+	switch {
+	case fast == a.cfg.RecentLevels:
+		return model.LineGameProgressionSkipGroup
+	case slow*2 > a.cfg.RecentLevels:
+		return model.LineGameProgressionRepeatGroup
+	default:
+		return model.LineGameProgressionNext
+	}
+}
+
+func newLineGameProgressionPolicy(cfg config.LineGameProgression) LineGameProgressionPolicy {
+	if cfg.Policy == config.LineGameProgressionAdaptive {
+		return NewAdaptiveProgressionPolicy(cfg)
+	}
+	return LinearProgressionPolicy{}
+}
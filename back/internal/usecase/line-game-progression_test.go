@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"github.com/4units/mos-hack-game/back/config"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveProgressionPolicy_Decide(t *testing.T) {
+	policy := NewAdaptiveProgressionPolicy(
+		config.LineGameProgression{
+			Policy:       config.LineGameProgressionAdaptive,
+			RecentLevels: 3,
+			FastCellTime: 1,
+			SlowCellTime: 3,
+		},
+	)
+	fast := model.LineGameLevelResult{Cells: 10, TimeSinceStart: 5 * time.Second}
+	normal := model.LineGameLevelResult{Cells: 10, TimeSinceStart: 20 * time.Second}
+	slow := model.LineGameLevelResult{Cells: 10, TimeSinceStart: 40 * time.Second}
+	fastWithHint := model.LineGameLevelResult{Cells: 10, TimeSinceStart: 5 * time.Second, HintsCount: 1}
+	tests := []struct {
+		name     string
+		results  []model.LineGameLevelResult
+		expected model.LineGameProgression
+	}{
+		{"not enough results", []model.LineGameLevelResult{fast, fast}, model.LineGameProgressionNext},
+		{"all fast", []model.LineGameLevelResult{fast, fast, fast}, model.LineGameProgressionSkipGroup},
+		{"old results are ignored", []model.LineGameLevelResult{fast, fast, fast, slow}, model.LineGameProgressionSkipGroup},
+		{"fast with hint", []model.LineGameLevelResult{fast, fastWithHint, fast}, model.LineGameProgressionNext},
+		{"mostly slow", []model.LineGameLevelResult{slow, fastWithHint, fast}, model.LineGameProgressionRepeatGroup},
+		{"mixed", []model.LineGameLevelResult{fast, normal, slow}, model.LineGameProgressionNext},
+	}
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				if decision := policy.Decide(test.results); decision != test.expected {
+					t.Errorf("Wrong decision. Expected %v, got %v\n", test.expected, decision)
+				}
+			},
+		)
+	}
+}
+
+func TestLineGameUsecase_TryCompleteUserLevel_Adaptive(t *testing.T) {
+	level := model.LineGameLevel{FieldSize: 3}
+	newUsecase := func(progress *progressStorageStub, results *levelResultStorageStub) *LineGameUsecase {
+		return NewLineGameUsecase(
+			LineGameUsecaseDeps{
+				LineGameLevelStorage: &levelStorageStub{
+					levels: map[model.LineGameLevelGroupCode][]model.LineGameLevel{
+						"3_0_0": {level, level, level, level},
+						"4_0_0": {level, level},
+					},
+					order: []model.LineGameLevelGroupCode{"3_0_0", "4_0_0"},
+				},
+				LineGameProgressStorage:    progress,
+				LineGameHintStorage:        &hintStorageStub{},
+				LineGameLevelResultStorage: results,
+				LineGameAttemptStorage:     &levelAttemptStorageStub{},
+				LineGameStarStorage:        &starStorageStub{},
+				EnergyUsecase:              newEnergyUsecaseStub(config.Energy{}),
+				BalanceUsecase:             NewBalanceUsecase(BalanceUsecaseDeps{BalanceStorage: &balanceStorageStub{}}),
+				LeaderboardUsecase:         newLeaderboardUsecaseStub(),
+				LineGameConifgProvider: &lineGameConfigStub{
+					cfg: config.LineGame{
+						Progression: config.LineGameProgression{
+							Policy:       config.LineGameProgressionAdaptive,
+							RecentLevels: 2,
+							FastCellTime: 1,
+							SlowCellTime: 3,
+						},
+					},
+				},
+			},
+		)
+	}
+	tests := []struct {
+		name     string
+		levelNum int
+		times    []time.Duration
+		expected []int
+		// expectedCode is the group after the last completion
+		expectedCode model.LineGameLevelGroupCode
+	}{
+		{"fast player skips the group", 0, []time.Duration{5 * time.Second, 5 * time.Second}, []int{1, 0}, "4_0_0"},
+		{"slow player repeats the group", 2, []time.Duration{time.Minute, time.Minute}, []int{3, 0}, "3_0_0"},
+		{"normal player goes linear", 2, []time.Duration{15 * time.Second, 15 * time.Second}, []int{3, 0}, "4_0_0"},
+	}
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				progress := &progressStorageStub{groupCode: "3_0_0", levelNum: test.levelNum}
+				results := &levelResultStorageStub{}
+				usecase := newUsecase(progress, results)
+				now := time.Now()
+				usecase.now = func() time.Time { return now }
+				userID := uuid.New()
+				for i, timeSinceStart := range test.times {
+					if _, err := usecase.GetUserLevel(context.Background(), userID); err != nil {
+						t.Fatal(err)
+					}
+					now = now.Add(timeSinceStart)
+					if _, err := usecase.TryCompleteUserLevel(context.Background(), userID, nil); err != nil {
+						t.Fatal(err)
+					}
+					if progress.levelNum != test.expected[i] {
+						t.Errorf("Wrong level num after completion %v. Expected %v, got %v\n", i, test.expected[i], progress.levelNum)
+					}
+				}
+				if progress.groupCode != test.expectedCode {
+					t.Errorf("Wrong group code. Expected %v, got %v\n", test.expectedCode, progress.groupCode)
+				}
+				if len(results.results) != len(test.times) || results.results[0].Policy != config.LineGameProgressionAdaptive {
+					t.Errorf("Wrong saved results. Expected %v adaptive results, got %+v\n", len(test.times), results.results)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/config"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+type LineGameStarStorage interface {
+	// SaveLevelStars must keep the best stars and time of the level and return the stars added over the best
+	SaveLevelStars(ctx context.Context, userID uuid.UUID, stars model.LineGameLevelStars) (int, error)
+	GetLevelStars(
+		ctx context.Context,
+		userID uuid.UUID,
+		groupCode model.LineGameLevelGroupCode,
+	) ([]model.LineGameLevelStars, error)
+}
+
+// GetLevelStars returns the best ratings of completed levels of the group or of all groups when the code is empty.
+func (l *LineGameUsecase) GetLevelStars(
+	ctx context.Context,
+	userID uuid.UUID,
+	groupCode model.LineGameLevelGroupCode,
+) ([]model.LineGameLevelStars, error) {
+	stars, err := l.LineGameStarStorage.GetLevelStars(ctx, userID, groupCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get level stars: %w", err)
+	}
+	return stars, nil
+}
+
+// nextStarThreshold returns the slowest tier with more stars than the tier of the completion,
+// it is nil when there is no such tier.
+func nextStarThreshold(
+	conditions []config.LineGameRewardCondition,
+	starsCfg config.LineGameStars,
+	tier int,
+) *model.LineGameStarThreshold {
+	stars := starsCfg.StarsByTier(tier)
+	for i := tier - 1; i >= 0; i-- {
+		if tierStars := starsCfg.StarsByTier(i); tierStars > stars {
+			return &model.LineGameStarThreshold{
+				Stars:   tierStars,
+				MaxTime: time.Duration(conditions[i].MaxTime * float64(time.Second)),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/4units/mos-hack-game/back/config"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+type starStorageStub struct {
+	levels []model.LineGameLevelStars
+}
+
+func (s *starStorageStub) SaveLevelStars(_ context.Context, _ uuid.UUID, stars model.LineGameLevelStars) (int, error) {
+	for i, level := range s.levels {
+		if level.GroupCode == stars.GroupCode && level.LevelNum == stars.LevelNum {
+			s.levels[i].Stars = max(level.Stars, stars.Stars)
+			s.levels[i].BestTime = min(level.BestTime, stars.BestTime)
+			return max(stars.Stars-level.Stars, 0), nil
+		}
+	}
+	s.levels = append(s.levels, stars)
+	return stars.Stars, nil
+}
+
+func (s *starStorageStub) GetLevelStars(
+	_ context.Context, _ uuid.UUID, groupCode model.LineGameLevelGroupCode,
+) ([]model.LineGameLevelStars, error) {
+	levels := make([]model.LineGameLevelStars, 0, len(s.levels))
+	for _, level := range s.levels {
+		if groupCode == "" || level.GroupCode == groupCode {
+			levels = append(levels, level)
+		}
+	}
+	return levels, nil
+}
+
+func TestNextStarThreshold(t *testing.T) {
+	conditions := []config.LineGameRewardCondition{
+		{MaxTime: 10}, {MaxTime: 40}, {MaxTime: 90}, {MaxTime: 200},
+	}
+	tests := []struct {
+		name          string
+		starsCfg      config.LineGameStars
+		time          time.Duration
+		expectedStars int
+		expectedNext  *model.LineGameStarThreshold
+	}{
+		{"best tier", config.LineGameStars{}, 5 * time.Second, 3, nil},
+		{
+			"second tier",
+			config.LineGameStars{},
+			20 * time.Second,
+			2,
+			&model.LineGameStarThreshold{Stars: 3, MaxTime: 10 * time.Second},
+		},
+		{
+			"slower than all",
+			config.LineGameStars{},
+			time.Hour,
+			1,
+			&model.LineGameStarThreshold{Stars: 2, MaxTime: 40 * time.Second},
+		},
+		{
+			"custom mapping",
+			config.LineGameStars{ByRewardTier: []int{3, 3, 2}},
+			time.Hour,
+			2,
+			&model.LineGameStarThreshold{Stars: 3, MaxTime: 40 * time.Second},
+		},
+	}
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				tier := rewardTier(conditions, test.time)
+				if stars := test.starsCfg.StarsByTier(tier); stars != test.expectedStars {
+					t.Errorf("Wrong stars. Expected %v, got %v\n", test.expectedStars, stars)
+				}
+				next := nextStarThreshold(conditions, test.starsCfg, tier)
+				if (next == nil) != (test.expectedNext == nil) || next != nil && *next != *test.expectedNext {
+					t.Errorf("Wrong next star. Expected %v, got %v\n", test.expectedNext, next)
+				}
+			},
+		)
+	}
+}
+
+func TestLineGameUsecase_TryCompleteUserLevel_SavesBestStars(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	level := model.LineGameLevel{
+		FieldSize: 2,
+		Start:     model.LineGameLevelCell{X: 0, Y: 0},
+		End:       model.LineGameLevelCell{X: 0, Y: 1},
+		Answer:    [][]int{{1, 2}, {4, 3}},
+	}
+	stars := &starStorageStub{}
+	progress := &progressStorageStub{groupCode: "2_0_0"}
+	leaderboard := newLeaderboardUsecaseStub()
+	usecase := NewLineGameUsecase(
+		LineGameUsecaseDeps{
+			LineGameLevelStorage: &levelStorageStub{
+				levels: map[model.LineGameLevelGroupCode][]model.LineGameLevel{"2_0_0": {level}},
+			},
+			LineGameProgressStorage:    progress,
+			LineGameHintStorage:        &hintStorageStub{},
+			LineGameLevelResultStorage: &levelResultStorageStub{},
+			LineGameAttemptStorage:     &levelAttemptStorageStub{},
+			LineGameStarStorage:        stars,
+			EnergyUsecase:              newEnergyUsecaseStub(config.Energy{}),
+			BalanceUsecase:             NewBalanceUsecase(BalanceUsecaseDeps{BalanceStorage: &balanceStorageStub{}}),
+			LeaderboardUsecase:         leaderboard,
+			LineGameConifgProvider: &lineGameConfigStub{
+				cfg: config.LineGame{
+					HintSteps: 1,
+					RewardsConditions: []config.LineGameRewardCondition{
+						{MaxTime: 10, Reward: config.LineGameReward{SoftCurrency: 30}},
+						{MaxTime: 60, Reward: config.LineGameReward{SoftCurrency: 10}},
+					},
+				},
+			},
+		},
+	)
+
+	now := time.Date(2025, 10, 19, 12, 0, 0, 0, time.UTC)
+	usecase.now = func() time.Time { return now }
+	if _, err := usecase.TryCompleteUserLevel(ctx, userID, level.Answer); !errors.Is(
+		err, model.ErrLineGameLevelNotStarted,
+	) {
+		t.Errorf("Wrong error. Expected %v for the level not requested, got %v\n", model.ErrLineGameLevelNotStarted, err)
+	}
+	for _, timeSinceStart := range []time.Duration{5 * time.Second, 30 * time.Second} {
+		// the only level of the last group is repeated
+		progress.groupCode, progress.levelNum = "2_0_0", 0
+		// a repeated request of the level does not restart its time
+		for range 2 {
+			if _, err := usecase.GetUserLevel(ctx, userID); err != nil {
+				t.Fatal(err)
+			}
+			now = now.Add(timeSinceStart / 2)
+		}
+		reward, err := usecase.TryCompleteUserLevel(ctx, userID, level.Answer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reward.TimeSinceStart != timeSinceStart {
+			t.Errorf("Wrong counted time. Expected %v, got %v\n", timeSinceStart, reward.TimeSinceStart)
+		}
+	}
+	levels, err := usecase.GetLevelStars(ctx, userID, "2_0_0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(levels) != 1 || levels[0].Stars != 3 || levels[0].BestTime != 5*time.Second {
+		t.Errorf("Wrong level stars. Expected 3 stars for 5s, got %v\n", levels)
+	}
+	// the slower replay earns fewer stars than the best, so it adds nothing to the board
+	board, err := leaderboard.GetLeaderboard(
+		ctx, userID, model.LeaderboardQuery{Metric: model.LeaderboardStars, Period: model.LeaderboardGlobal, Limit: 1},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if board.User == nil || board.User.Score != 3 {
+		t.Errorf("Wrong leaderboard stars. Expected 3, got %+v\n", board.User)
+	}
+}
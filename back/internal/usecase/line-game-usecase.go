@@ -2,19 +2,25 @@ package usecase
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"github.com/4units/mos-hack-game/back/config"
+	line_solver "github.com/4units/mos-hack-game/back/internal/line-solver"
 	"github.com/4units/mos-hack-game/back/internal/model"
 	http_errors "github.com/4units/mos-hack-game/back/pkg/http-errors"
+	logs "github.com/4units/mos-hack-game/back/pkg/logging"
 	"github.com/google/uuid"
+	"hash/fnv"
 	"log/slog"
 	"net/http"
+	"slices"
+	"sync"
 	"time"
 )
 
 var (
-	ErrNotEnoughSoftCurrency = http_errors.NewSame("not enough soft currency", http.StatusForbidden)
+	ErrNotEnoughSoftCurrency = model.ErrNotEnoughSoftCurrency
 )
 
 type LineLevelStorage interface {
@@ -24,8 +30,14 @@ type LineLevelStorage interface {
 		ctx context.Context, currentGroupCode model.LineGameLevelGroupCode,
 		currentLevelNum int,
 	) (model.LineGameLevelGroupCode, int, error)
+	GetNextGroupLevel(ctx context.Context, currentGroupCode model.LineGameLevelGroupCode) (
+		model.LineGameLevelGroupCode,
+		int,
+		error,
+	)
 	GetClosestLowOrDefaultLevel(ctx context.Context, id model.LineGameLevelGroupCode, num int) (
-		model.LineGameLevel,
+		model.LineGameLevelGroupCode,
+		int,
 		error,
 	)
 }
@@ -46,6 +58,39 @@ type LineLevelProgressStorage interface {
 	AddUserLineGameLevel(ctx context.Context, id uuid.UUID, groupCode model.LineGameLevelGroupCode, levelNum int) error
 }
 
+type LineGameHintStorage interface {
+	GetLevelHints(ctx context.Context, userID uuid.UUID, attempt model.LineGameLevelAttempt) (
+		[]model.LineGameHint,
+		error,
+	)
+	// BuyLevelHint must save the hint and spend its price atomically, it returns false and spends nothing
+	// when the same hint is already saved for the attempt
+	BuyLevelHint(
+		ctx context.Context,
+		userID uuid.UUID,
+		attempt model.LineGameLevelAttempt,
+		hint model.LineGameHint,
+	) (bool, error)
+}
+
+type LineGameLevelResultStorage interface {
+	AddLevelResult(ctx context.Context, userID uuid.UUID, passedCount int, result model.LineGameLevelResult) error
+	GetRecentLevelResults(
+		ctx context.Context,
+		userID uuid.UUID,
+		groupCode model.LineGameLevelGroupCode,
+		limit int,
+	) ([]model.LineGameLevelResult, error)
+}
+
+// LineGameAttemptStorage keeps starts of level attempts, so the completion time is counted by the server
+type LineGameAttemptStorage interface {
+	// StartLevelAttempt must keep the first start of the attempt with the passed count
+	StartLevelAttempt(ctx context.Context, userID uuid.UUID, passedCount int, startedAt time.Time) error
+	// GetLevelAttemptStart returns ErrLineGameLevelNotStarted when the attempt is not started
+	GetLevelAttemptStart(ctx context.Context, userID uuid.UUID, passedCount int) (time.Time, error)
+}
+
 type LineGameConifgProvider interface {
 	LineGameConifg() *config.LineGame
 }
@@ -55,167 +100,572 @@ type PriceConifgProvider interface {
 }
 
 type LineGameUsecaseDeps struct {
-	LineGameLevelStorage    LineLevelStorage
-	LineGameProgressStorage LineLevelProgressStorage
+	LineGameLevelStorage       LineLevelStorage
+	LineGameProgressStorage    LineLevelProgressStorage
+	LineGameHintStorage        LineGameHintStorage
+	LineGameLevelResultStorage LineGameLevelResultStorage
+	LineGameAttemptStorage     LineGameAttemptStorage
+	LineGameBoosterStorage     LineGameBoosterStorage
+	LineGameStarStorage        LineGameStarStorage
+	// ProgressionPolicy overrides the policy from the config when it is set
+	ProgressionPolicy       LineGameProgressionPolicy
 	BalanceUsecase          *BalanceUsecase
+	LeaderboardUsecase      *LeaderboardUsecase
+	EnergyUsecase           *EnergyUsecase
+	BoosterInventoryUsecase *BoosterInventoryUsecase
 	LineGameConifgProvider
 	PriceConifgProvider
+	BoostersConfigProvider
 }
 type LineGameUsecase struct {
 	LineGameUsecaseDeps
+	// endlessLevels keeps the last generated endless level of every user, so the level is generated
+	// once for all requests of its attempt
+	endlessMu     sync.Mutex
+	endlessLevels map[uuid.UUID]endlessLevel
+	now           func() time.Time
+}
+
+// maxEndlessLevelsCached is a count of users which endless levels are kept, the cache is cleared above it
+const maxEndlessLevelsCached = 10_000
+
+type endlessLevel struct {
+	levelNum int
+	step     config.LineGameEndlessStep
+	level    model.LineGameLevel
 }
 
 func NewLineGameUsecase(
 	deps LineGameUsecaseDeps,
 ) *LineGameUsecase {
-	return &LineGameUsecase{LineGameUsecaseDeps: deps}
+	return &LineGameUsecase{
+		LineGameUsecaseDeps: deps,
+		endlessLevels:       make(map[uuid.UUID]endlessLevel),
+		now:                 time.Now,
+	}
 }
 
+// GetUserLevel returns the current level of the user, the start of the level attempt costs energy.
+// The first request of the attempt starts its time.
 func (l *LineGameUsecase) GetUserLevel(ctx context.Context, userID uuid.UUID) (model.LineGameLevel, error) {
+	level, attempt, err := l.getUserLevel(ctx, userID)
+	if err != nil {
+		return model.LineGameLevel{}, err
+	}
+	if err = l.EnergyUsecase.SpendLevelEnergy(ctx, userID, attempt.PassedCount); err != nil {
+		return model.LineGameLevel{}, err
+	}
+	if err = l.LineGameAttemptStorage.StartLevelAttempt(ctx, userID, attempt.PassedCount, l.now()); err != nil {
+		return model.LineGameLevel{}, fmt.Errorf("failed to start level attempt: %w", err)
+	}
+	return level, nil
+}
+
+// getUserLevel returns the current level of the user with hints bought for it and the attempt of the level.
+func (l *LineGameUsecase) getUserLevel(ctx context.Context, userID uuid.UUID) (
+	model.LineGameLevel,
+	model.LineGameLevelAttempt,
+	error,
+) {
 	groupCode, levelNum, passedCount, err := l.LineGameProgressStorage.GetUserLineGameLevel(ctx, userID)
 	var level model.LineGameLevel
 	if err != nil {
 		if errors.Is(err, model.ErrUserHasNotLineGameProgress) {
 			groupCode, err = l.LineGameLevelStorage.GetStartGroupCode(ctx)
 			if err != nil {
-				return model.LineGameLevel{}, fmt.Errorf("failed to get start group code: %w", err)
+				return model.LineGameLevel{}, model.LineGameLevelAttempt{}, fmt.Errorf(
+					"failed to get start group code: %w", err,
+				)
 			}
 			level, err = l.LineGameLevelStorage.GetLevel(ctx, groupCode, 0)
 			if err != nil {
-				return model.LineGameLevel{}, fmt.Errorf("faield to get level: %w", err)
+				return model.LineGameLevel{}, model.LineGameLevelAttempt{}, fmt.Errorf("faield to get level: %w", err)
 			}
 			err = l.LineGameProgressStorage.AddUserLineGameLevel(ctx, userID, groupCode, 0)
 			if err != nil {
-				return model.LineGameLevel{}, fmt.Errorf("failed to update player progress level: %w", err)
+				return model.LineGameLevel{}, model.LineGameLevelAttempt{}, fmt.Errorf(
+					"failed to update player progress level: %w", err,
+				)
 			}
 		} else {
-			return model.LineGameLevel{}, fmt.Errorf("failed to get user level: %w", err)
+			return model.LineGameLevel{}, model.LineGameLevelAttempt{}, fmt.Errorf("failed to get user level: %w", err)
 		}
 	} else {
-		level, err = l.LineGameLevelStorage.GetLevel(ctx, groupCode, levelNum)
+		level, groupCode, levelNum, err = l.getProgressLevel(ctx, userID, groupCode, levelNum, passedCount)
 		if err != nil {
-			if errors.Is(err, model.ErrLineGameNotExistsLevelInStorage) {
-				slog.Warn(
-					"User level not exists in the storage", slog.String("group_code", string(groupCode)),
-					slog.Int("level_num", levelNum),
-				)
-				level, err = l.LineGameLevelStorage.GetClosestLowOrDefaultLevel(ctx, groupCode, levelNum)
-			} else {
-				return model.LineGameLevel{}, fmt.Errorf("failed to get level: %w", err)
-			}
+			return model.LineGameLevel{}, model.LineGameLevelAttempt{}, err
 		}
 	}
+	attempt := model.LineGameLevelAttempt{GroupCode: groupCode, LevelNum: levelNum, PassedCount: passedCount}
+	level.Hints, err = l.LineGameHintStorage.GetLevelHints(ctx, userID, attempt)
+	if err != nil {
+		return model.LineGameLevel{}, model.LineGameLevelAttempt{}, fmt.Errorf("failed to get level hints: %w", err)
+	}
 	level.PassedCount = passedCount
-	return level, nil
+	return level, attempt, nil
 }
 
+// getProgressLevel returns the level of the user progress. When the level is not in the storage anymore,
+// the closest existing level is returned and the progress is moved to it.
+func (l *LineGameUsecase) getProgressLevel(
+	ctx context.Context,
+	userID uuid.UUID,
+	groupCode model.LineGameLevelGroupCode,
+	levelNum, passedCount int,
+) (model.LineGameLevel, model.LineGameLevelGroupCode, int, error) {
+	level, err := l.getLevel(ctx, userID, groupCode, levelNum)
+	if err == nil {
+		return level, groupCode, levelNum, nil
+	}
+	if !errors.Is(err, model.ErrLineGameNotExistsLevelInStorage) {
+		return model.LineGameLevel{}, "", 0, fmt.Errorf("failed to get level: %w", err)
+	}
+	closestGroupCode, closestLevelNum, err := l.LineGameLevelStorage.GetClosestLowOrDefaultLevel(
+		ctx, groupCode, levelNum,
+	)
+	if err != nil {
+		return model.LineGameLevel{}, "", 0, fmt.Errorf("failed to get closest level: %w", err)
+	}
+	slog.Warn(
+		"User level not exists in the storage, move to closest level",
+		slog.String("user_id", userID.String()),
+		slog.String("group_code", string(groupCode)), slog.Int("level_num", levelNum),
+		slog.String("closest_group_code", string(closestGroupCode)), slog.Int("closest_level_num", closestLevelNum),
+	)
+	level, err = l.LineGameLevelStorage.GetLevel(ctx, closestGroupCode, closestLevelNum)
+	if err != nil {
+		return model.LineGameLevel{}, "", 0, fmt.Errorf("failed to get closest level: %w", err)
+	}
+	if err = l.LineGameProgressStorage.UpdateUserLineGameLevel(
+		ctx, userID, closestGroupCode, passedCount, closestLevelNum,
+	); err != nil {
+		return model.LineGameLevel{}, "", 0, fmt.Errorf("failed to repair user progress: %w", err)
+	}
+	return level, closestGroupCode, closestLevelNum, nil
+}
+
+// TryCompleteUserLevel completes the level attempt started by GetUserLevel, the completion time is counted
+// from the start of the attempt.
 func (l *LineGameUsecase) TryCompleteUserLevel(
 	ctx context.Context,
 	userID uuid.UUID,
 	answer [][]int,
-	timeSinceStart time.Duration,
 ) (model.LineGameReward, error) {
 	groupCode, levelNum, passedCount, err := l.LineGameProgressStorage.GetUserLineGameLevel(ctx, userID)
 	if err != nil {
 		return model.LineGameReward{}, fmt.Errorf("failed to get user level: %w", err)
 	}
 
-	if l.LineGameConifg().CheckAnswer {
-		level, err := l.LineGameLevelStorage.GetLevel(ctx, groupCode, levelNum)
-		if err != nil {
-			if errors.Is(err, model.ErrLineGameNotExistsLevelInStorage) {
-				level, err = l.LineGameLevelStorage.GetClosestLowOrDefaultLevel(ctx, groupCode, levelNum)
-				if err != nil {
-					return model.LineGameReward{}, fmt.Errorf("failed to get closest level: %w", err)
-				}
-			} else {
-				return model.LineGameReward{}, fmt.Errorf("failed to get level: %w", err)
-			}
-		}
-		if level.FieldSize != len(answer) {
-			return model.LineGameReward{}, model.ErrLineGameFieldSizeNotEqual
-		}
-		verifiedOrders := 0
-		x, y := level.Start.X, level.Start.Y
-		checkedCells := 1
-
-	Loop:
-		for x != level.End.X || y != level.End.Y {
-			numberToMove := answer[y][x]
-			switch numberToMove {
-			case 0:
-				y--
-			case 1:
-				x++
-			case 2:
-				y++
-			case 3:
-				x--
-			default:
-				break Loop
-			}
-			checkedCells++
-			if verifiedOrders+1 <= len(level.Order) {
-				orderCell := level.Order[verifiedOrders]
-				if orderCell.X == x && orderCell.Y == y {
-					verifiedOrders++
-				}
-			}
-			if x < 0 || x >= level.FieldSize || y < 0 || y >= level.FieldSize {
-				return model.LineGameReward{}, model.ErrLineGameAnswerOutOfBorders
-			}
-		}
-		if verifiedOrders != len(level.Order) {
-			return model.LineGameReward{}, model.ErrLineGameAnswerOrderIncorrect
-		}
-		expectedCellsInWay := level.FieldSize*level.FieldSize - len(level.Blockers)
-		if checkedCells != expectedCellsInWay {
-			return model.LineGameReward{}, fmt.Errorf(
-				"cells in way %v, expected %v error: %w", checkedCells, expectedCellsInWay,
-				model.ErrLineGameAnswerCellsWayIncorrect,
-			)
+	level, groupCode, levelNum, err := l.getProgressLevel(ctx, userID, groupCode, levelNum, passedCount)
+	if err != nil {
+		return model.LineGameReward{}, err
+	}
+	// the attempt is paid before its start, so a started attempt needs no energy here
+	startedAt, err := l.LineGameAttemptStorage.GetLevelAttemptStart(ctx, userID, passedCount)
+	if err != nil {
+		return model.LineGameReward{}, fmt.Errorf("failed to get level attempt start: %w", err)
+	}
+	timeSinceStart := l.now().Sub(startedAt)
+	// generated levels are always checked because they are reproducible by the user seed
+	if l.LineGameConifg().CheckAnswer || level.Endless {
+		if err = level.CheckAnswer(answer); err != nil {
+			return model.LineGameReward{}, err
 		}
 	}
 
-	nextGroupCode, nextLevelNum, err := l.LineGameLevelStorage.GetNextLevel(ctx, groupCode, levelNum)
+	attempt := model.LineGameLevelAttempt{GroupCode: groupCode, LevelNum: levelNum, PassedCount: passedCount}
+	result, err := l.decideProgression(ctx, userID, level, attempt, timeSinceStart)
+	if err != nil {
+		return model.LineGameReward{}, err
+	}
+	nextGroupCode, nextLevelNum, err := l.getNextLevel(ctx, userID, groupCode, levelNum, result.Progression)
 	if err != nil {
 		return model.LineGameReward{}, fmt.Errorf("failed to get next level: %w", err)
 	}
+	if err = l.LineGameLevelResultStorage.AddLevelResult(ctx, userID, passedCount, result); err != nil {
+		return model.LineGameReward{}, fmt.Errorf("failed to add level result: %w", err)
+	}
 	if err = l.LineGameProgressStorage.UpdateUserLineGameLevel(
 		ctx, userID, nextGroupCode, passedCount+1, nextLevelNum,
 	); err != nil {
 		return model.LineGameReward{}, fmt.Errorf("failed to update next level: %w", err)
 	}
 
-	rewardCfg := l.LineGameConifg().RewardsConditions[len(l.LineGameConifg().RewardsConditions)-1].Reward
-	for _, condition := range l.LineGameConifg().RewardsConditions {
-		if condition.MaxTime > timeSinceStart.Seconds() {
-			rewardCfg = condition.Reward
-			break
+	lineGameCfg := l.LineGameConifg()
+	conditions := lineGameCfg.GroupRewardsConditions(
+		string(groupCode), level.FieldSize, len(level.Order), len(level.Blockers),
+	)
+	tier := rewardTier(conditions, timeSinceStart)
+	rewardCfg := rewardByTime(conditions, timeSinceStart)
+	reward := model.LineGameReward{
+		SoftCurrency:   rewardCfg.SoftCurrency,
+		Boosters:       rewardCfg.Boosters,
+		Stars:          lineGameCfg.Stars.StarsByTier(tier),
+		TimeSinceStart: timeSinceStart,
+		NextStar:       nextStarThreshold(conditions, lineGameCfg.Stars, tier),
+	}
+	// generated levels are unique for every user, so they are not shown on the level map
+	var addedStars int
+	if !level.Endless {
+		if addedStars, err = l.LineGameStarStorage.SaveLevelStars(
+			ctx, userID, model.LineGameLevelStars{
+				GroupCode: groupCode,
+				LevelNum:  levelNum,
+				Stars:     reward.Stars,
+				BestTime:  timeSinceStart,
+			},
+		); err != nil {
+			return model.LineGameReward{}, fmt.Errorf("failed to save level stars: %w", err)
 		}
 	}
 	if err = l.BalanceUsecase.AddSoftCurrency(ctx, userID, rewardCfg.SoftCurrency); err != nil {
 		return model.LineGameReward{}, fmt.Errorf("failed to update soft currency balance: %w", err)
 	}
-	return model.LineGameReward{
-		SoftCurrency: rewardCfg.SoftCurrency,
-	}, nil
+	if err = l.BoosterInventoryUsecase.GrantBoosters(ctx, userID, rewardCfg.Boosters); err != nil {
+		return model.LineGameReward{}, err
+	}
+	l.recordLeaderboards(ctx, userID, level, groupCode, levelNum, timeSinceStart, addedStars)
+	return reward, nil
 }
 
+// recordLeaderboards saves the result on leaderboards, failures are only logged to not lose the completion.
+func (l *LineGameUsecase) recordLeaderboards(
+	ctx context.Context,
+	userID uuid.UUID,
+	level model.LineGameLevel,
+	groupCode model.LineGameLevelGroupCode,
+	levelNum int,
+	timeSinceStart time.Duration,
+	stars int,
+) {
+	// generated levels are unique for every user, so their times are not compared
+	if !level.Endless {
+		if err := l.LeaderboardUsecase.RecordLevelTime(ctx, userID, groupCode, levelNum, timeSinceStart); err != nil {
+			logs.Error("failed to record level time", err)
+		}
+	}
+	// only stars above the best of the level are added, so replaying a level does not farm the board
+	if stars > 0 {
+		if err := l.LeaderboardUsecase.AddStars(ctx, userID, stars); err != nil {
+			logs.Error("failed to add leaderboard stars", err)
+		}
+	}
+}
+
+// rewardByTime returns the reward of the first condition with greater max time or the reward of the last one.
+func rewardByTime(
+	conditions []config.LineGameRewardCondition,
+	timeSinceStart time.Duration,
+) config.LineGameReward {
+	tier := rewardTier(conditions, timeSinceStart)
+	if tier < 0 {
+		return config.LineGameReward{}
+	}
+	return conditions[tier].Reward
+}
+
+// rewardTier returns the index of the condition of the reward by time, it is -1 when there are no conditions.
+func rewardTier(conditions []config.LineGameRewardCondition, timeSinceStart time.Duration) int {
+	for i, condition := range conditions {
+		if condition.MaxTime > timeSinceStart.Seconds() {
+			return i
+		}
+	}
+	return len(conditions) - 1
+}
+
+// getLevel returns the level from the storage or generates it for the endless mode.
+func (l *LineGameUsecase) getLevel(
+	ctx context.Context,
+	userID uuid.UUID,
+	groupCode model.LineGameLevelGroupCode,
+	levelNum int,
+) (model.LineGameLevel, error) {
+	if groupCode == model.LineGameEndlessGroupCode {
+		return l.generateEndlessLevel(userID, levelNum)
+	}
+	return l.LineGameLevelStorage.GetLevel(ctx, groupCode, levelNum)
+}
+
+// decideProgression returns the result of the completed level with the decision of the progression policy.
+// Endless levels are always played one by one.
+func (l *LineGameUsecase) decideProgression(
+	ctx context.Context,
+	userID uuid.UUID,
+	level model.LineGameLevel,
+	attempt model.LineGameLevelAttempt,
+	timeSinceStart time.Duration,
+) (model.LineGameLevelResult, error) {
+	hints, err := l.LineGameHintStorage.GetLevelHints(ctx, userID, attempt)
+	if err != nil {
+		return model.LineGameLevelResult{}, fmt.Errorf("failed to get level hints: %w", err)
+	}
+	policy := l.progressionPolicy()
+	result := model.LineGameLevelResult{
+		GroupCode:      attempt.GroupCode,
+		LevelNum:       attempt.LevelNum,
+		Cells:          level.FieldSize*level.FieldSize - len(level.Blockers),
+		TimeSinceStart: timeSinceStart,
+		HintsCount:     len(hints),
+		Policy:         policy.Name(),
+		Progression:    model.LineGameProgressionNext,
+	}
+	if level.Endless {
+		return result, nil
+	}
+	results := []model.LineGameLevelResult{result}
+	if recentLevels := l.LineGameConifg().Progression.RecentLevels; recentLevels > 0 {
+		recent, err := l.LineGameLevelResultStorage.GetRecentLevelResults(ctx, userID, attempt.GroupCode, recentLevels)
+		if err != nil {
+			return model.LineGameLevelResult{}, fmt.Errorf("failed to get recent level results: %w", err)
+		}
+		results = append(results, recent...)
+	}
+	result.Progression = policy.Decide(results)
+	slog.Info(
+		"Line game progression decision",
+		slog.String("user_id", userID.String()),
+		slog.String("group_code", string(attempt.GroupCode)), slog.Int("level_num", attempt.LevelNum),
+		slog.String("policy", result.Policy), slog.String("progression", string(result.Progression)),
+		slog.Float64("cell_time", result.CellTime()), slog.Int("hints_count", result.HintsCount),
+	)
+	return result, nil
+}
+
+func (l *LineGameUsecase) progressionPolicy() LineGameProgressionPolicy {
+	if l.ProgressionPolicy != nil {
+		return l.ProgressionPolicy
+	}
+	return newLineGameProgressionPolicy(l.LineGameConifg().Progression)
+}
+
+// getNextLevel moves the user to the endless mode after the last level group.
+// When the endless mode is disabled the last level is played again.
+func (l *LineGameUsecase) getNextLevel(
+	ctx context.Context,
+	userID uuid.UUID,
+	groupCode model.LineGameLevelGroupCode,
+	levelNum int,
+	progression model.LineGameProgression,
+) (model.LineGameLevelGroupCode, int, error) {
+	if groupCode == model.LineGameEndlessGroupCode {
+		return groupCode, levelNum + 1, nil
+	}
+	var (
+		nextGroupCode model.LineGameLevelGroupCode
+		nextLevelNum  int
+		err           error
+	)
+	if progression == model.LineGameProgressionSkipGroup {
+		nextGroupCode, nextLevelNum, err = l.LineGameLevelStorage.GetNextGroupLevel(ctx, groupCode)
+	} else {
+		nextGroupCode, nextLevelNum, err = l.LineGameLevelStorage.GetNextLevel(ctx, groupCode, levelNum)
+	}
+	if progression == model.LineGameProgressionRepeatGroup &&
+		(err == nil && nextGroupCode != groupCode || errors.Is(err, model.ErrLineGameGroupsIsFinished)) {
+		return groupCode, 0, nil
+	}
+	if !errors.Is(err, model.ErrLineGameGroupsIsFinished) {
+		return nextGroupCode, nextLevelNum, err
+	}
+	if !l.LineGameConifg().Endless.Enabled {
+		return groupCode, levelNum, nil
+	}
+	slog.Info(
+		"User finished level groups, move to endless mode",
+		slog.String("user_id", userID.String()),
+	)
+	return model.LineGameEndlessGroupCode, 0, nil
+}
+
+// generateEndlessLevel returns the same level for the same user and level number
+// while the endless config is not changed.
+func (l *LineGameUsecase) generateEndlessLevel(userID uuid.UUID, levelNum int) (model.LineGameLevel, error) {
+	endlessCfg := l.LineGameConifg().Endless
+	if len(endlessCfg.Steps) == 0 {
+		return model.LineGameLevel{}, errors.New("endless mode has no steps")
+	}
+	stepNum := len(endlessCfg.Steps) - 1
+	if endlessCfg.LevelsPerStep > 0 {
+		stepNum = min(levelNum/endlessCfg.LevelsPerStep, stepNum)
+	}
+	step := endlessCfg.Steps[stepNum]
+	// the level depends only on the user, the level number and the step, so the cached one is the same
+	l.endlessMu.Lock()
+	cached, ok := l.endlessLevels[userID]
+	l.endlessMu.Unlock()
+	if ok && cached.levelNum == levelNum && cached.step == step {
+		return cached.level, nil
+	}
+	level, err := line_solver.Generate(
+		endlessLevelSeed(userID, levelNum), step.FieldSize, step.Orders, step.Blockers,
+	)
+	if err != nil {
+		return model.LineGameLevel{}, fmt.Errorf("failed to generate endless level %v: %w", levelNum, err)
+	}
+	level.Endless = true
+
+	l.endlessMu.Lock()
+	defer l.endlessMu.Unlock()
+	if len(l.endlessLevels) >= maxEndlessLevelsCached {
+		clear(l.endlessLevels)
+	}
+	l.endlessLevels[userID] = endlessLevel{levelNum: levelNum, step: step, level: level}
+	return level, nil
+}
+
+func endlessLevelSeed(userID uuid.UUID, levelNum int) uint64 {
+	hash := fnv.New64a()
+	hash.Write(userID[:])
+	hash.Write(binary.BigEndian.AppendUint64(nil, uint64(levelNum)))
+	return hash.Sum64()
+}
+
+// GetLevelHint returns the whole answer, it is paid as the full hint once for the attempt of the level.
 func (l *LineGameUsecase) GetLevelHint(ctx context.Context, userID uuid.UUID) ([][]int, error) {
-	level, err := l.GetUserLevel(ctx, userID)
+	level, attempt, err := l.getUserLevel(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user level: %w", err)
 	}
-	if err = l.BalanceUsecase.TrySpendSoftCurrency(ctx, userID, l.PriceConifg().LineGameHintPrice); err != nil {
-		return nil, fmt.Errorf("failed to spend soft currency: %w", err)
+	if _, err = l.buyLevelHint(ctx, userID, level, attempt, model.LineGameHintFull, nil); err != nil {
+		return nil, err
 	}
 	return level.Answer, nil
 }
 
-func (l *LineGameUsecase) GetTimeStopBooster(ctx context.Context, userID uuid.UUID) error {
-	if err := l.BalanceUsecase.TrySpendSoftCurrency(ctx, userID, l.PriceConifg().LineGameHintPrice); err != nil {
-		return fmt.Errorf("failed to spend soft currency: %w", err)
+// BuyLevelHint reveals a part of the way continuing the user path. When the path can not be continued,
+// the hint continues its longest part which can be. Every hint is paid once for the attempt of the level,
+// the hint revealing the same cells is returned again for free.
+func (l *LineGameUsecase) BuyLevelHint(
+	ctx context.Context,
+	userID uuid.UUID,
+	hintType model.LineGameHintType,
+	path []model.LineGameLevelCell,
+) (model.LineGameHint, error) {
+	level, attempt, err := l.getUserLevel(ctx, userID)
+	if err != nil {
+		return model.LineGameHint{}, fmt.Errorf("failed to get user level: %w", err)
+	}
+	return l.buyLevelHint(ctx, userID, level, attempt, hintType, path)
+}
+
+func (l *LineGameUsecase) buyLevelHint(
+	ctx context.Context,
+	userID uuid.UUID,
+	level model.LineGameLevel,
+	attempt model.LineGameLevelAttempt,
+	hintType model.LineGameHintType,
+	path []model.LineGameLevelCell,
+) (model.LineGameHint, error) {
+	hint, err := l.buildLevelHint(level, hintType, path)
+	if err != nil {
+		return model.LineGameHint{}, err
+	}
+	// the hint revealing the same cells is bought already, the next cells of a longer path are a new hint
+	for _, bought := range level.Hints {
+		if bought.Reveals(hint) {
+			return bought, nil
+		}
+	}
+	hint.Price = l.hintPrice(hintType)
+	// the balance is created on the first request, so it is requested before the purchase
+	if _, err = l.BalanceUsecase.GetUserBalance(ctx, userID); err != nil {
+		return model.LineGameHint{}, fmt.Errorf("failed to get balance: %w", err)
+	}
+	if _, err = l.LineGameHintStorage.BuyLevelHint(ctx, userID, attempt, hint); err != nil {
+		return model.LineGameHint{}, fmt.Errorf("failed to buy level hint: %w", err)
+	}
+	return hint, nil
+}
+
+func (l *LineGameUsecase) buildLevelHint(
+	level model.LineGameLevel,
+	hintType model.LineGameHintType,
+	path []model.LineGameLevelCell,
+) (model.LineGameHint, error) {
+	answerWay, err := level.AnswerWay()
+	if err != nil {
+		return model.LineGameHint{}, fmt.Errorf("failed to get answer way: %w", err)
+	}
+	if hintType == model.LineGameHintFull {
+		return model.LineGameHint{Type: hintType, Cells: answerWay}, nil
+	}
+	if len(path) == 0 {
+		path = []model.LineGameLevelCell{level.Start}
+	}
+	if err = level.CheckPath(path); err != nil {
+		return model.LineGameHint{}, http_errors.NewSame("hint path is invalid: "+err.Error(), http.StatusBadRequest)
+	}
+	way, keptCells := continueLinePath(level, answerWay, path)
+	hint := model.LineGameHint{Type: hintType, KeptCells: keptCells}
+	switch hintType {
+	case model.LineGameHintSteps:
+		hintSteps := l.LineGameConifg().HintSteps
+		// configs saved before the steps hint have no count of its cells
+		if hintSteps <= 0 {
+			hintSteps = defaultLineGameHintSteps
+		}
+		hint.Cells = way[keptCells:min(keptCells+hintSteps, len(way))]
+	case model.LineGameHintCheckpoint:
+		checkpoint := level.End
+		for _, order := range level.Order {
+			if !slices.Contains(way[:keptCells], order) {
+				checkpoint = order
+				break
+			}
+		}
+		hint.Cells = way[keptCells : slices.Index(way, checkpoint)+1]
+	default:
+		return model.LineGameHint{}, fmt.Errorf("unknown hint type %q", hintType)
+	}
+	return hint, nil
+}
+
+func (l *LineGameUsecase) hintPrice(hintType model.LineGameHintType) int {
+	switch hintType {
+	case model.LineGameHintSteps:
+		return l.PriceConifg().LineGameStepsHintPrice
+	case model.LineGameHintCheckpoint:
+		return l.PriceConifg().LineGameCheckpointHintPrice
+	default:
+		return l.PriceConifg().LineGameHintPrice
+	}
+}
+
+// defaultLineGameHintSteps is a count of cells revealed by the steps hint when the config has no count
+const defaultLineGameHintSteps = 3
+
+// maxHintSolveSteps limits the search of all parts of the path for one hint
+const maxHintSolveSteps = 2_000_000
+
+// continueLinePath returns the whole way and the count of path cells kept in it.
+// The longest solvable part of the path is found by binary search, because every part of a solvable path
+// is solvable too and the common part with the stored answer is always solvable. Parts which are not
+// solved within the search budget are counted as unsolvable.
+func continueLinePath(
+	level model.LineGameLevel,
+	answerWay, path []model.LineGameLevelCell,
+) ([]model.LineGameLevelCell, int) {
+	budget := maxHintSolveSteps
+	way, steps, err := line_solver.SolveFromWithin(level, path, budget)
+	if err == nil {
+		return way, len(path)
+	}
+	budget -= steps
+	solvable := 0
+	for solvable < len(path) && path[solvable] == answerWay[solvable] {
+		solvable++
+	}
+	way, unsolvable := answerWay, len(path)
+	for solvable+1 < unsolvable && budget > 0 {
+		mid := (solvable + unsolvable) / 2
+		midWay, steps, err := line_solver.SolveFromWithin(level, path[:mid], budget)
+		budget -= steps
+		if err != nil {
+			unsolvable = mid
+			continue
+		}
+		way, solvable = midWay, mid
 	}
-	return nil
+	return way, solvable
 }
@@ -0,0 +1,647 @@
+package usecase
+
+import (
+	"context"
+	"github.com/4units/mos-hack-game/back/config"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+type progressStorageStub struct {
+	groupCode   model.LineGameLevelGroupCode
+	levelNum    int
+	passedCount int
+}
+
+func (p *progressStorageStub) GetUserLineGameLevel(_ context.Context, _ uuid.UUID) (
+	model.LineGameLevelGroupCode, int, int, error,
+) {
+	return p.groupCode, p.levelNum, p.passedCount, nil
+}
+
+func (p *progressStorageStub) UpdateUserLineGameLevel(
+	_ context.Context, _ uuid.UUID, groupCode model.LineGameLevelGroupCode, passedCount int, levelNum int,
+) error {
+	p.groupCode, p.levelNum, p.passedCount = groupCode, levelNum, passedCount
+	return nil
+}
+
+func (p *progressStorageStub) AddUserLineGameLevel(
+	_ context.Context, _ uuid.UUID, groupCode model.LineGameLevelGroupCode, levelNum int,
+) error {
+	p.groupCode, p.levelNum = groupCode, levelNum
+	return nil
+}
+
+// levelAttemptStorageStub keeps starts of attempts of one user by their passed counts
+type levelAttemptStorageStub struct {
+	starts map[int]time.Time
+}
+
+func (a *levelAttemptStorageStub) StartLevelAttempt(
+	_ context.Context, _ uuid.UUID, passedCount int, startedAt time.Time,
+) error {
+	if a.starts == nil {
+		a.starts = make(map[int]time.Time)
+	}
+	if _, ok := a.starts[passedCount]; !ok {
+		a.starts[passedCount] = startedAt
+	}
+	return nil
+}
+
+func (a *levelAttemptStorageStub) GetLevelAttemptStart(
+	_ context.Context, _ uuid.UUID, passedCount int,
+) (time.Time, error) {
+	startedAt, ok := a.starts[passedCount]
+	if !ok {
+		return time.Time{}, model.ErrLineGameLevelNotStarted
+	}
+	return startedAt, nil
+}
+
+type hintStorageStub struct {
+	hints map[model.LineGameLevelAttempt][]model.LineGameHint
+	// balance pays for bought hints, hints are free without it
+	balance *balanceStorageStub
+}
+
+func (h *hintStorageStub) GetLevelHints(
+	_ context.Context, _ uuid.UUID, attempt model.LineGameLevelAttempt,
+) ([]model.LineGameHint, error) {
+	return h.hints[attempt], nil
+}
+
+func (h *hintStorageStub) AddLevelHint(
+	_ context.Context, _ uuid.UUID, attempt model.LineGameLevelAttempt, hint model.LineGameHint,
+) error {
+	if h.hints == nil {
+		h.hints = make(map[model.LineGameLevelAttempt][]model.LineGameHint)
+	}
+	h.hints[attempt] = append(h.hints[attempt], hint)
+	return nil
+}
+
+func (h *hintStorageStub) BuyLevelHint(
+	ctx context.Context, userID uuid.UUID, attempt model.LineGameLevelAttempt, hint model.LineGameHint,
+) (bool, error) {
+	for _, saved := range h.hints[attempt] {
+		if saved.Reveals(hint) {
+			return false, nil
+		}
+	}
+	if h.balance != nil {
+		if h.balance.softCurrency < hint.Price {
+			return false, model.ErrNotEnoughSoftCurrency
+		}
+		h.balance.softCurrency -= hint.Price
+	}
+	return true, h.AddLevelHint(ctx, userID, attempt, hint)
+}
+
+type levelStorageStub struct {
+	levels map[model.LineGameLevelGroupCode][]model.LineGameLevel
+	// order of groups, the groups are finished after the current group when it is empty
+	order []model.LineGameLevelGroupCode
+}
+
+func (l *levelStorageStub) GetStartGroupCode(_ context.Context) (model.LineGameLevelGroupCode, error) {
+	return "3_0_0", nil
+}
+
+func (l *levelStorageStub) GetLevel(
+	_ context.Context, groupCode model.LineGameLevelGroupCode, levelNum int,
+) (model.LineGameLevel, error) {
+	levels := l.levels[groupCode]
+	if levelNum >= len(levels) {
+		return model.LineGameLevel{}, model.ErrLineGameNotExistsLevelInStorage
+	}
+	return levels[levelNum], nil
+}
+
+func (l *levelStorageStub) GetNextLevel(
+	_ context.Context, groupCode model.LineGameLevelGroupCode, levelNum int,
+) (model.LineGameLevelGroupCode, int, error) {
+	if levelNum+1 >= len(l.levels[groupCode]) {
+		return l.GetNextGroupLevel(context.Background(), groupCode)
+	}
+	return groupCode, levelNum + 1, nil
+}
+
+func (l *levelStorageStub) GetNextGroupLevel(
+	_ context.Context, groupCode model.LineGameLevelGroupCode,
+) (model.LineGameLevelGroupCode, int, error) {
+	i := slices.Index(l.order, groupCode)
+	if i < 0 || i+1 >= len(l.order) {
+		return "", 0, model.ErrLineGameGroupsIsFinished
+	}
+	return l.order[i+1], 0, nil
+}
+
+type levelResultStorageStub struct {
+	results []model.LineGameLevelResult
+}
+
+func (l *levelResultStorageStub) AddLevelResult(
+	_ context.Context, _ uuid.UUID, _ int, result model.LineGameLevelResult,
+) error {
+	l.results = append(l.results, result)
+	return nil
+}
+
+func (l *levelResultStorageStub) GetRecentLevelResults(
+	_ context.Context, _ uuid.UUID, groupCode model.LineGameLevelGroupCode, limit int,
+) ([]model.LineGameLevelResult, error) {
+	recent := make([]model.LineGameLevelResult, 0, limit)
+	for i := len(l.results) - 1; i >= 0 && len(recent) < limit; i-- {
+		if l.results[i].GroupCode == groupCode {
+			recent = append(recent, l.results[i])
+		}
+	}
+	return recent, nil
+}
+
+func (l *levelStorageStub) GetClosestLowOrDefaultLevel(
+	_ context.Context, groupCode model.LineGameLevelGroupCode, num int,
+) (model.LineGameLevelGroupCode, int, error) {
+	if levels, ok := l.levels[groupCode]; ok {
+		return groupCode, min(num, len(levels)-1), nil
+	}
+	return "3_0_0", 0, nil
+}
+
+func TestLineGameUsecase_GetUserLevel_RepairProgress(t *testing.T) {
+	tests := []struct {
+		name         string
+		groupCode    model.LineGameLevelGroupCode
+		levelNum     int
+		expectedCode model.LineGameLevelGroupCode
+		expectedNum  int
+	}{
+		{"existing level", "4_0_0", 1, "4_0_0", 1},
+		{"group became shorter", "4_0_0", 7, "4_0_0", 1},
+		{"group was removed", "5_0_0", 3, "3_0_0", 0},
+	}
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				progress := &progressStorageStub{groupCode: test.groupCode, levelNum: test.levelNum, passedCount: 10}
+				usecase := NewLineGameUsecase(
+					LineGameUsecaseDeps{
+						LineGameLevelStorage: &levelStorageStub{
+							levels: map[model.LineGameLevelGroupCode][]model.LineGameLevel{
+								"3_0_0": {{FieldSize: 3}},
+								"4_0_0": {{FieldSize: 4}, {FieldSize: 4}},
+							},
+						},
+						LineGameProgressStorage:    progress,
+						LineGameHintStorage:        &hintStorageStub{},
+						LineGameLevelResultStorage: &levelResultStorageStub{},
+						LineGameAttemptStorage:     &levelAttemptStorageStub{},
+						LineGameStarStorage:        &starStorageStub{},
+						EnergyUsecase:              newEnergyUsecaseStub(config.Energy{}),
+					},
+				)
+
+				level, err := usecase.GetUserLevel(context.Background(), uuid.New())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if progress.groupCode != test.expectedCode || progress.levelNum != test.expectedNum {
+					t.Errorf(
+						"Wrong progress. Expected %v %v, got %v %v\n",
+						test.expectedCode, test.expectedNum, progress.groupCode, progress.levelNum,
+					)
+				}
+				if level.PassedCount != 10 || progress.passedCount != 10 {
+					t.Errorf(
+						"Wrong passed count. Expected 10, got %v in level and %v in progress\n",
+						level.PassedCount, progress.passedCount,
+					)
+				}
+			},
+		)
+	}
+}
+
+type balanceStorageStub struct {
+	softCurrency int
+}
+
+func (b *balanceStorageStub) GetUserBalance(_ context.Context, _ uuid.UUID) (model.UserBalance, error) {
+	return model.UserBalance{SoftCurrency: b.softCurrency}, nil
+}
+
+func (b *balanceStorageStub) CreateUserBalance(_ context.Context, _ uuid.UUID, balance model.UserBalance) error {
+	b.softCurrency = balance.SoftCurrency
+	return nil
+}
+
+func (b *balanceStorageStub) GetSoftCurrency(_ context.Context, _ uuid.UUID) (int, error) {
+	return b.softCurrency, nil
+}
+
+func (b *balanceStorageStub) UpdateSoftCurrency(_ context.Context, _ uuid.UUID, count int) error {
+	b.softCurrency = count
+	return nil
+}
+
+type lineGameConfigStub struct {
+	cfg config.LineGame
+}
+
+func (c *lineGameConfigStub) LineGameConifg() *config.LineGame {
+	return &c.cfg
+}
+
+func TestLineGameUsecase_TryCompleteUserLevel_Endless(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	progress := &progressStorageStub{groupCode: "3_0_0", levelNum: 0}
+	usecase := NewLineGameUsecase(
+		LineGameUsecaseDeps{
+			LineGameLevelStorage: &levelStorageStub{
+				levels: map[model.LineGameLevelGroupCode][]model.LineGameLevel{
+					"3_0_0": {{FieldSize: 3}},
+				},
+			},
+			LineGameProgressStorage:    progress,
+			LineGameHintStorage:        &hintStorageStub{},
+			LineGameLevelResultStorage: &levelResultStorageStub{},
+			LineGameAttemptStorage:     &levelAttemptStorageStub{},
+			LineGameStarStorage:        &starStorageStub{},
+			EnergyUsecase:              newEnergyUsecaseStub(config.Energy{}),
+			BalanceUsecase:             NewBalanceUsecase(BalanceUsecaseDeps{BalanceStorage: &balanceStorageStub{}}),
+			LeaderboardUsecase:         newLeaderboardUsecaseStub(),
+			LineGameConifgProvider: &lineGameConfigStub{
+				cfg: config.LineGame{
+					RewardsConditions: []config.LineGameRewardCondition{
+						{MaxTime: 10, Reward: config.LineGameReward{SoftCurrency: 10}},
+					},
+					Endless: config.LineGameEndless{
+						Enabled:       true,
+						LevelsPerStep: 1,
+						Steps: []config.LineGameEndlessStep{
+							{FieldSize: 4, Orders: 1, Blockers: 1},
+							{FieldSize: 5, Orders: 2, Blockers: 2},
+						},
+					},
+				},
+			},
+		},
+	)
+
+	if _, err := usecase.GetUserLevel(ctx, userID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := usecase.TryCompleteUserLevel(ctx, userID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if progress.groupCode != model.LineGameEndlessGroupCode || progress.levelNum != 0 {
+		t.Errorf(
+			"Wrong progress. Expected %v 0, got %v %v\n",
+			model.LineGameEndlessGroupCode, progress.groupCode, progress.levelNum,
+		)
+	}
+
+	for levelNum, fieldSize := range []int{4, 5, 5} {
+		level, err := usecase.GetUserLevel(ctx, userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !level.Endless || level.FieldSize != fieldSize {
+			t.Errorf(
+				"Wrong endless level %v. Expected field size %v, got %v (endless %v)\n",
+				levelNum, fieldSize, level.FieldSize, level.Endless,
+			)
+		}
+		wrongAnswer := make([][]int, level.FieldSize)
+		for y := range wrongAnswer {
+			wrongAnswer[y] = make([]int, level.FieldSize)
+		}
+		if _, err = usecase.TryCompleteUserLevel(ctx, userID, wrongAnswer); err == nil {
+			t.Errorf("Wrong result. Expected error for incorrect answer of endless level %v\n", levelNum)
+		}
+		if _, err = usecase.TryCompleteUserLevel(ctx, userID, level.Answer); err != nil {
+			t.Fatal(err)
+		}
+		if progress.levelNum != levelNum+1 {
+			t.Errorf("Wrong endless level num. Expected %v, got %v\n", levelNum+1, progress.levelNum)
+		}
+	}
+}
+
+func TestLineGameUsecase_GenerateEndlessLevel_Cached(t *testing.T) {
+	userID := uuid.New()
+	configStub := &lineGameConfigStub{
+		cfg: config.LineGame{
+			Endless: config.LineGameEndless{
+				Enabled: true,
+				Steps:   []config.LineGameEndlessStep{{FieldSize: 4, Orders: 1, Blockers: 1}},
+			},
+		},
+	}
+	usecase := NewLineGameUsecase(LineGameUsecaseDeps{LineGameConifgProvider: configStub})
+
+	level, err := usecase.generateEndlessLevel(userID, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, ok := usecase.endlessLevels[userID]
+	if !ok || cached.levelNum != 3 {
+		t.Fatalf("Wrong cached endless level. Expected level 3, got %v\n", cached.levelNum)
+	}
+	same, err := usecase.generateEndlessLevel(userID, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(level, same) {
+		t.Errorf("Wrong endless level. Expected the cached level, got %v\n", same)
+	}
+
+	// a changed step of the config generates the level again
+	configStub.cfg.Endless.Steps[0] = config.LineGameEndlessStep{FieldSize: 5, Orders: 2, Blockers: 2}
+	if level, err = usecase.generateEndlessLevel(userID, 3); err != nil {
+		t.Fatal(err)
+	}
+	if level.FieldSize != 5 || usecase.endlessLevels[userID].step.FieldSize != 5 {
+		t.Errorf("Wrong endless level. Expected field size 5 of the new step, got %v\n", level.FieldSize)
+	}
+}
+
+func TestLineGameUsecase_BuildLevelHint(t *testing.T) {
+	// stored answer goes by columns: (0,0) (0,1) (0,2) (1,2) (1,1) (1,0) (2,0) (2,1) (2,2)
+	level := model.LineGameLevel{
+		FieldSize: 3,
+		Start:     model.LineGameLevelCell{X: 0, Y: 0},
+		End:       model.LineGameLevelCell{X: 2, Y: 2},
+		Order:     []model.LineGameLevelCell{{X: 2, Y: 0}},
+		Answer: [][]int{
+			{2, 1, 2},
+			{2, 0, 2},
+			{1, 0, 4},
+		},
+	}
+	usecase := NewLineGameUsecase(
+		LineGameUsecaseDeps{
+			LineGameConifgProvider: &lineGameConfigStub{cfg: config.LineGame{HintSteps: 3}},
+		},
+	)
+	tests := []struct {
+		name          string
+		hintType      model.LineGameHintType
+		path          []model.LineGameLevelCell
+		expectedKept  int
+		expectedCells []model.LineGameLevelCell
+	}{
+		{
+			"steps after dead end",
+			model.LineGameHintSteps,
+			[]model.LineGameLevelCell{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}},
+			2,
+			[]model.LineGameLevelCell{{X: 2, Y: 0}, {X: 2, Y: 1}, {X: 1, Y: 1}},
+		},
+		{
+			"checkpoint is order cell",
+			model.LineGameHintCheckpoint,
+			[]model.LineGameLevelCell{{X: 0, Y: 0}, {X: 1, Y: 0}},
+			2,
+			[]model.LineGameLevelCell{{X: 2, Y: 0}},
+		},
+		{
+			"checkpoint is end cell",
+			model.LineGameHintCheckpoint,
+			[]model.LineGameLevelCell{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}},
+			3,
+			[]model.LineGameLevelCell{
+				{X: 2, Y: 1}, {X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2},
+			},
+		},
+		{
+			"full is stored answer",
+			model.LineGameHintFull,
+			[]model.LineGameLevelCell{{X: 0, Y: 0}, {X: 1, Y: 0}},
+			0,
+			[]model.LineGameLevelCell{
+				{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 1, Y: 1},
+				{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1}, {X: 2, Y: 2},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				hint, err := usecase.buildLevelHint(level, test.hintType, test.path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if hint.KeptCells != test.expectedKept || !slices.Equal(hint.Cells, test.expectedCells) {
+					t.Errorf(
+						"Wrong hint. Expected %v %v, got %v %v\n",
+						test.expectedKept, test.expectedCells, hint.KeptCells, hint.Cells,
+					)
+				}
+			},
+		)
+	}
+
+	_, err := usecase.buildLevelHint(
+		level, model.LineGameHintSteps, []model.LineGameLevelCell{{X: 0, Y: 0}, {X: 1, Y: 1}},
+	)
+	if err == nil {
+		t.Errorf("Wrong result. Expected error for path with a gap\n")
+	}
+
+	usecase = NewLineGameUsecase(
+		LineGameUsecaseDeps{
+			LineGameConifgProvider: &lineGameConfigStub{cfg: config.LineGame{}},
+		},
+	)
+	hint, err := usecase.buildLevelHint(level, model.LineGameHintSteps, []model.LineGameLevelCell{{X: 0, Y: 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hint.Cells) != defaultLineGameHintSteps {
+		t.Errorf("Wrong hint cells count. Expected %v, got %v\n", defaultLineGameHintSteps, len(hint.Cells))
+	}
+}
+
+type priceConfigStub struct {
+	cfg config.ItemsPrice
+}
+
+func (p *priceConfigStub) PriceConifg() *config.ItemsPrice {
+	return &p.cfg
+}
+
+func TestLineGameUsecase_BuyLevelHint_Once(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	level := model.LineGameLevel{
+		FieldSize: 2,
+		Start:     model.LineGameLevelCell{X: 0, Y: 0},
+		End:       model.LineGameLevelCell{X: 0, Y: 1},
+		Answer:    [][]int{{1, 2}, {4, 3}},
+	}
+	progress := &progressStorageStub{groupCode: "2_0_0", levelNum: 0, passedCount: 3}
+	balance := &balanceStorageStub{softCurrency: 100}
+	usecase := NewLineGameUsecase(
+		LineGameUsecaseDeps{
+			LineGameLevelStorage: &levelStorageStub{
+				levels: map[model.LineGameLevelGroupCode][]model.LineGameLevel{"2_0_0": {level, level}},
+			},
+			LineGameProgressStorage:    progress,
+			LineGameHintStorage:        &hintStorageStub{balance: balance},
+			LineGameLevelResultStorage: &levelResultStorageStub{},
+			LineGameAttemptStorage:     &levelAttemptStorageStub{},
+			LineGameStarStorage:        &starStorageStub{},
+			EnergyUsecase:              newEnergyUsecaseStub(config.Energy{}),
+			BalanceUsecase:             NewBalanceUsecase(BalanceUsecaseDeps{BalanceStorage: balance}),
+			LeaderboardUsecase:         newLeaderboardUsecaseStub(),
+			LineGameConifgProvider: &lineGameConfigStub{
+				cfg: config.LineGame{
+					HintSteps: 1,
+					RewardsConditions: []config.LineGameRewardCondition{
+						{MaxTime: 10, Reward: config.LineGameReward{SoftCurrency: 10}},
+					},
+				},
+			},
+			PriceConifgProvider: &priceConfigStub{cfg: config.ItemsPrice{LineGameStepsHintPrice: 30}},
+		},
+	)
+
+	var hint model.LineGameHint
+	for range 2 {
+		var err error
+		hint, err = usecase.BuyLevelHint(ctx, userID, model.LineGameHintSteps, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hint.Price != 30 || len(hint.Cells) != 1 {
+			t.Errorf("Wrong hint. Expected 1 cell for 30, got %v for %v\n", hint.Cells, hint.Price)
+		}
+	}
+	if balance.softCurrency != 70 {
+		t.Errorf("Wrong balance. Expected 70, got %v\n", balance.softCurrency)
+	}
+	nextHint, err := usecase.BuyLevelHint(
+		ctx, userID, model.LineGameHintSteps, []model.LineGameLevelCell{level.Start, hint.Cells[0]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nextHint.KeptCells != 2 || len(nextHint.Cells) != 1 || balance.softCurrency != 40 {
+		t.Errorf("Wrong next hint. Expected the next step for 30, got %v and balance %v\n", nextHint, balance.softCurrency)
+	}
+	userLevel, err := usecase.GetUserLevel(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(userLevel.Hints) != 2 || userLevel.Hints[1].Type != model.LineGameHintSteps {
+		t.Errorf("Wrong level hints. Expected both bought steps hints, got %v\n", userLevel.Hints)
+	}
+
+	if _, err = usecase.TryCompleteUserLevel(ctx, userID, level.Answer); err != nil {
+		t.Fatal(err)
+	}
+	if userLevel, err = usecase.GetUserLevel(ctx, userID); err != nil {
+		t.Fatal(err)
+	}
+	if len(userLevel.Hints) != 0 {
+		t.Errorf("Wrong level hints. Expected no hints for the next attempt, got %v\n", userLevel.Hints)
+	}
+}
+
+func TestLineGameUsecase_TryCompleteUserLevel_GroupRewards(t *testing.T) {
+	ctx := context.Background()
+	five, two := 5, 2
+	level := model.LineGameLevel{
+		FieldSize: 2,
+		Start:     model.LineGameLevelCell{X: 0, Y: 0},
+		End:       model.LineGameLevelCell{X: 0, Y: 1},
+		Answer:    [][]int{{1, 2}, {4, 3}},
+	}
+	global := []config.LineGameRewardCondition{{MaxTime: 10, Reward: config.LineGameReward{SoftCurrency: 10}}}
+	tests := []struct {
+		name           string
+		groupRewards   []config.LineGameGroupRewards
+		expectedReward int
+	}{
+		{"global fallback", nil, 10},
+		{
+			"range",
+			[]config.LineGameGroupRewards{
+				{
+					MinFieldSize:      &five,
+					RewardsConditions: []config.LineGameRewardCondition{{MaxTime: 10}},
+				},
+				{
+					MaxFieldSize: &two,
+					RewardsConditions: []config.LineGameRewardCondition{
+						{MaxTime: 10, Reward: config.LineGameReward{SoftCurrency: 20}},
+					},
+				},
+			},
+			20,
+		},
+		{
+			"group code before range",
+			[]config.LineGameGroupRewards{
+				{
+					MaxFieldSize: &two,
+					RewardsConditions: []config.LineGameRewardCondition{
+						{MaxTime: 10, Reward: config.LineGameReward{SoftCurrency: 20}},
+					},
+				},
+				{
+					GroupCode: "2_0_0",
+					RewardsConditions: []config.LineGameRewardCondition{
+						{MaxTime: 10, Reward: config.LineGameReward{SoftCurrency: 30}},
+					},
+				},
+			},
+			30,
+		},
+	}
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				usecase := NewLineGameUsecase(
+					LineGameUsecaseDeps{
+						LineGameLevelStorage: &levelStorageStub{
+							levels: map[model.LineGameLevelGroupCode][]model.LineGameLevel{"2_0_0": {level, level}},
+						},
+						LineGameProgressStorage:    &progressStorageStub{groupCode: "2_0_0"},
+						LineGameHintStorage:        &hintStorageStub{},
+						LineGameLevelResultStorage: &levelResultStorageStub{},
+						LineGameAttemptStorage:     &levelAttemptStorageStub{},
+						LineGameStarStorage:        &starStorageStub{},
+						EnergyUsecase:              newEnergyUsecaseStub(config.Energy{}),
+						BalanceUsecase: NewBalanceUsecase(
+							BalanceUsecaseDeps{BalanceStorage: &balanceStorageStub{}},
+						),
+						LeaderboardUsecase: newLeaderboardUsecaseStub(),
+						LineGameConifgProvider: &lineGameConfigStub{
+							cfg: config.LineGame{RewardsConditions: global, GroupRewards: test.groupRewards},
+						},
+					},
+				)
+				userID := uuid.New()
+				if _, err := usecase.GetUserLevel(ctx, userID); err != nil {
+					t.Fatal(err)
+				}
+				reward, err := usecase.TryCompleteUserLevel(ctx, userID, level.Answer)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if reward.SoftCurrency != test.expectedReward {
+					t.Errorf("Wrong reward. Expected %v, got %v\n", test.expectedReward, reward.SoftCurrency)
+				}
+			},
+		)
+	}
+}
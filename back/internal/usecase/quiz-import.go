@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/config"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"slices"
+)
+
+// maxQuizExport is a max count of quizzes in one export
+const maxQuizExport = 10000
+
+// ImportQuizzes saves valid quizzes of the batch as drafts of the user in one transaction and reports every row.
+// Quizzes which questions are already saved or are earlier in the batch are reported as duplicates
+// and are not saved. Nothing is saved in the dry run, the report shows what would be done.
+func (q *QuizUsecase) ImportQuizzes(
+	ctx context.Context,
+	userID uuid.UUID,
+	rows []model.QuizImportRow,
+	dryRun bool,
+) (model.QuizImportReport, error) {
+	if err := q.UserUsecase.CheckUserAnyRole(
+		ctx, userID, []model.Role{
+			model.RoleQuizWriter,
+			model.RoleAdmin,
+		},
+	); err != nil {
+		return model.QuizImportReport{}, err
+	}
+
+	// rows are copied to report invalid answer keys as row errors
+	rows = slices.Clone(rows)
+	questions := make([]string, 0, len(rows))
+	for i, row := range rows {
+		if row.Error == "" {
+			if err := row.Quiz.ValidateAnswerKey(); err != nil {
+				rows[i].Error = err.Error()
+				continue
+			}
+			questions = append(questions, model.NormalizeQuizQuestion(row.Quiz.Question))
+		}
+	}
+	savedQuizIDs, err := q.QuizStorage.GetQuizIDsByQuestions(ctx, questions)
+	if err != nil {
+		return model.QuizImportReport{}, fmt.Errorf("failed to get quizzes by questions: %w", err)
+	}
+
+	report := model.QuizImportReport{
+		DryRun:  dryRun,
+		Results: make([]model.QuizImportResult, 0, len(rows)),
+	}
+	batchRows := make(map[string]int, len(rows))
+	quizzes := make([]model.Quiz, 0, len(rows))
+	// created are indexes of results of quizzes to save
+	created := make([]int, 0, len(rows))
+	for _, row := range rows {
+		result := model.QuizImportResult{Row: row.Row}
+		question := model.NormalizeQuizQuestion(row.Quiz.Question)
+		switch {
+		case row.Error != "":
+			result.Status = model.QuizImportInvalid
+			result.Error = row.Error
+			report.Invalid++
+		case savedQuizIDs[question] != uuid.Nil:
+			result.Status = model.QuizImportDuplicate
+			result.DuplicateOfQuizID = savedQuizIDs[question]
+			report.Duplicates++
+		case batchRows[question] != 0:
+			result.Status = model.QuizImportDuplicate
+			result.DuplicateOfRow = batchRows[question]
+			report.Duplicates++
+		default:
+			batchRows[question] = row.Row
+			quiz := row.Quiz
+			if quiz.Difficulty == "" {
+				quiz.Difficulty = config.QuizDifficultyMedium
+			}
+			quiz.Type = quiz.TypeOrDefault()
+			quiz.Status = model.QuizStatusDraft
+			quiz.AuthorID = userID
+			quizzes = append(quizzes, quiz)
+			created = append(created, len(report.Results))
+			result.Status = model.QuizImportValid
+			report.Created++
+		}
+		report.Results = append(report.Results, result)
+	}
+	if dryRun || len(quizzes) == 0 {
+		return report, nil
+	}
+
+	quizIDs, err := q.QuizStorage.AddQuizzes(ctx, quizzes)
+	if err != nil {
+		return model.QuizImportReport{}, fmt.Errorf("failed to add quizzes: %w", err)
+	}
+	for i, resultIndex := range created {
+		report.Results[resultIndex].Status = model.QuizImportCreated
+		report.Results[resultIndex].QuizID = quizIDs[i]
+	}
+	return report, nil
+}
+
+// ExportQuizzes returns quizzes of any status found by the search to quiz writers and admins,
+// the search limit is replaced by the max count of exported quizzes.
+func (q *QuizUsecase) ExportQuizzes(
+	ctx context.Context,
+	userID uuid.UUID,
+	search model.QuizSearch,
+) ([]model.Quiz, error) {
+	search.Limit = maxQuizExport
+	search.Offset = 0
+	page, err := q.SearchQuizzes(ctx, userID, search)
+	if err != nil {
+		return nil, err
+	}
+	return page.Quizzes, nil
+}
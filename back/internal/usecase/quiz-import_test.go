@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"testing"
+)
+
+func TestQuizUsecase_ImportQuizzes(t *testing.T) {
+	ctx := context.Background()
+	writerID := uuid.New()
+	savedQuizID := uuid.New()
+	usecase := newQuizUsecaseStub(
+		&balanceStorageStub{},
+		model.Quiz{ID: savedQuizID, Question: "What is a deposit?", Answers: []string{"a"}, CorrectAnswer: 1},
+	)
+	usecase.UserUsecase = New(
+		UserUsecaseDeps{
+			UserStorage: &userStorageStub{
+				roles: map[uuid.UUID][]model.Role{writerID: {model.RoleQuizWriter}},
+			},
+		},
+	)
+	rows := []model.QuizImportRow{
+		{Row: 1, Quiz: model.Quiz{Question: "What is a loan?", Answers: []string{"a", "b"}, CorrectAnswer: 2}},
+		{Row: 2, Quiz: model.Quiz{Question: "  what is a DEPOSIT ", Answers: []string{"a"}, CorrectAnswer: 1}},
+		{Row: 3, Quiz: model.Quiz{Question: "What is a loan", Answers: []string{"a"}, CorrectAnswer: 1}},
+		{Row: 4, Error: "question is required"},
+	}
+	expected := []model.QuizImportResult{
+		{Row: 1, Status: model.QuizImportValid},
+		{Row: 2, Status: model.QuizImportDuplicate, DuplicateOfQuizID: savedQuizID},
+		{Row: 3, Status: model.QuizImportDuplicate, DuplicateOfRow: 1},
+		{Row: 4, Status: model.QuizImportInvalid, Error: "question is required"},
+	}
+
+	if _, err := usecase.ImportQuizzes(ctx, uuid.New(), rows, true); !errors.Is(err, model.ErrUserRoleHasNoAccess) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrUserRoleHasNoAccess, err)
+	}
+	report, err := usecase.ImportQuizzes(ctx, writerID, rows, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, result := range report.Results {
+		if result != expected[i] {
+			t.Errorf("Wrong result of row %v. Expected %v, got %v\n", i+1, expected[i], result)
+		}
+	}
+	if report.Created != 1 || report.Duplicates != 2 || report.Invalid != 1 {
+		t.Errorf("Wrong counts. Expected 1 created, 2 duplicates and 1 invalid, got %v\n", report)
+	}
+	if quizzes, _ := usecase.ExportQuizzes(ctx, writerID, model.QuizSearch{}); len(quizzes) != 1 {
+		t.Fatalf("Wrong quizzes. Expected nothing saved in the dry run, got %v\n", quizzes)
+	}
+
+	report, err = usecase.ImportQuizzes(ctx, writerID, rows, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := report.Results[0]
+	if created.Status != model.QuizImportCreated || created.QuizID == uuid.Nil {
+		t.Fatalf("Wrong result. Expected created quiz, got %v\n", created)
+	}
+	quizzes, err := usecase.ExportQuizzes(ctx, writerID, model.QuizSearch{AuthorID: writerID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quizzes) != 1 || quizzes[0].ID != created.QuizID || quizzes[0].Status != model.QuizStatusDraft {
+		t.Errorf("Wrong exported quizzes. Expected the draft %v of the writer, got %v\n", created.QuizID, quizzes)
+	}
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	http_errors "github.com/4units/mos-hack-game/back/pkg/http-errors"
+	logs "github.com/4units/mos-hack-game/back/pkg/logging"
+	"github.com/google/uuid"
+	"net/http"
+	"slices"
+)
+
+var (
+	ErrQuizStatusTransitionNotAllowed = http_errors.NewSame(
+		"quiz status can not be changed to the requested one", http.StatusConflict,
+	)
+	ErrQuizRejectionCommentRequired = http_errors.NewSame(
+		"comment is required to reject the quiz", http.StatusBadRequest,
+	)
+)
+
+// quizStatusTransitions are statuses which a quiz can get from its current one. Admins make any of them,
+// quiz writers can only submit their drafts for review and archive them.
+var quizStatusTransitions = map[model.QuizStatus][]model.QuizStatus{
+	model.QuizStatusDraft:     {model.QuizStatusInReview, model.QuizStatusArchived},
+	model.QuizStatusInReview:  {model.QuizStatusPublished, model.QuizStatusDraft, model.QuizStatusArchived},
+	model.QuizStatusPublished: {model.QuizStatusArchived},
+	model.QuizStatusArchived:  {model.QuizStatusDraft},
+}
+
+// ChangeQuizStatus moves the quiz to the status and records who made the change.
+// Moving a quiz from review back to the draft is a rejection, it requires a comment for the writer.
+func (q *QuizUsecase) ChangeQuizStatus(
+	ctx context.Context,
+	userID, quizID uuid.UUID,
+	status model.QuizStatus,
+	comment string,
+) error {
+	isAdmin, err := q.checkQuizEditor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	quiz, err := q.QuizStorage.GetQuizByID(ctx, quizID)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(quizStatusTransitions[quiz.Status], status) {
+		return ErrQuizStatusTransitionNotAllowed
+	}
+	if !isAdmin && (quiz.AuthorID != userID || quiz.Status != model.QuizStatusDraft) {
+		return model.ErrUserRoleHasNoAccess
+	}
+	if quiz.Status == model.QuizStatusInReview && status == model.QuizStatusDraft && comment == "" {
+		return ErrQuizRejectionCommentRequired
+	}
+	if err = q.QuizStorage.ChangeQuizStatus(
+		ctx, model.QuizStatusTransition{
+			QuizID:  quizID,
+			From:    quiz.Status,
+			To:      status,
+			UserID:  userID,
+			Comment: comment,
+		},
+	); err != nil {
+		return err
+	}
+	if quiz.Status != model.QuizStatusPublished && status != model.QuizStatusPublished {
+		return nil
+	}
+	// the quiz is shown or hidden at once on this instance, other ones pick it up with the next reload
+	if err = q.ReloadQuizIndex(ctx); err != nil {
+		logs.Error("failed to reload quiz index", err)
+	}
+	return nil
+}
+
+// ArchiveQuiz hides the quiz from players and the review, archived quizzes are kept for the history
+// and can be moved back to drafts by admins.
+func (q *QuizUsecase) ArchiveQuiz(ctx context.Context, userID, quizID uuid.UUID) error {
+	return q.ChangeQuizStatus(ctx, userID, quizID, model.QuizStatusArchived, "")
+}
+
+// GetQuizStatusTransitions returns the moderation history of the quiz to its author or an admin.
+func (q *QuizUsecase) GetQuizStatusTransitions(
+	ctx context.Context,
+	userID, quizID uuid.UUID,
+) ([]model.QuizStatusTransition, error) {
+	isAdmin, err := q.checkQuizEditor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	quiz, err := q.QuizStorage.GetQuizByID(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && quiz.AuthorID != userID {
+		return nil, model.ErrUserRoleHasNoAccess
+	}
+	transitions, err := q.QuizStorage.GetQuizStatusTransitions(ctx, quizID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz status transitions: %w", err)
+	}
+	return transitions, nil
+}
+
+// GetQuizzesForReview returns quizzes submitted for review to an admin, the oldest quiz is first.
+func (q *QuizUsecase) GetQuizzesForReview(ctx context.Context, userID uuid.UUID, limit int) ([]model.Quiz, error) {
+	if err := q.UserUsecase.CheckUserAnyRole(ctx, userID, []model.Role{model.RoleAdmin}); err != nil {
+		return nil, err
+	}
+	quizzes, err := q.QuizStorage.GetQuizzesByStatus(ctx, model.QuizStatusInReview, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quizzes in review: %w", err)
+	}
+	return quizzes, nil
+}
+
+// checkQuizEditor checks that the user is a quiz writer or an admin and returns true for admins.
+func (q *QuizUsecase) checkQuizEditor(ctx context.Context, userID uuid.UUID) (bool, error) {
+	if err := q.UserUsecase.CheckUserAnyRole(
+		ctx, userID, []model.Role{
+			model.RoleQuizWriter,
+			model.RoleAdmin,
+		},
+	); err != nil {
+		return false, err
+	}
+	err := q.UserUsecase.CheckUserAnyRole(ctx, userID, []model.Role{model.RoleAdmin})
+	if errors.Is(err, model.ErrUserRoleHasNoAccess) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
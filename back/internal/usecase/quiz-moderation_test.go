@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"testing"
+)
+
+// staleQuizStorageStub returns the quiz read before a concurrent change
+type staleQuizStorageStub struct {
+	*quizStorageStub
+	stale model.Quiz
+}
+
+func (s *staleQuizStorageStub) GetQuizByID(_ context.Context, _ uuid.UUID) (model.Quiz, error) {
+	return s.stale, nil
+}
+
+func TestQuizUsecase_ChangeQuizStatus(t *testing.T) {
+	ctx := context.Background()
+	writerID := uuid.New()
+	otherWriterID := uuid.New()
+	adminID := uuid.New()
+	usecase := newQuizUsecaseStub(&balanceStorageStub{})
+	usecase.UserUsecase = New(
+		UserUsecaseDeps{
+			UserStorage: &userStorageStub{
+				roles: map[uuid.UUID][]model.Role{
+					writerID:      {model.RoleQuizWriter},
+					otherWriterID: {model.RoleQuizWriter},
+					adminID:       {model.RoleAdmin},
+				},
+			},
+		},
+	)
+	quizID, err := usecase.AddQuiz(ctx, writerID, model.Quiz{Question: "q", Answers: []string{"a"}, CorrectAnswer: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		userID      uuid.UUID
+		status      model.QuizStatus
+		comment     string
+		expectedErr error
+	}{
+		{"approve draft", adminID, model.QuizStatusPublished, "", ErrQuizStatusTransitionNotAllowed},
+		{"submit draft of other writer", otherWriterID, model.QuizStatusInReview, "", model.ErrUserRoleHasNoAccess},
+		{"submit own draft", writerID, model.QuizStatusInReview, "", nil},
+		{"reject without comment", adminID, model.QuizStatusDraft, "", ErrQuizRejectionCommentRequired},
+		{"reject with comment", adminID, model.QuizStatusDraft, "fix the link", nil},
+		{"submit fixed draft", writerID, model.QuizStatusInReview, "", nil},
+		{"approve by writer", writerID, model.QuizStatusPublished, "", model.ErrUserRoleHasNoAccess},
+		{"approve by admin", adminID, model.QuizStatusPublished, "", nil},
+	}
+	for _, test := range tests {
+		err = usecase.ChangeQuizStatus(ctx, test.userID, quizID, test.status, test.comment)
+		if !errors.Is(err, test.expectedErr) {
+			t.Fatalf("Wrong error in %v. Expected %v, got %v\n", test.name, test.expectedErr, err)
+		}
+	}
+
+	quiz, err := usecase.GetRandomQuiz(ctx, uuid.New(), model.QuizFilter{})
+	if err != nil || quiz.ID != quizID {
+		t.Fatalf("Wrong quiz. Expected published quiz %v, got %v with error %v\n", quizID, quiz.ID, err)
+	}
+	question := "new"
+	err = usecase.UpdateQuiz(ctx, writerID, model.QuizUpdate{ID: quizID, Question: &question})
+	if !errors.Is(err, ErrQuizNotEditable) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", ErrQuizNotEditable, err)
+	}
+	transitions, err := usecase.GetQuizStatusTransitions(ctx, writerID, quizID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transitions) != 5 || transitions[2].Comment != "fix the link" || transitions[4].UserID != adminID {
+		t.Errorf("Wrong transitions. Expected creation and 4 changes with the rejection comment, got %v\n", transitions)
+	}
+
+	if err = usecase.ChangeQuizStatus(ctx, adminID, quizID, model.QuizStatusArchived, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = usecase.GetRandomQuiz(ctx, uuid.New(), model.QuizFilter{}); !errors.Is(err, ErrNoQuizExists) {
+		t.Errorf("Wrong error. Expected archived quiz to be hidden, got %v\n", err)
+	}
+}
+
+func TestQuizUsecase_UpdateQuiz_SubmittedConcurrently(t *testing.T) {
+	ctx := context.Background()
+	writerID := uuid.New()
+	usecase := newQuizUsecaseStub(&balanceStorageStub{})
+	usecase.UserUsecase = New(
+		UserUsecaseDeps{
+			UserStorage: &userStorageStub{roles: map[uuid.UUID][]model.Role{writerID: {model.RoleQuizWriter}}},
+		},
+	)
+	quizID, err := usecase.AddQuiz(ctx, writerID, model.Quiz{Question: "q", Answers: []string{"a"}, CorrectAnswer: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := usecase.QuizStorage.(*quizStorageStub)
+	draft, err := storage.GetQuizByID(ctx, quizID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the draft is submitted for review after the edit has read it
+	if err = usecase.ChangeQuizStatus(ctx, writerID, quizID, model.QuizStatusInReview, ""); err != nil {
+		t.Fatal(err)
+	}
+	usecase.QuizStorage = &staleQuizStorageStub{quizStorageStub: storage, stale: draft}
+	question := "new"
+	err = usecase.UpdateQuiz(ctx, writerID, model.QuizUpdate{ID: quizID, Question: &question})
+	if !errors.Is(err, model.ErrQuizChanged) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrQuizChanged, err)
+	}
+	if quiz, _ := storage.GetQuizByID(ctx, quizID); quiz.Question != "q" {
+		t.Errorf("Wrong question. Expected the reviewed question q, got %v\n", quiz.Question)
+	}
+}
@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	http_errors "github.com/4units/mos-hack-game/back/pkg/http-errors"
+	logs "github.com/4units/mos-hack-game/back/pkg/logging"
+	"github.com/google/uuid"
+	"net/http"
+	"slices"
+	"time"
+)
+
+var (
+	ErrQuizRoundTooManyQuestions = http_errors.NewSame("too many questions in quiz round", http.StatusBadRequest)
+	ErrQuizRoundQuestionExpired  = http_errors.NewSame(
+		"time of the quiz round question is over", http.StatusConflict,
+	)
+)
+
+type QuizRoundStorage interface {
+	AddQuizRound(ctx context.Context, round model.QuizRound) (uuid.UUID, error)
+	GetQuizRoundInProgress(ctx context.Context, userID uuid.UUID) (model.QuizRound, error)
+	// UpdateQuizRound must save the round only when it has answeredBefore answers and credit the reward
+	// of the finished round atomically, it returns ErrQuizRoundChanged when the round was changed
+	UpdateQuizRound(ctx context.Context, round model.QuizRound, answeredBefore int) error
+	// AnswerQuizRound must add the answer to the quiz history and save the round completed by the func
+	// by the rules of UpdateQuizRound atomically, it returns true when the answer is rewarded
+	AnswerQuizRound(
+		ctx context.Context,
+		userID uuid.UUID,
+		answer model.QuizAnswer,
+		answeredBefore int,
+		complete func(rewarded bool) model.QuizRound,
+	) (bool, error)
+}
+
+type QuizRoundUsecaseDeps struct {
+	QuizRoundStorage   QuizRoundStorage
+	QuizUsecase        *QuizUsecase
+	BalanceUsecase     *BalanceUsecase
+	LeaderboardUsecase *LeaderboardUsecase
+	QuizConfigProvider
+}
+
+type QuizRoundUsecase struct {
+	QuizRoundUsecaseDeps
+	now func() time.Time
+}
+
+func NewQuizRoundUsecase(deps QuizRoundUsecaseDeps) *QuizRoundUsecase {
+	return &QuizRoundUsecase{
+		QuizRoundUsecaseDeps: deps,
+		now:                  time.Now,
+	}
+}
+
+// StartRound starts a round of random quizzes matching the filter, zero questions means the count from the config.
+// The round has fewer questions when not enough quizzes match the filter.
+func (q *QuizRoundUsecase) StartRound(
+	ctx context.Context,
+	userID uuid.UUID,
+	filter model.QuizFilter,
+	questions int,
+) (model.QuizRoundProgress, error) {
+	roundCfg := q.QuizConfig().Round
+	if questions == 0 {
+		questions = roundCfg.Questions
+	}
+	if questions > roundCfg.MaxQuestions {
+		return model.QuizRoundProgress{}, ErrQuizRoundTooManyQuestions
+	}
+	// a round left by the user may be over already, then it is finished before starting a new one
+	progress, err := q.GetRound(ctx, userID)
+	if err == nil && progress.Question != nil {
+		return model.QuizRoundProgress{}, model.ErrQuizRoundInProgress
+	}
+	if err != nil && !errors.Is(err, model.ErrQuizRoundNotExists) {
+		return model.QuizRoundProgress{}, err
+	}
+
+	quizIDs, err := q.QuizUsecase.pickQuizIDs(ctx, userID, filter, questions)
+	if err != nil {
+		return model.QuizRoundProgress{}, err
+	}
+	now := q.now().UTC()
+	round := model.QuizRound{
+		UserID:            userID,
+		Category:          filter.Category,
+		Difficulty:        filter.Difficulty,
+		QuizIDs:           quizIDs,
+		Answers:           make([]model.QuizRoundAnswer, 0, len(quizIDs)),
+		QuestionTimeLimit: roundCfg.QuestionTimeLimit(),
+		QuestionStartedAt: now,
+		StartedAt:         now,
+	}
+	if round.ID, err = q.QuizRoundStorage.AddQuizRound(ctx, round); err != nil {
+		return model.QuizRoundProgress{}, err
+	}
+	q.addQuestionShow(ctx, round)
+	return q.roundProgress(ctx, round, now)
+}
+
+// GetRound returns the round in progress to resume it, questions which time is over are counted as wrong answers.
+// The round is returned once more with the summary when it is finished by the time of the last question.
+func (q *QuizRoundUsecase) GetRound(ctx context.Context, userID uuid.UUID) (model.QuizRoundProgress, error) {
+	round, err := q.QuizRoundStorage.GetQuizRoundInProgress(ctx, userID)
+	if err != nil {
+		return model.QuizRoundProgress{}, err
+	}
+	now := q.now().UTC()
+	answeredBefore := len(round.Answers)
+	expireQuizRoundQuestions(&round, now)
+	if len(round.Answers) > answeredBefore {
+		if err = q.saveRound(ctx, &round, answeredBefore, now); err != nil {
+			return model.QuizRoundProgress{}, err
+		}
+	}
+	return q.roundProgress(ctx, round, now)
+}
+
+// AnswerRound saves the answer to the current question of the round, the quiz id must be the id of the current
+// question, so answers sent after the time of the question do not go to the next one.
+func (q *QuizRoundUsecase) AnswerRound(
+	ctx context.Context,
+	userID, quizID uuid.UUID,
+	answer model.QuizAnswerValue,
+) (model.QuizRoundProgress, error) {
+	round, err := q.QuizRoundStorage.GetQuizRoundInProgress(ctx, userID)
+	if err != nil {
+		return model.QuizRoundProgress{}, err
+	}
+	now := q.now().UTC()
+	answeredBefore := len(round.Answers)
+	expireQuizRoundQuestions(&round, now)
+	if round.CurrentQuizID() != quizID {
+		if len(round.Answers) > answeredBefore {
+			if err = q.saveRound(ctx, &round, answeredBefore, now); err != nil {
+				return model.QuizRoundProgress{}, err
+			}
+		}
+		return model.QuizRoundProgress{}, ErrQuizRoundQuestionExpired
+	}
+
+	quiz, err := q.QuizUsecase.QuizStorage.GetQuizByID(ctx, quizID)
+	if err != nil {
+		return model.QuizRoundProgress{}, err
+	}
+	attempt, err := q.QuizUsecase.nextAnswerAttempt(ctx, userID, quizID)
+	if err != nil {
+		return model.QuizRoundProgress{}, err
+	}
+	roundAnswer := model.QuizRoundAnswer{
+		QuizID:  quizID,
+		Answer:  answer,
+		Correct: quiz.CheckAnswer(answer),
+		Time:    now.Sub(round.QuestionStartedAt),
+	}
+	if len(round.Answers)+1 >= len(round.QuizIDs) {
+		if err = q.ensureBalance(ctx, userID); err != nil {
+			return model.QuizRoundProgress{}, err
+		}
+	}
+	// the answer goes to the quiz history with the round, so a quiz answered correctly before is not rewarded
+	// in rounds again and the answer is not rewarded when the round was changed by a concurrent request
+	answered := round
+	rewarded, err := q.QuizRoundStorage.AnswerQuizRound(
+		ctx, userID, model.QuizAnswer{QuizID: quizID, Answer: answer, Correct: roundAnswer.Correct}, answeredBefore,
+		func(rewarded bool) model.QuizRound {
+			answered = round
+			roundAnswer := roundAnswer
+			if rewarded {
+				roundAnswer.Reward = quizRoundAnswerReward(
+					q.QuizUsecase.attemptReward(quiz, attempt),
+					q.QuizConfig().Round.SpeedBonusPercent,
+					roundAnswer.Time,
+					round.QuestionTimeLimit,
+				)
+			}
+			answered.Answers = append(slices.Clip(round.Answers), roundAnswer)
+			answered.QuestionStartedAt = now
+			finishQuizRound(&answered, now)
+			return answered
+		},
+	)
+	if err != nil {
+		return model.QuizRoundProgress{}, fmt.Errorf("failed to answer quiz round: %w", err)
+	}
+	if rewarded {
+		q.QuizUsecase.recordRewardedAnswer(ctx, userID)
+	}
+	if !answered.Finished() {
+		q.addQuestionShow(ctx, answered)
+	}
+	return q.roundProgress(ctx, answered, now)
+}
+
+// saveRound saves new answers of the round, the reward of the finished round is credited with it.
+func (q *QuizRoundUsecase) saveRound(
+	ctx context.Context,
+	round *model.QuizRound,
+	answeredBefore int,
+	now time.Time,
+) error {
+	finishQuizRound(round, now)
+	if round.Finished() && round.Reward > 0 {
+		if err := q.ensureBalance(ctx, round.UserID); err != nil {
+			return err
+		}
+	}
+	if err := q.QuizRoundStorage.UpdateQuizRound(ctx, *round, answeredBefore); err != nil {
+		return fmt.Errorf("failed to update quiz round: %w", err)
+	}
+	if !round.Finished() {
+		q.addQuestionShow(ctx, *round)
+	}
+	return nil
+}
+
+// ensureBalance creates the balance of the user before the round reward is credited to it.
+func (q *QuizRoundUsecase) ensureBalance(ctx context.Context, userID uuid.UUID) error {
+	if _, err := q.BalanceUsecase.GetUserBalance(ctx, userID); err != nil {
+		return fmt.Errorf("failed to get balance: %w", err)
+	}
+	return nil
+}
+
+// addQuestionShow records the show of the current question for the quiz stats and the answer time.
+func (q *QuizRoundUsecase) addQuestionShow(ctx context.Context, round model.QuizRound) {
+	if err := q.QuizUsecase.QuizAnswerStorage.AddQuizShow(ctx, round.UserID, round.CurrentQuizID()); err != nil {
+		logs.Error("failed to record quiz show", err)
+	}
+}
+
+func (q *QuizRoundUsecase) roundProgress(
+	ctx context.Context,
+	round model.QuizRound,
+	now time.Time,
+) (model.QuizRoundProgress, error) {
+	progress := model.QuizRoundProgress{Round: round}
+	if round.Finished() {
+		return progress, nil
+	}
+	quiz, err := q.QuizUsecase.QuizStorage.GetQuizByID(ctx, round.CurrentQuizID())
+	if err != nil {
+		return model.QuizRoundProgress{}, err
+	}
+	progress.Question = &quiz
+	progress.TimeLeft = max(round.QuestionStartedAt.Add(round.QuestionTimeLimit).Sub(now), 0)
+	return progress, nil
+}
+
+// expireQuizRoundQuestions adds wrong answers for questions which time is over,
+// the time of the next question starts when the time of the previous one is over.
+func expireQuizRoundQuestions(round *model.QuizRound, now time.Time) {
+	for !round.Finished() && !now.Before(round.QuestionStartedAt.Add(round.QuestionTimeLimit)) {
+		round.Answers = append(
+			round.Answers, model.QuizRoundAnswer{
+				QuizID:   round.CurrentQuizID(),
+				TimedOut: true,
+				Time:     round.QuestionTimeLimit,
+			},
+		)
+		round.QuestionStartedAt = round.QuestionStartedAt.Add(round.QuestionTimeLimit)
+	}
+}
+
+// finishQuizRound sums the reward of the round when all its questions are answered.
+func finishQuizRound(round *model.QuizRound, now time.Time) {
+	if !round.Finished() {
+		return
+	}
+	round.FinishedAt = now
+	round.Reward = 0
+	for _, answer := range round.Answers {
+		round.Reward += answer.Reward
+	}
+}
+
+// quizRoundAnswerReward adds the speed bonus to the reward, the bonus falls linearly to zero at the time limit.
+func quizRoundAnswerReward(reward, speedBonusPercent int, answerTime, timeLimit time.Duration) int {
+	if timeLimit <= 0 {
+		return reward
+	}
+	timeLeft := min(max(timeLimit-answerTime, 0), timeLimit)
+	bonusPercent := int(int64(speedBonusPercent) * int64(timeLeft) / int64(timeLimit))
+	return reward * (100 + bonusPercent) / 100
+}
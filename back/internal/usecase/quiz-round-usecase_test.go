@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/4units/mos-hack-game/back/config"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+type quizRoundStorageStub struct {
+	rounds []model.QuizRound
+	// answers get the history of round answers, balance gets rewards of finished rounds
+	answers *quizAnswerStorageStub
+	balance *balanceStorageStub
+}
+
+func (q *quizRoundStorageStub) AddQuizRound(_ context.Context, round model.QuizRound) (uuid.UUID, error) {
+	for _, saved := range q.rounds {
+		if saved.UserID == round.UserID && saved.FinishedAt.IsZero() {
+			return uuid.Nil, model.ErrQuizRoundInProgress
+		}
+	}
+	round.ID = uuid.New()
+	q.rounds = append(q.rounds, round)
+	return round.ID, nil
+}
+
+func (q *quizRoundStorageStub) GetQuizRoundInProgress(_ context.Context, userID uuid.UUID) (model.QuizRound, error) {
+	for _, round := range q.rounds {
+		if round.UserID == userID && round.FinishedAt.IsZero() {
+			round.Answers = append([]model.QuizRoundAnswer(nil), round.Answers...)
+			return round, nil
+		}
+	}
+	return model.QuizRound{}, model.ErrQuizRoundNotExists
+}
+
+func (q *quizRoundStorageStub) UpdateQuizRound(_ context.Context, round model.QuizRound, answeredBefore int) error {
+	for i, saved := range q.rounds {
+		if saved.ID == round.ID && saved.FinishedAt.IsZero() && len(saved.Answers) == answeredBefore {
+			q.rounds[i] = round
+			if !round.FinishedAt.IsZero() {
+				q.balance.softCurrency += round.Reward
+			}
+			return nil
+		}
+	}
+	return model.ErrQuizRoundChanged
+}
+
+func (q *quizRoundStorageStub) AnswerQuizRound(
+	ctx context.Context,
+	_ uuid.UUID,
+	answer model.QuizAnswer,
+	answeredBefore int,
+	complete func(rewarded bool) model.QuizRound,
+) (bool, error) {
+	answer.Rewarded = answer.Correct
+	for _, saved := range q.answers.answers {
+		if saved.QuizID == answer.QuizID && saved.Rewarded {
+			answer.Rewarded = false
+		}
+	}
+	// the answer is not saved when the round is not, as in one transaction
+	if err := q.UpdateQuizRound(ctx, complete(answer.Rewarded), answeredBefore); err != nil {
+		return false, err
+	}
+	q.answers.answers = append(q.answers.answers, answer)
+	return answer.Rewarded, nil
+}
+
+func newQuizRoundUsecaseStub(balance *balanceStorageStub, now *time.Time, quizList ...model.Quiz) *QuizRoundUsecase {
+	quizUsecase := newQuizUsecaseStub(balance, quizList...)
+	quizConfig := quizUsecase.QuizConfig()
+	quizConfig.Round = config.QuizRound{
+		Questions:         3,
+		MaxQuestions:      5,
+		QuestionSeconds:   10,
+		SpeedBonusPercent: 50,
+	}
+	usecase := NewQuizRoundUsecase(
+		QuizRoundUsecaseDeps{
+			QuizRoundStorage: &quizRoundStorageStub{
+				answers: quizUsecase.QuizAnswerStorage.(*quizAnswerStorageStub),
+				balance: balance,
+			},
+			QuizUsecase:        quizUsecase,
+			BalanceUsecase:     quizUsecase.BalanceUsecase,
+			LeaderboardUsecase: quizUsecase.LeaderboardUsecase,
+			QuizConfigProvider: quizUsecase.QuizConfigProvider,
+		},
+	)
+	usecase.now = func() time.Time { return *now }
+	return usecase
+}
+
+func TestQuizRoundUsecase_Round(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	balance := &balanceStorageStub{}
+	quizList := []model.Quiz{
+		{ID: uuid.New(), CorrectAnswer: 1, Category: "fraud", Difficulty: config.QuizDifficultyHard},
+		{ID: uuid.New(), CorrectAnswer: 1, Category: "fraud", Difficulty: config.QuizDifficultyHard},
+		{ID: uuid.New(), CorrectAnswer: 1, Category: "fraud", Difficulty: config.QuizDifficultyHard},
+		{ID: uuid.New(), CorrectAnswer: 1, Category: "deposits", Difficulty: config.QuizDifficultyHard},
+	}
+	usecase := newQuizRoundUsecaseStub(balance, &now, quizList...)
+
+	progress, err := usecase.StartRound(ctx, userID, model.QuizFilter{Category: "fraud"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(progress.Round.QuizIDs) != 3 || progress.Question == nil || progress.Question.Category != "fraud" {
+		t.Fatalf("Wrong round. Expected 3 questions of the category, got %v\n", progress)
+	}
+	if _, err = usecase.StartRound(ctx, userID, model.QuizFilter{}, 0); !errors.Is(err, model.ErrQuizRoundInProgress) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrQuizRoundInProgress, err)
+	}
+
+	// the correct answer in 2 of 10 seconds has 40% of the speed bonus
+	now = now.Add(2 * time.Second)
+	progress, err = usecase.AnswerRound(ctx, userID, progress.Question.ID, model.QuizAnswerValue{Option: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer := progress.Round.Answers[0]; !answer.Correct || answer.Reward != 112 {
+		t.Errorf("Wrong answer. Expected correct answer with reward 112, got %v\n", answer)
+	}
+
+	now = now.Add(5 * time.Second)
+	resumed, err := usecase.GetRound(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.Question.ID != progress.Question.ID || resumed.TimeLeft != 5*time.Second {
+		t.Errorf(
+			"Wrong resumed round. Expected question %v with 5s left, got %v with %v\n",
+			progress.Question.ID, resumed.Question.ID, resumed.TimeLeft,
+		)
+	}
+
+	progress, err = usecase.AnswerRound(ctx, userID, progress.Question.ID, model.QuizAnswerValue{Option: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer := progress.Round.Answers[1]; answer.Correct || answer.Reward != 0 {
+		t.Errorf("Wrong answer. Expected wrong answer without reward, got %v\n", answer)
+	}
+
+	now = now.Add(11 * time.Second)
+	_, err = usecase.AnswerRound(ctx, userID, progress.Question.ID, model.QuizAnswerValue{Option: 1})
+	if !errors.Is(err, ErrQuizRoundQuestionExpired) {
+		t.Fatalf("Wrong error. Expected %v, got %v\n", ErrQuizRoundQuestionExpired, err)
+	}
+	if _, err = usecase.GetRound(ctx, userID); !errors.Is(err, model.ErrQuizRoundNotExists) {
+		t.Errorf("Wrong error. Expected finished round, got %v\n", err)
+	}
+	if balance.softCurrency != 112 {
+		t.Errorf("Wrong balance. Expected 112, got %v\n", balance.softCurrency)
+	}
+}
+
+func TestQuizRoundUsecase_GetRound_ExpiresQuestions(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	usecase := newQuizRoundUsecaseStub(
+		&balanceStorageStub{}, &now,
+		model.Quiz{ID: uuid.New(), CorrectAnswer: 1},
+		model.Quiz{ID: uuid.New(), CorrectAnswer: 1},
+	)
+	if _, err := usecase.StartRound(ctx, userID, model.QuizFilter{}, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(25 * time.Second)
+	progress, err := usecase.GetRound(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress.Question != nil || !progress.Round.Finished() || progress.Round.Score() != 0 {
+		t.Fatalf("Wrong round. Expected finished round without correct answers, got %v\n", progress)
+	}
+	if progress.Round.Time() != 20*time.Second {
+		t.Errorf("Wrong round time. Expected 20s, got %v\n", progress.Round.Time())
+	}
+	if _, err = usecase.StartRound(ctx, userID, model.QuizFilter{}, 6); !errors.Is(err, ErrQuizRoundTooManyQuestions) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", ErrQuizRoundTooManyQuestions, err)
+	}
+	if _, err = usecase.StartRound(ctx, userID, model.QuizFilter{}, 0); err != nil {
+		t.Errorf("Wrong result. Expected a new round after the finished one, got %v\n", err)
+	}
+}
+
+func TestQuizRoundUsecase_AnswerRound_RewardsFirstCorrectAnswerOnce(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	balance := &balanceStorageStub{}
+	quiz := model.Quiz{ID: uuid.New(), CorrectAnswer: 1, Difficulty: config.QuizDifficultyHard}
+	usecase := newQuizRoundUsecaseStub(balance, &now, quiz)
+	usecase.QuizConfig().RetryRewardPercents = []int{50}
+
+	// the instant correct answer after the wrong one has half of the reward and the full speed bonus
+	for i, test := range []struct {
+		option         int
+		expectedReward int
+	}{
+		{2, 0},
+		{1, 60},
+		{1, 0},
+	} {
+		progress, err := usecase.StartRound(ctx, userID, model.QuizFilter{}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		progress, err = usecase.AnswerRound(ctx, userID, quiz.ID, model.QuizAnswerValue{Option: test.option})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if progress.Round.Reward != test.expectedReward {
+			t.Errorf("Wrong reward of round %v. Expected %v, got %v\n", i+1, test.expectedReward, progress.Round.Reward)
+		}
+	}
+	if balance.softCurrency != 60 {
+		t.Errorf("Wrong balance. Expected 60, got %v\n", balance.softCurrency)
+	}
+	history, err := usecase.QuizUsecase.GetQuizHistory(ctx, userID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 {
+		t.Errorf("Wrong quiz history. Expected 3 answers of rounds, got %v\n", history)
+	}
+}
+
+// staleQuizRoundStorageStub returns the round read before a concurrent answer
+type staleQuizRoundStorageStub struct {
+	*quizRoundStorageStub
+	stale model.QuizRound
+}
+
+func (s *staleQuizRoundStorageStub) GetQuizRoundInProgress(_ context.Context, _ uuid.UUID) (model.QuizRound, error) {
+	return s.stale, nil
+}
+
+func TestQuizRoundUsecase_AnswerRound_Concurrently(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	balance := &balanceStorageStub{}
+	quiz := model.Quiz{ID: uuid.New(), CorrectAnswer: 1, Difficulty: config.QuizDifficultyHard}
+	usecase := newQuizRoundUsecaseStub(balance, &now, quiz)
+	progress, err := usecase.StartRound(ctx, userID, model.QuizFilter{}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rounds := usecase.QuizRoundStorage.(*quizRoundStorageStub)
+	if _, err = usecase.AnswerRound(ctx, userID, quiz.ID, model.QuizAnswerValue{Option: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the correct answer of the request which read the round before the wrong one is not saved
+	usecase.QuizRoundStorage = &staleQuizRoundStorageStub{quizRoundStorageStub: rounds, stale: progress.Round}
+	_, err = usecase.AnswerRound(ctx, userID, quiz.ID, model.QuizAnswerValue{Option: 1})
+	if !errors.Is(err, model.ErrQuizRoundChanged) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrQuizRoundChanged, err)
+	}
+	if len(rounds.answers.answers) != 1 || rounds.answers.answers[0].Rewarded || balance.softCurrency != 0 {
+		t.Errorf(
+			"Wrong answers. Expected only the wrong answer without rewards, got %v and balance %v\n",
+			rounds.answers.answers, balance.softCurrency,
+		)
+	}
+}
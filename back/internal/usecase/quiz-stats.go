@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+)
+
+// GetQuizStats returns stats of answers to quizzes of any status to quiz writers and admins, so they find
+// questions which are too hard or misleading. Answers to single quizzes and to questions of rounds are counted.
+func (q *QuizUsecase) GetQuizStats(
+	ctx context.Context,
+	userID uuid.UUID,
+	search model.QuizStatsSearch,
+) (model.QuizStatsPage, error) {
+	if _, err := q.checkQuizEditor(ctx, userID); err != nil {
+		return model.QuizStatsPage{}, err
+	}
+	if search.Sort == "" {
+		search.Sort = model.QuizStatsSortCorrectRate
+	}
+	page, err := q.QuizAnswerStorage.GetQuizStats(ctx, search)
+	if err != nil {
+		return model.QuizStatsPage{}, fmt.Errorf("failed to get quiz stats: %w", err)
+	}
+	return page, nil
+}
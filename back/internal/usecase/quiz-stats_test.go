@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"testing"
+)
+
+func TestQuizUsecase_GetQuizStats(t *testing.T) {
+	ctx := context.Background()
+	writerID := uuid.New()
+	playerID := uuid.New()
+	quiz := model.Quiz{ID: uuid.New(), Answers: []string{"a", "b"}, CorrectAnswer: 2}
+	usecase := newQuizUsecaseStub(&balanceStorageStub{}, quiz)
+	usecase.UserUsecase = New(
+		UserUsecaseDeps{
+			UserStorage: &userStorageStub{
+				roles: map[uuid.UUID][]model.Role{writerID: {model.RoleQuizWriter}},
+			},
+		},
+	)
+	for _, answer := range []int{1, 2} {
+		if _, err := usecase.GetRandomQuiz(ctx, playerID, model.QuizFilter{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := usecase.TryCompleteQuiz(ctx, playerID, quiz.ID, model.QuizAnswerValue{Option: answer}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := usecase.GetQuizStats(ctx, playerID, model.QuizStatsSearch{}); !errors.Is(
+		err, model.ErrUserRoleHasNoAccess,
+	) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrUserRoleHasNoAccess, err)
+	}
+	page, err := usecase.GetQuizStats(ctx, writerID, model.QuizStatsSearch{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Stats) != 1 {
+		t.Fatalf("Wrong stats. Expected stats of 1 quiz, got %v\n", page.Stats)
+	}
+	stats := page.Stats[0]
+	if stats.Quiz.ID != quiz.ID || stats.Shown != 2 || stats.Answers != 2 || stats.CorrectRate() != 0.5 {
+		t.Errorf("Wrong stats. Expected 2 shows and 2 answers with a half correct, got %v\n", stats)
+	}
+}
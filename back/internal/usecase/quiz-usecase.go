@@ -6,36 +6,43 @@ import (
 	"github.com/4units/mos-hack-game/back/config"
 	"github.com/4units/mos-hack-game/back/internal/model"
 	http_errors "github.com/4units/mos-hack-game/back/pkg/http-errors"
+	logs "github.com/4units/mos-hack-game/back/pkg/logging"
 	"github.com/google/uuid"
-	"math/rand"
 	"net/http"
+	"sync/atomic"
+	"time"
 )
 
 var (
-	ErrNotCorrectAnswer = http_errors.NewSame("not correct quiz answer provided", http.StatusNotAcceptable)
 	ErrNoQuizExists     = http_errors.NewSame("no one quiz exists", http.StatusNotFound)
+	ErrQuizNotPublished = http_errors.NewSame("quiz is not published", http.StatusNotFound)
+	ErrQuizNotEditable  = http_errors.NewSame("quiz writers can edit only their drafts", http.StatusConflict)
 )
 
 type QuizStorage interface {
-	GetAllQuiz(ctx context.Context) ([]model.Quiz, error)
+	GetQuizIndex(ctx context.Context) ([]model.QuizIndexEntry, error)
 	GetQuizByID(ctx context.Context, id uuid.UUID) (model.Quiz, error)
-	AddQuiz(
-		ctx context.Context,
-		question string,
-		answers []string,
-		correctAnswer int,
-		linkInfo string,
-		answerDescription string,
-	) error
-	UpdateQuiz(
-		ctx context.Context,
-		quizID uuid.UUID,
-		question string,
-		answers []string,
-		correctAnswer int,
-		linkInfo string,
-		answerDescription string,
-	) error
+	GetQuizzesByStatus(ctx context.Context, status model.QuizStatus, limit int) ([]model.Quiz, error)
+	SearchQuizzes(ctx context.Context, search model.QuizSearch) (model.QuizPage, error)
+	GetQuizIDsByQuestions(ctx context.Context, normalizedQuestions []string) (map[string]uuid.UUID, error)
+	AddQuiz(ctx context.Context, quiz model.Quiz) (uuid.UUID, error)
+	AddQuizzes(ctx context.Context, quizzes []model.Quiz) ([]uuid.UUID, error)
+	// UpdateQuiz must return model.ErrQuizChanged when the status or the version of the quiz was changed
+	UpdateQuiz(ctx context.Context, quiz model.Quiz, editorID uuid.UUID) error
+	GetQuizVersions(ctx context.Context, quizID uuid.UUID) ([]model.QuizVersion, error)
+	ChangeQuizStatus(ctx context.Context, transition model.QuizStatusTransition) error
+	GetQuizStatusTransitions(ctx context.Context, quizID uuid.UUID) ([]model.QuizStatusTransition, error)
+}
+
+type QuizAnswerStorage interface {
+	AddQuizShow(ctx context.Context, userID, quizID uuid.UUID) error
+	// AddQuizAnswer returns true when the answer is the first correct answer of the user to the quiz
+	AddQuizAnswer(ctx context.Context, userID uuid.UUID, answer model.QuizAnswer) (bool, error)
+	CountQuizAnswers(ctx context.Context, userID, quizID uuid.UUID) (int, error)
+	IsQuizRewarded(ctx context.Context, userID, quizID uuid.UUID) (bool, error)
+	GetAnsweredQuizIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	GetQuizAnswers(ctx context.Context, userID uuid.UUID, limit int) ([]model.QuizAnswer, error)
+	GetQuizStats(ctx context.Context, search model.QuizStatsSearch) (model.QuizStatsPage, error)
 }
 
 type QuizConfigProvider interface {
@@ -43,101 +50,358 @@ type QuizConfigProvider interface {
 }
 
 type QuizUsecaseDeps struct {
-	QuizStorage    QuizStorage
-	UserUsecase    *UserUsecase
-	BalanceUsecase *BalanceUsecase
+	QuizStorage        QuizStorage
+	QuizAnswerStorage  QuizAnswerStorage
+	UserUsecase        *UserUsecase
+	BalanceUsecase     *BalanceUsecase
+	LeaderboardUsecase *LeaderboardUsecase
 	QuizConfigProvider
 }
 
 type QuizUsecase struct {
 	QuizUsecaseDeps
+	index atomic.Pointer[model.QuizIndex]
 }
 
 func NewQuizUsecase(deps QuizUsecaseDeps) *QuizUsecase {
 	return &QuizUsecase{QuizUsecaseDeps: deps}
 }
 
-func (q *QuizUsecase) TryCompleteQuiz(ctx context.Context, userID, quizID uuid.UUID, answer int) (int, error) {
+// ReloadQuizIndex loads ids, categories and difficulties of quizzes for the random selection.
+func (q *QuizUsecase) ReloadQuizIndex(ctx context.Context) error {
+	entries, err := q.QuizStorage.GetQuizIndex(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get quiz index: %w", err)
+	}
+	q.index.Store(model.NewQuizIndex(entries))
+	return nil
+}
+
+// WatchQuizIndex reloads the quiz index periodically to pick up quizzes added by other instances.
+func (q *QuizUsecase) WatchQuizIndex(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := q.ReloadQuizIndex(ctx); err != nil {
+				logs.Error("failed to reload quiz index", err)
+			}
+		}
+	}
+}
+
+func (q *QuizUsecase) getQuizIndex(ctx context.Context) (*model.QuizIndex, error) {
+	if index := q.index.Load(); index != nil {
+		return index, nil
+	}
+	if err := q.ReloadQuizIndex(ctx); err != nil {
+		return nil, err
+	}
+	return q.index.Load(), nil
+}
+
+// TryCompleteQuiz saves the answer to the history and returns the result with the quiz to explain the answer.
+// Only the first correct answer to the quiz is rewarded, its reward is reduced for retries by the retry policy.
+// The answer key is returned for wrong answers only when no rewarded retry is possible.
+func (q *QuizUsecase) TryCompleteQuiz(
+	ctx context.Context,
+	userID, quizID uuid.UUID,
+	answer model.QuizAnswerValue,
+) (model.QuizAnswerResult, error) {
 	quiz, err := q.QuizStorage.GetQuizByID(ctx, quizID)
 	if err != nil {
-		return 0, err
+		return model.QuizAnswerResult{}, err
+	}
+	if quiz.Status != model.QuizStatusPublished {
+		return model.QuizAnswerResult{}, ErrQuizNotPublished
+	}
+	result := model.QuizAnswerResult{
+		Correct: quiz.CheckAnswer(answer),
+		Quiz:    quiz,
+	}
+	if result.Attempt, result.Reward, err = q.addAnswer(ctx, userID, quiz, answer, result.Correct); err != nil {
+		return model.QuizAnswerResult{}, err
+	}
+	if result.KeyRevealed, err = q.answerKeyRevealed(ctx, userID, quiz, result); err != nil {
+		return model.QuizAnswerResult{}, err
+	}
+	if !result.KeyRevealed {
+		result.Quiz = quiz.WithoutAnswerKey()
+	}
+	if result.Reward == 0 {
+		return result, nil
+	}
+	if err = q.BalanceUsecase.AddSoftCurrency(ctx, userID, result.Reward); err != nil {
+		return model.QuizAnswerResult{}, err
+	}
+	return result, nil
+}
+
+// answerKeyRevealed returns false for a wrong answer while a rewarded retry is possible, otherwise the retry
+// with the key would be rewarded for the wrong answer given on purpose. Without the retry policy the key is
+// always revealed.
+func (q *QuizUsecase) answerKeyRevealed(
+	ctx context.Context,
+	userID uuid.UUID,
+	quiz model.Quiz,
+	result model.QuizAnswerResult,
+) (bool, error) {
+	cfg := q.QuizConfig()
+	if result.Correct || len(cfg.RetryRewardPercents) == 0 ||
+		cfg.AttemptReward(cfg.Reward(quiz.Difficulty), result.Attempt+1) == 0 {
+		return true, nil
+	}
+	rewarded, err := q.QuizAnswerStorage.IsQuizRewarded(ctx, userID, quiz.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check quiz reward: %w", err)
+	}
+	return rewarded, nil
+}
+
+// addAnswer saves the answer to the quiz and returns its attempt and reward, the reward is paid by the caller.
+// Only the first correct answer to the quiz in single quizzes or rounds is rewarded and counted on the leaderboard.
+func (q *QuizUsecase) addAnswer(
+	ctx context.Context,
+	userID uuid.UUID,
+	quiz model.Quiz,
+	answer model.QuizAnswerValue,
+	correct bool,
+) (int, int, error) {
+	attempt, err := q.nextAnswerAttempt(ctx, userID, quiz.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+	rewarded, err := q.QuizAnswerStorage.AddQuizAnswer(
+		ctx, userID, model.QuizAnswer{
+			QuizID:  quiz.ID,
+			Answer:  answer,
+			Correct: correct,
+		},
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to add quiz answer: %w", err)
 	}
-	if quiz.CorrectAnswer != answer {
-		return 0, ErrNotCorrectAnswer
+	if !rewarded {
+		return attempt, 0, nil
 	}
-	softCurrencyReward := q.QuizConfig().SoftCurrencyReward
-	if err = q.BalanceUsecase.AddSoftCurrency(ctx, userID, softCurrencyReward); err != nil {
-		return 0, err
+	q.recordRewardedAnswer(ctx, userID)
+	return attempt, q.attemptReward(quiz, attempt), nil
+}
+
+// nextAnswerAttempt returns the number of the next answer of the user to the quiz, the first one is 1.
+func (q *QuizUsecase) nextAnswerAttempt(ctx context.Context, userID, quizID uuid.UUID) (int, error) {
+	answers, err := q.QuizAnswerStorage.CountQuizAnswers(ctx, userID, quizID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count quiz answers: %w", err)
 	}
-	return softCurrencyReward, nil
+	return answers + 1, nil
 }
 
-func (q *QuizUsecase) GetRandomQuiz(ctx context.Context) (model.Quiz, error) {
-	quizList, err := q.QuizStorage.GetAllQuiz(ctx)
+// attemptReward returns the reward of the rewarded answer to the quiz with the attempt number.
+func (q *QuizUsecase) attemptReward(quiz model.Quiz, attempt int) int {
+	cfg := q.QuizConfig()
+	return cfg.AttemptReward(cfg.Reward(quiz.Difficulty), attempt)
+}
+
+// recordRewardedAnswer counts the rewarded answer on the leaderboard, failures are only logged.
+func (q *QuizUsecase) recordRewardedAnswer(ctx context.Context, userID uuid.UUID) {
+	if err := q.LeaderboardUsecase.AddQuizCorrectAnswer(ctx, userID); err != nil {
+		logs.Error("failed to record quiz answer", err)
+	}
+}
+
+// GetRandomQuiz returns a random quiz matching the filter which the user has not answered yet,
+// quizzes are repeated when the user has answered all of them. The show is recorded for the quiz stats.
+func (q *QuizUsecase) GetRandomQuiz(
+	ctx context.Context,
+	userID uuid.UUID,
+	filter model.QuizFilter,
+) (model.Quiz, error) {
+	index, err := q.getQuizIndex(ctx)
+	if err != nil {
+		return model.Quiz{}, err
+	}
+	answered, err := q.getAnsweredQuizIDs(ctx, userID)
 	if err != nil {
 		return model.Quiz{}, err
 	}
-	if quizList == nil || len(quizList) == 0 {
-		return model.Quiz{}, ErrNoQuizExists
+	// quizzes unpublished after the last reload of the index are still in it, so they are picked again
+	unpublished := make(map[uuid.UUID]bool)
+	for {
+		entry, ok := index.Pick(filter, answered)
+		if !ok || unpublished[entry.ID] {
+			return model.Quiz{}, ErrNoQuizExists
+		}
+		quiz, err := q.QuizStorage.GetQuizByID(ctx, entry.ID)
+		if err != nil {
+			return model.Quiz{}, err
+		}
+		if quiz.Status != model.QuizStatusPublished {
+			unpublished[entry.ID] = true
+			answered[entry.ID] = true
+			continue
+		}
+		if err = q.QuizAnswerStorage.AddQuizShow(ctx, userID, quiz.ID); err != nil {
+			logs.Error("failed to record quiz show", err)
+		}
+		return quiz, nil
 	}
-	randNum := rand.Intn(len(quizList))
-	return quizList[randNum], nil
 }
 
-func (q *QuizUsecase) AddQuiz(
-	ctx context.Context, userID uuid.UUID, question string, answers []string,
-	correctAnswer int, linkInfo, answerDescription string,
-) error {
-	if err := q.UserUsecase.CheckUserAnyRole(
-		ctx, userID, []model.Role{
-			model.RoleQuizWriter,
-			model.RoleAdmin,
-		},
-	); err != nil {
-		return err
+// pickQuizIDs returns ids of up to count different random quizzes matching the filter,
+// quizzes which the user has not answered yet are picked first.
+func (q *QuizUsecase) pickQuizIDs(
+	ctx context.Context,
+	userID uuid.UUID,
+	filter model.QuizFilter,
+	count int,
+) ([]uuid.UUID, error) {
+	index, err := q.getQuizIndex(ctx)
+	if err != nil {
+		return nil, err
 	}
-	if err := q.QuizStorage.AddQuiz(ctx, question, answers, correctAnswer, linkInfo, answerDescription); err != nil {
-		return err
+	answered, err := q.getAnsweredQuizIDs(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	// answered quizzes are excluded until all unanswered ones are picked
+	var (
+		picked   = make(map[uuid.UUID]bool, count)
+		excluded = answered
+		repeated bool
+		quizIDs  = make([]uuid.UUID, 0, count)
+	)
+	for len(quizIDs) < count {
+		entry, ok := index.Pick(filter, excluded)
+		if !ok {
+			break
+		}
+		if excluded[entry.ID] {
+			if repeated {
+				break
+			}
+			repeated = true
+			excluded = picked
+			continue
+		}
+		excluded[entry.ID] = true
+		picked[entry.ID] = true
+		quizIDs = append(quizIDs, entry.ID)
+	}
+	if len(quizIDs) == 0 {
+		return nil, ErrNoQuizExists
+	}
+	return quizIDs, nil
+}
+
+func (q *QuizUsecase) getAnsweredQuizIDs(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]bool, error) {
+	answeredIDs, err := q.QuizAnswerStorage.GetAnsweredQuizIDs(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get answered quiz ids: %w", err)
+	}
+	answered := make(map[uuid.UUID]bool, len(answeredIDs))
+	for _, quizID := range answeredIDs {
+		answered[quizID] = true
+	}
+	return answered, nil
+}
+
+// GetQuizHistory returns the last answers of the user, the newest answer is first.
+func (q *QuizUsecase) GetQuizHistory(ctx context.Context, userID uuid.UUID, limit int) ([]model.QuizAnswer, error) {
+	answers, err := q.QuizAnswerStorage.GetQuizAnswers(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz answers: %w", err)
+	}
+	return answers, nil
 }
 
-func (q *QuizUsecase) UpdateQuiz(
-	ctx context.Context, userID, quizID uuid.UUID, question string, answers []string,
-	correctAnswer int, linkInfo, answerDescription string,
-) error {
+// AddQuiz saves the quiz as a draft of the user and returns its id, the draft is shown to players
+// after it is submitted for review and approved by an admin.
+func (q *QuizUsecase) AddQuiz(ctx context.Context, userID uuid.UUID, quiz model.Quiz) (uuid.UUID, error) {
 	if err := q.UserUsecase.CheckUserAnyRole(
 		ctx, userID, []model.Role{
 			model.RoleQuizWriter,
 			model.RoleAdmin,
 		},
 	); err != nil {
+		return uuid.Nil, err
+	}
+	if err := quiz.ValidateAnswerKey(); err != nil {
+		return uuid.Nil, err
+	}
+	if quiz.Difficulty == "" {
+		quiz.Difficulty = config.QuizDifficultyMedium
+	}
+	quiz.Type = quiz.TypeOrDefault()
+	quiz.Status = model.QuizStatusDraft
+	quiz.AuthorID = userID
+	return q.QuizStorage.AddQuiz(ctx, quiz)
+}
+
+// UpdateQuiz replaces only fields present in the update and saves the quiz as the next version.
+// Quiz writers can edit only their drafts, admins can edit any quiz.
+func (q *QuizUsecase) UpdateQuiz(ctx context.Context, userID uuid.UUID, update model.QuizUpdate) error {
+	isAdmin, err := q.checkQuizEditor(ctx, userID)
+	if err != nil {
 		return err
 	}
-	quiz, err := q.QuizStorage.GetQuizByID(ctx, quizID)
+	quiz, err := q.QuizStorage.GetQuizByID(ctx, update.ID)
 	if err != nil {
-		return fmt.Errorf("failed to get quiz by id %v: %v", quizID, err)
+		return fmt.Errorf("failed to get quiz by id %v: %w", update.ID, err)
 	}
-	if question != "" {
-		quiz.Question = question
+	if !isAdmin && quiz.AuthorID != userID {
+		return model.ErrUserRoleHasNoAccess
 	}
-	if correctAnswer > 0 {
-		quiz.CorrectAnswer = correctAnswer
+	if !isAdmin && quiz.Status != model.QuizStatusDraft {
+		return ErrQuizNotEditable
 	}
-	if len(answers) > 0 {
-		quiz.Answers = answers
+	quiz = update.Apply(quiz)
+	if err = quiz.ValidateAnswerKey(); err != nil {
+		return err
 	}
-	if linkInfo != "" {
-		quiz.InfoLink = linkInfo
+	if err = q.QuizStorage.UpdateQuiz(ctx, quiz, userID); err != nil {
+		return err
 	}
-	if answerDescription != "" {
-		quiz.AnswerDescription = answerDescription
+	if quiz.Status != model.QuizStatusPublished {
+		return nil
 	}
-	if err = q.QuizStorage.UpdateQuiz(
-		ctx, quizID, quiz.Question, quiz.Answers, quiz.CorrectAnswer, quiz.InfoLink,
-		quiz.AnswerDescription,
-	); err != nil {
-		return err
+	// category and difficulty of the quiz may change its buckets in the index
+	if err = q.ReloadQuizIndex(ctx); err != nil {
+		logs.Error("failed to reload quiz index", err)
 	}
 	return nil
 }
+
+// SearchQuizzes returns a page of quizzes of any status to quiz writers and admins.
+func (q *QuizUsecase) SearchQuizzes(
+	ctx context.Context,
+	userID uuid.UUID,
+	search model.QuizSearch,
+) (model.QuizPage, error) {
+	if _, err := q.checkQuizEditor(ctx, userID); err != nil {
+		return model.QuizPage{}, err
+	}
+	page, err := q.QuizStorage.SearchQuizzes(ctx, search)
+	if err != nil {
+		return model.QuizPage{}, fmt.Errorf("failed to search quizzes: %w", err)
+	}
+	return page, nil
+}
+
+// GetQuizVersions returns the edit history of the quiz to quiz writers and admins, the newest version is first.
+func (q *QuizUsecase) GetQuizVersions(ctx context.Context, userID, quizID uuid.UUID) ([]model.QuizVersion, error) {
+	if _, err := q.checkQuizEditor(ctx, userID); err != nil {
+		return nil, err
+	}
+	versions, err := q.QuizStorage.GetQuizVersions(ctx, quizID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz versions: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, ErrNoQuizExists
+	}
+	return versions, nil
+}
@@ -0,0 +1,619 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/4units/mos-hack-game/back/config"
+	"github.com/4units/mos-hack-game/back/internal/model"
+	"github.com/google/uuid"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type quizStorageStub struct {
+	QuizStorage
+	quizList    []model.Quiz
+	transitions []model.QuizStatusTransition
+	versions    []model.QuizVersion
+}
+
+func (q *quizStorageStub) GetQuizIndex(_ context.Context) ([]model.QuizIndexEntry, error) {
+	entries := make([]model.QuizIndexEntry, 0, len(q.quizList))
+	for _, quiz := range q.quizList {
+		if quiz.Status != model.QuizStatusPublished {
+			continue
+		}
+		entries = append(
+			entries, model.QuizIndexEntry{
+				ID:         quiz.ID,
+				Category:   quiz.Category,
+				Difficulty: quiz.Difficulty,
+			},
+		)
+	}
+	return entries, nil
+}
+
+func (q *quizStorageStub) GetQuizByID(_ context.Context, id uuid.UUID) (model.Quiz, error) {
+	for _, quiz := range q.quizList {
+		if quiz.ID == id {
+			return quiz, nil
+		}
+	}
+	return model.Quiz{}, ErrNoQuizExists
+}
+
+func (q *quizStorageStub) AddQuiz(_ context.Context, quiz model.Quiz) (uuid.UUID, error) {
+	quiz.ID = uuid.New()
+	quiz.Version = 1
+	q.quizList = append(q.quizList, quiz)
+	q.versions = append(q.versions, model.QuizVersion{Version: 1, Quiz: quiz, EditorID: quiz.AuthorID})
+	q.transitions = append(
+		q.transitions, model.QuizStatusTransition{QuizID: quiz.ID, To: quiz.Status, UserID: quiz.AuthorID},
+	)
+	return quiz.ID, nil
+}
+
+func (q *quizStorageStub) AddQuizzes(ctx context.Context, quizzes []model.Quiz) ([]uuid.UUID, error) {
+	quizIDs := make([]uuid.UUID, 0, len(quizzes))
+	for _, quiz := range quizzes {
+		quizID, err := q.AddQuiz(ctx, quiz)
+		if err != nil {
+			return nil, err
+		}
+		quizIDs = append(quizIDs, quizID)
+	}
+	return quizIDs, nil
+}
+
+func (q *quizStorageStub) GetQuizIDsByQuestions(
+	_ context.Context,
+	normalizedQuestions []string,
+) (map[string]uuid.UUID, error) {
+	quizIDs := make(map[string]uuid.UUID)
+	for _, quiz := range q.quizList {
+		question := model.NormalizeQuizQuestion(quiz.Question)
+		if quiz.Status != model.QuizStatusArchived && slices.Contains(normalizedQuestions, question) {
+			quizIDs[question] = quiz.ID
+		}
+	}
+	return quizIDs, nil
+}
+
+func (q *quizStorageStub) UpdateQuiz(_ context.Context, quiz model.Quiz, editorID uuid.UUID) error {
+	for i, saved := range q.quizList {
+		if saved.ID == quiz.ID {
+			if saved.Status != quiz.Status || saved.Version != quiz.Version {
+				return model.ErrQuizChanged
+			}
+			quiz.Status, quiz.AuthorID, quiz.Version = saved.Status, saved.AuthorID, saved.Version+1
+			q.quizList[i] = quiz
+			q.versions = append(q.versions, model.QuizVersion{Version: quiz.Version, Quiz: quiz, EditorID: editorID})
+			return nil
+		}
+	}
+	return ErrNoQuizExists
+}
+
+func (q *quizStorageStub) GetQuizVersions(_ context.Context, quizID uuid.UUID) ([]model.QuizVersion, error) {
+	versions := make([]model.QuizVersion, 0)
+	for i := len(q.versions) - 1; i >= 0; i-- {
+		if q.versions[i].Quiz.ID == quizID {
+			versions = append(versions, q.versions[i])
+		}
+	}
+	return versions, nil
+}
+
+func (q *quizStorageStub) SearchQuizzes(_ context.Context, search model.QuizSearch) (model.QuizPage, error) {
+	page := model.QuizPage{Quizzes: make([]model.Quiz, 0)}
+	for _, quiz := range q.quizList {
+		if (search.Text == "" || strings.Contains(strings.ToLower(quiz.Question), strings.ToLower(search.Text))) &&
+			(search.Status == "" || quiz.Status == search.Status) &&
+			(search.AuthorID == uuid.Nil || quiz.AuthorID == search.AuthorID) {
+			if page.Total >= search.Offset && len(page.Quizzes) < search.Limit {
+				page.Quizzes = append(page.Quizzes, quiz)
+			}
+			page.Total++
+		}
+	}
+	return page, nil
+}
+
+func (q *quizStorageStub) ChangeQuizStatus(_ context.Context, transition model.QuizStatusTransition) error {
+	for i, quiz := range q.quizList {
+		if quiz.ID == transition.QuizID && quiz.Status == transition.From {
+			q.quizList[i].Status = transition.To
+			q.transitions = append(q.transitions, transition)
+			return nil
+		}
+	}
+	return model.ErrQuizStatusChanged
+}
+
+func (q *quizStorageStub) GetQuizStatusTransitions(
+	_ context.Context,
+	quizID uuid.UUID,
+) ([]model.QuizStatusTransition, error) {
+	transitions := make([]model.QuizStatusTransition, 0)
+	for _, transition := range q.transitions {
+		if transition.QuizID == quizID {
+			transitions = append(transitions, transition)
+		}
+	}
+	return transitions, nil
+}
+
+type quizAnswerStorageStub struct {
+	answers []model.QuizAnswer
+	// shows are ids of shown quizzes
+	shows []uuid.UUID
+}
+
+func (q *quizAnswerStorageStub) AddQuizShow(_ context.Context, _, quizID uuid.UUID) error {
+	q.shows = append(q.shows, quizID)
+	return nil
+}
+
+func (q *quizAnswerStorageStub) AddQuizAnswer(_ context.Context, _ uuid.UUID, answer model.QuizAnswer) (bool, error) {
+	if answer.Correct {
+		answer.Rewarded = true
+		for _, saved := range q.answers {
+			if saved.QuizID == answer.QuizID && saved.Rewarded {
+				answer.Rewarded = false
+			}
+		}
+	}
+	q.answers = append(q.answers, answer)
+	return answer.Rewarded, nil
+}
+
+func (q *quizAnswerStorageStub) CountQuizAnswers(_ context.Context, _, quizID uuid.UUID) (int, error) {
+	var count int
+	for _, answer := range q.answers {
+		if answer.QuizID == quizID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (q *quizAnswerStorageStub) IsQuizRewarded(_ context.Context, _, quizID uuid.UUID) (bool, error) {
+	for _, answer := range q.answers {
+		if answer.QuizID == quizID && answer.Rewarded {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (q *quizAnswerStorageStub) GetAnsweredQuizIDs(_ context.Context, _ uuid.UUID) ([]uuid.UUID, error) {
+	quizIDs := make([]uuid.UUID, 0, len(q.answers))
+	for _, answer := range q.answers {
+		quizIDs = append(quizIDs, answer.QuizID)
+	}
+	return quizIDs, nil
+}
+
+func (q *quizAnswerStorageStub) GetQuizAnswers(_ context.Context, _ uuid.UUID, limit int) ([]model.QuizAnswer, error) {
+	answers := make([]model.QuizAnswer, 0, limit)
+	for i := len(q.answers) - 1; i >= 0 && len(answers) < limit; i-- {
+		answers = append(answers, q.answers[i])
+	}
+	return answers, nil
+}
+
+func (q *quizAnswerStorageStub) GetQuizStats(
+	_ context.Context,
+	search model.QuizStatsSearch,
+) (model.QuizStatsPage, error) {
+	statsByQuizID := make(map[uuid.UUID]*model.QuizStats)
+	page := model.QuizStatsPage{Stats: make([]model.QuizStats, 0)}
+	quizStats := func(quizID uuid.UUID) *model.QuizStats {
+		if statsByQuizID[quizID] == nil {
+			statsByQuizID[quizID] = &model.QuizStats{Quiz: model.Quiz{ID: quizID}}
+		}
+		return statsByQuizID[quizID]
+	}
+	for _, quizID := range q.shows {
+		quizStats(quizID).Shown++
+	}
+	for _, answer := range q.answers {
+		stats := quizStats(answer.QuizID)
+		stats.Answers++
+		if answer.Correct {
+			stats.Correct++
+		}
+	}
+	for _, stats := range statsByQuizID {
+		if stats.Answers >= search.MinAnswers {
+			page.Stats = append(page.Stats, *stats)
+		}
+	}
+	page.Total = len(page.Stats)
+	return page, nil
+}
+
+type quizConfigStub struct {
+	cfg config.Quiz
+}
+
+func (q *quizConfigStub) QuizConfig() *config.Quiz {
+	return &q.cfg
+}
+
+// newQuizUsecaseStub publishes quizzes without a status
+func newQuizUsecaseStub(balance *balanceStorageStub, quizList ...model.Quiz) *QuizUsecase {
+	for i := range quizList {
+		if quizList[i].Status == "" {
+			quizList[i].Status = model.QuizStatusPublished
+		}
+	}
+	return NewQuizUsecase(
+		QuizUsecaseDeps{
+			QuizStorage:        &quizStorageStub{quizList: quizList},
+			QuizAnswerStorage:  &quizAnswerStorageStub{},
+			BalanceUsecase:     NewBalanceUsecase(BalanceUsecaseDeps{BalanceStorage: balance}),
+			LeaderboardUsecase: newLeaderboardUsecaseStub(),
+			QuizConfigProvider: &quizConfigStub{
+				cfg: config.Quiz{
+					SoftCurrencyReward: 40,
+					DifficultyRewards:  config.QuizDifficultyRewards{Hard: 80},
+				},
+			},
+		},
+	)
+}
+
+func TestQuizUsecase_TryCompleteQuiz_RewardsOnce(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	quiz := model.Quiz{ID: uuid.New(), Answers: []string{"a", "b"}, CorrectAnswer: 2}
+	balance := &balanceStorageStub{}
+	usecase := newQuizUsecaseStub(balance, quiz)
+
+	tests := []struct {
+		name            string
+		answer          int
+		expectedCorrect bool
+		expectedReward  int
+	}{
+		{"wrong answer", 1, false, 0},
+		{"first correct answer", 2, true, 40},
+		{"repeated correct answer", 2, true, 0},
+	}
+	for _, test := range tests {
+		result, err := usecase.TryCompleteQuiz(ctx, userID, quiz.ID, model.QuizAnswerValue{Option: test.answer})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Correct != test.expectedCorrect || result.Reward != test.expectedReward {
+			t.Errorf(
+				"Wrong result in %v. Expected correct %v and reward %v, got %v\n", test.name,
+				test.expectedCorrect, test.expectedReward, result,
+			)
+		}
+	}
+	if balance.softCurrency != 40 {
+		t.Errorf("Wrong balance. Expected 40, got %v\n", balance.softCurrency)
+	}
+	history, err := usecase.GetQuizHistory(ctx, userID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 || history[0].Rewarded || !history[1].Rewarded || history[2].Correct {
+		t.Errorf("Wrong history. Expected wrong, rewarded and repeated answers, got %v\n", history)
+	}
+}
+
+func TestQuizUsecase_GetRandomQuiz_PrefersUnanswered(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	answered := model.Quiz{ID: uuid.New(), CorrectAnswer: 1}
+	unanswered := model.Quiz{ID: uuid.New(), CorrectAnswer: 1}
+	usecase := newQuizUsecaseStub(&balanceStorageStub{}, answered, unanswered)
+	if _, err := usecase.TryCompleteQuiz(ctx, userID, answered.ID, model.QuizAnswerValue{Option: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 20 {
+		quiz, err := usecase.GetRandomQuiz(ctx, userID, model.QuizFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if quiz.ID != unanswered.ID {
+			t.Fatalf("Wrong quiz. Expected unanswered quiz %v, got %v\n", unanswered.ID, quiz.ID)
+		}
+	}
+
+	if _, err := usecase.TryCompleteQuiz(ctx, userID, unanswered.ID, model.QuizAnswerValue{Option: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := usecase.GetRandomQuiz(ctx, userID, model.QuizFilter{}); err != nil {
+		t.Errorf("Wrong result. Expected repeated quiz after all are answered, got %v\n", err)
+	}
+}
+
+func TestQuizUsecase_GetRandomQuiz_SkipsUnpublished(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	archived := model.Quiz{ID: uuid.New(), CorrectAnswer: 1}
+	published := model.Quiz{ID: uuid.New(), CorrectAnswer: 1}
+	usecase := newQuizUsecaseStub(&balanceStorageStub{}, archived, published)
+	if err := usecase.ReloadQuizIndex(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the quiz is archived after the index is loaded
+	quizzes := usecase.QuizStorage.(*quizStorageStub)
+	quizzes.quizList[0].Status = model.QuizStatusArchived
+	for range 20 {
+		quiz, err := usecase.GetRandomQuiz(ctx, userID, model.QuizFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if quiz.ID != published.ID {
+			t.Fatalf("Wrong quiz. Expected published quiz %v, got %v\n", published.ID, quiz.ID)
+		}
+	}
+	quizzes.quizList[1].Status = model.QuizStatusArchived
+	if _, err := usecase.GetRandomQuiz(ctx, userID, model.QuizFilter{}); !errors.Is(err, ErrNoQuizExists) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", ErrNoQuizExists, err)
+	}
+}
+
+func TestQuizUsecase_TryCompleteQuiz_DifficultyReward(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	hard := model.Quiz{ID: uuid.New(), CorrectAnswer: 1, Difficulty: config.QuizDifficultyHard}
+	easy := model.Quiz{ID: uuid.New(), CorrectAnswer: 1, Difficulty: config.QuizDifficultyEasy}
+	usecase := newQuizUsecaseStub(&balanceStorageStub{}, hard, easy)
+
+	tests := []struct {
+		name           string
+		quiz           model.Quiz
+		expectedReward int
+	}{
+		{"hard quiz", hard, 80},
+		{"easy quiz without own reward", easy, 40},
+	}
+	for _, test := range tests {
+		result, err := usecase.TryCompleteQuiz(ctx, userID, test.quiz.ID, model.QuizAnswerValue{Option: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Reward != test.expectedReward {
+			t.Errorf("Wrong reward in %v. Expected %v, got %v\n", test.name, test.expectedReward, result.Reward)
+		}
+	}
+}
+
+func TestQuizUsecase_TryCompleteQuiz_RetryReward(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	quiz := model.Quiz{
+		ID: uuid.New(), Answers: []string{"a", "b", "c"}, CorrectAnswer: 3,
+		InfoLink: "https://example.com/deposits", AnswerDescription: "Deposits are insured",
+	}
+	usecase := newQuizUsecaseStub(&balanceStorageStub{}, quiz)
+	usecase.QuizConfigProvider = &quizConfigStub{
+		cfg: config.Quiz{SoftCurrencyReward: 40, RetryRewardPercents: []int{50, 0}},
+	}
+
+	// the key is kept after the first wrong answer, so the half reward of the retry is not given for it
+	result, err := usecase.TryCompleteQuiz(ctx, userID, quiz.ID, model.QuizAnswerValue{Option: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Correct || result.Attempt != 1 || result.KeyRevealed || result.Quiz.CorrectAnswer != 0 ||
+		result.Quiz.InfoLink != quiz.InfoLink || result.Quiz.AnswerDescription != quiz.AnswerDescription {
+		t.Errorf("Wrong result. Expected the wrong first attempt with the explanation without the key, got %v\n", result)
+	}
+	result, err = usecase.TryCompleteQuiz(ctx, userID, quiz.ID, model.QuizAnswerValue{Option: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Correct || result.Attempt != 2 || result.Reward != 20 || !result.KeyRevealed {
+		t.Errorf("Wrong result. Expected the half reward for the second attempt, got %v\n", result)
+	}
+
+	// the third attempt is not rewarded, so the key is revealed after the second wrong answer
+	other := model.Quiz{
+		ID: uuid.New(), Answers: []string{"a", "b", "c"}, CorrectAnswer: 2, Status: model.QuizStatusPublished,
+	}
+	storage := usecase.QuizStorage.(*quizStorageStub)
+	storage.quizList = append(storage.quizList, other)
+	for i, test := range []struct {
+		option              int
+		expectedKeyRevealed bool
+		expectedReward      int
+	}{
+		{1, false, 0},
+		{3, true, 0},
+		{2, true, 0},
+	} {
+		result, err = usecase.TryCompleteQuiz(ctx, userID, other.ID, model.QuizAnswerValue{Option: test.option})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.KeyRevealed != test.expectedKeyRevealed || result.Reward != test.expectedReward ||
+			result.KeyRevealed && result.Quiz.CorrectAnswer != 2 {
+			t.Errorf(
+				"Wrong result of attempt %v. Expected key revealed %v and reward %v, got %v\n",
+				i+1, test.expectedKeyRevealed, test.expectedReward, result,
+			)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		attempt        int
+		expectedReward int
+	}{
+		{"first attempt", 1, 40},
+		{"second attempt", 2, 20},
+		{"third attempt", 3, 0},
+		{"attempt after the policy", 5, 0},
+	}
+	cfg := usecase.QuizConfig()
+	for _, test := range tests {
+		if reward := cfg.AttemptReward(40, test.attempt); reward != test.expectedReward {
+			t.Errorf("Wrong reward in %v. Expected %v, got %v\n", test.name, test.expectedReward, reward)
+		}
+	}
+}
+
+func TestQuizUsecase_TryCompleteQuiz_KeyRevealed(t *testing.T) {
+	tests := []struct {
+		name                string
+		retryRewardPercents []int
+		options             []int
+		expectedKeyRevealed []bool
+	}{
+		{"without retry policy", nil, []int{1, 1}, []bool{true, true}},
+		{"rewarded retry", []int{50}, []int{1, 2, 1}, []bool{false, true, true}},
+	}
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				ctx := context.Background()
+				userID := uuid.New()
+				quiz := model.Quiz{ID: uuid.New(), Answers: []string{"a", "b"}, CorrectAnswer: 2}
+				usecase := newQuizUsecaseStub(&balanceStorageStub{}, quiz)
+				usecase.QuizConfig().RetryRewardPercents = test.retryRewardPercents
+				// the wrong answer after the rewarded one reveals the key, no retry is rewarded then
+				for i, option := range test.options {
+					result, err := usecase.TryCompleteQuiz(ctx, userID, quiz.ID, model.QuizAnswerValue{Option: option})
+					if err != nil {
+						t.Fatal(err)
+					}
+					if result.KeyRevealed != test.expectedKeyRevealed[i] {
+						t.Errorf(
+							"Wrong key revealed of attempt %v. Expected %v, got %v\n",
+							i+1, test.expectedKeyRevealed[i], result.KeyRevealed,
+						)
+					}
+				}
+			},
+		)
+	}
+}
+
+func TestQuizUsecase_GetRandomQuiz_Filter(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	fraud := model.Quiz{ID: uuid.New(), Category: "fraud", Difficulty: config.QuizDifficultyHard}
+	deposits := model.Quiz{ID: uuid.New(), Category: "deposits", Difficulty: config.QuizDifficultyHard}
+	usecase := newQuizUsecaseStub(&balanceStorageStub{}, fraud, deposits)
+
+	for range 20 {
+		quiz, err := usecase.GetRandomQuiz(
+			ctx, userID, model.QuizFilter{Category: "fraud", Difficulty: config.QuizDifficultyHard},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if quiz.ID != fraud.ID {
+			t.Fatalf("Wrong quiz. Expected quiz %v of the category, got %v\n", fraud.ID, quiz.ID)
+		}
+	}
+	_, err := usecase.GetRandomQuiz(ctx, userID, model.QuizFilter{Difficulty: config.QuizDifficultyEasy})
+	if !errors.Is(err, ErrNoQuizExists) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", ErrNoQuizExists, err)
+	}
+}
+
+func TestQuizUsecase_SearchQuizzes_Versions(t *testing.T) {
+	ctx := context.Background()
+	writerID := uuid.New()
+	usecase := newQuizUsecaseStub(&balanceStorageStub{})
+	usecase.UserUsecase = New(
+		UserUsecaseDeps{
+			UserStorage: &userStorageStub{roles: map[uuid.UUID][]model.Role{writerID: {model.RoleQuizWriter}}},
+		},
+	)
+	questions := []string{"What is a deposit?", "What is a loan?", "How to spot a fraud call?"}
+	quizIDs := make([]uuid.UUID, 0, len(questions))
+	for _, question := range questions {
+		quizID, err := usecase.AddQuiz(
+			ctx, writerID, model.Quiz{Question: question, Answers: []string{"a"}, CorrectAnswer: 1},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		quizIDs = append(quizIDs, quizID)
+	}
+
+	page, err := usecase.SearchQuizzes(ctx, writerID, model.QuizSearch{Text: "WHAT", Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 2 || len(page.Quizzes) != 1 || page.Quizzes[0].ID != quizIDs[1] {
+		t.Errorf("Wrong page. Expected the second of 2 found quizzes, got %v\n", page)
+	}
+	if _, err = usecase.SearchQuizzes(ctx, uuid.New(), model.QuizSearch{Limit: 1}); !errors.Is(
+		err, model.ErrUserRoleHasNoAccess,
+	) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrUserRoleHasNoAccess, err)
+	}
+
+	question := "What is a savings account?"
+	update := model.QuizUpdate{ID: quizIDs[0], Question: &question}
+	if err = usecase.UpdateQuiz(ctx, writerID, update); err != nil {
+		t.Fatal(err)
+	}
+	versions, err := usecase.GetQuizVersions(ctx, writerID, quizIDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[0].Version != 2 || versions[1].Quiz.Question != questions[0] {
+		t.Errorf("Wrong versions. Expected the edit and the first version, got %v\n", versions)
+	}
+
+	if err = usecase.ArchiveQuiz(ctx, writerID, quizIDs[2]); err != nil {
+		t.Fatal(err)
+	}
+	page, err = usecase.SearchQuizzes(ctx, writerID, model.QuizSearch{Status: model.QuizStatusArchived, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 1 || page.Quizzes[0].ID != quizIDs[2] {
+		t.Errorf("Wrong page. Expected the archived quiz, got %v\n", page)
+	}
+}
+
+func TestQuizUsecase_UpdateQuiz_ChangesType(t *testing.T) {
+	ctx := context.Background()
+	writerID := uuid.New()
+	usecase := newQuizUsecaseStub(&balanceStorageStub{})
+	usecase.UserUsecase = New(
+		UserUsecaseDeps{
+			UserStorage: &userStorageStub{roles: map[uuid.UUID][]model.Role{writerID: {model.RoleQuizWriter}}},
+		},
+	)
+	quiz := model.Quiz{Question: "How many days in a leap year?", Answers: []string{"365", "366"}, CorrectAnswer: 2}
+	quizID, err := usecase.AddQuiz(ctx, writerID, quiz)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	numeric := model.QuizTypeNumeric
+	zero, days := 0, 366.0
+	update := model.QuizUpdate{ID: quizID, Type: &numeric, Answers: []string{}, NumericAnswer: &days}
+	if err = usecase.UpdateQuiz(ctx, writerID, update); !errors.Is(err, model.ErrQuizAnswerKeyOfOtherType) {
+		t.Errorf("Wrong error. Expected %v, got %v\n", model.ErrQuizAnswerKeyOfOtherType, err)
+	}
+	update.CorrectAnswer = &zero
+	if err = usecase.UpdateQuiz(ctx, writerID, update); err != nil {
+		t.Fatal(err)
+	}
+
+	quiz, err = usecase.QuizStorage.GetQuizByID(ctx, quizID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quiz.CorrectAnswer != 0 || len(quiz.Answers) != 0 || !quiz.CheckAnswer(model.QuizAnswerValue{Number: &days}) {
+		t.Errorf("Wrong quiz. Expected the numeric quiz without options, got %v\n", quiz)
+	}
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS line_game_level_group_drafts;
+DROP TABLE IF EXISTS line_game_levels;
+DROP TABLE IF EXISTS line_game_level_groups;
\ No newline at end of file
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS line_game_level_groups(
+	group_code VARCHAR(10) PRIMARY KEY,
+	field_size INT NOT NULL,
+	orders INT NOT NULL,
+	blockers INT NOT NULL,
+	published_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+	published_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS line_game_levels(
+	group_code VARCHAR(10) NOT NULL REFERENCES line_game_level_groups(group_code) ON DELETE CASCADE,
+	level_num INT NOT NULL,
+	level JSONB NOT NULL,
+	PRIMARY KEY (group_code, level_num)
+);
+
+CREATE TABLE IF NOT EXISTS line_game_level_group_drafts(
+	group_code VARCHAR(10) PRIMARY KEY,
+	content JSONB NOT NULL,
+	uploaded_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+	uploaded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
\ No newline at end of file
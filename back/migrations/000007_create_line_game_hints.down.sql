@@ -0,0 +1 @@
+DROP TABLE IF EXISTS line_game_hints;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS line_game_hints(
+	user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	group_code VARCHAR(10) NOT NULL,
+	level_num INT NOT NULL,
+	passed_count INT NOT NULL,
+	hint_type VARCHAR(16) NOT NULL,
+	kept_cells INT NOT NULL,
+	cells JSONB NOT NULL,
+	price INT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (user_id, group_code, level_num, passed_count, hint_type)
+);
\ No newline at end of file
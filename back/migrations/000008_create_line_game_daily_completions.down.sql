@@ -0,0 +1 @@
+DROP TABLE IF EXISTS line_game_daily_completions;
\ No newline at end of file
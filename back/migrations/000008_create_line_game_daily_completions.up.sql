@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS line_game_daily_completions(
+	user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	day DATE NOT NULL,
+	time_since_start INT NOT NULL,
+	reward INT NOT NULL,
+	streak INT NOT NULL,
+	completed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (user_id, day)
+);
\ No newline at end of file
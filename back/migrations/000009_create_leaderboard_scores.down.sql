@@ -0,0 +1 @@
+DROP TABLE IF EXISTS leaderboard_scores;
\ No newline at end of file
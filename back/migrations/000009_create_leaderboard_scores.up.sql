@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS leaderboard_scores(
+	board VARCHAR(48) NOT NULL,
+	period VARCHAR(16) NOT NULL,
+	user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	score INT NOT NULL,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (board, period, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_leaderboard_scores_board_score ON leaderboard_scores(board, period, score);
\ No newline at end of file
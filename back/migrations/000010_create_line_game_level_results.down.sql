@@ -0,0 +1 @@
+DROP TABLE IF EXISTS line_game_level_results;
\ No newline at end of file
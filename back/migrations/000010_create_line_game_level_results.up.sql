@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS line_game_level_results(
+	user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	passed_count INT NOT NULL,
+	group_code VARCHAR(10) NOT NULL,
+	level_num INT NOT NULL,
+	cells INT NOT NULL,
+	time_since_start INT NOT NULL,
+	hints_count INT NOT NULL,
+	policy VARCHAR(16) NOT NULL,
+	progression VARCHAR(16) NOT NULL,
+	completed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (user_id, passed_count)
+);
+
+CREATE INDEX IF NOT EXISTS idx_line_game_level_results_group ON line_game_level_results(user_id, group_code, passed_count);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS user_energy;
\ No newline at end of file
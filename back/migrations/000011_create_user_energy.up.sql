@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS user_energy(
+	user_id UUID PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+	energy INT NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	paid_passed_count INT NOT NULL DEFAULT -1
+);
\ No newline at end of file
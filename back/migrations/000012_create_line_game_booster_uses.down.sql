@@ -0,0 +1 @@
+DROP TABLE IF EXISTS line_game_booster_uses;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS line_game_booster_uses(
+	user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	group_code VARCHAR(10) NOT NULL,
+	level_num INT NOT NULL,
+	passed_count INT NOT NULL,
+	booster_id VARCHAR(32) NOT NULL,
+	uses INT NOT NULL,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (user_id, group_code, level_num, passed_count, booster_id)
+);
\ No newline at end of file
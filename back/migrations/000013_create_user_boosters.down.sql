@@ -0,0 +1 @@
+DROP TABLE IF EXISTS user_boosters;
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS user_boosters(
+	user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	booster_id VARCHAR(32) NOT NULL,
+	count INT NOT NULL CHECK (count >= 0),
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (user_id, booster_id)
+);
\ No newline at end of file
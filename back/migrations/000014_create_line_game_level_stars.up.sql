@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS line_game_level_stars(
+	user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	group_code VARCHAR(10) NOT NULL,
+	level_num INT NOT NULL,
+	stars INT NOT NULL,
+	best_time INT NOT NULL,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (user_id, group_code, level_num)
+);
\ No newline at end of file
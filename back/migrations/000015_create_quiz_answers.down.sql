@@ -0,0 +1 @@
+DROP TABLE IF EXISTS quiz_answers;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS quiz_answers(
+	answer_id BIGSERIAL PRIMARY KEY,
+	user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	quiz_id UUID NOT NULL REFERENCES quiz(quiz_id) ON DELETE CASCADE,
+	answer INT NOT NULL,
+	correct BOOLEAN NOT NULL,
+	rewarded BOOLEAN NOT NULL DEFAULT FALSE,
+	answered_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_answers_user ON quiz_answers(user_id, answered_at);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quiz_answers_rewarded ON quiz_answers(user_id, quiz_id) WHERE rewarded;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE quiz
+DROP COLUMN IF EXISTS tags,
+DROP COLUMN IF EXISTS difficulty,
+DROP COLUMN IF EXISTS category;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE quiz
+ADD COLUMN IF NOT EXISTS category VARCHAR(32) NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS difficulty VARCHAR(16) NOT NULL DEFAULT 'medium',
+ADD COLUMN IF NOT EXISTS tags JSONB NOT NULL DEFAULT '[]';
\ No newline at end of file
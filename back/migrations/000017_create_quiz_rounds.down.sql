@@ -0,0 +1 @@
+DROP TABLE IF EXISTS quiz_rounds;
\ No newline at end of file
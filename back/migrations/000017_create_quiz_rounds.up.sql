@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS quiz_rounds(
+	round_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	category VARCHAR(32) NOT NULL DEFAULT '',
+	difficulty VARCHAR(16) NOT NULL DEFAULT '',
+	quiz_ids JSONB NOT NULL,
+	answers JSONB NOT NULL DEFAULT '[]',
+	question_time_limit INT NOT NULL,
+	question_started_at TIMESTAMP NOT NULL,
+	started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	finished_at TIMESTAMP,
+	reward INT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quiz_rounds_in_progress ON quiz_rounds(user_id) WHERE finished_at IS NULL;
\ No newline at end of file
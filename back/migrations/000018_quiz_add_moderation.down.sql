@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS quiz_status_transitions;
+
+DROP INDEX IF EXISTS idx_quiz_status;
+
+ALTER TABLE quiz
+DROP COLUMN IF EXISTS created_at,
+DROP COLUMN IF EXISTS author_id,
+DROP COLUMN IF EXISTS status;
\ No newline at end of file
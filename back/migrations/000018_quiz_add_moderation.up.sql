@@ -0,0 +1,18 @@
+ALTER TABLE quiz
+ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'published',
+ADD COLUMN IF NOT EXISTS author_id UUID REFERENCES users(user_id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_quiz_status ON quiz(status, created_at);
+
+CREATE TABLE IF NOT EXISTS quiz_status_transitions(
+	transition_id BIGSERIAL PRIMARY KEY,
+	quiz_id UUID NOT NULL REFERENCES quiz(quiz_id) ON DELETE CASCADE,
+	from_status VARCHAR(16) NOT NULL DEFAULT '',
+	to_status VARCHAR(16) NOT NULL,
+	user_id UUID REFERENCES users(user_id) ON DELETE SET NULL,
+	comment TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_status_transitions_quiz ON quiz_status_transitions(quiz_id, created_at);
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_quiz_author;
+
+DROP TABLE IF EXISTS quiz_versions;
+
+ALTER TABLE quiz DROP COLUMN IF EXISTS version;
\ No newline at end of file
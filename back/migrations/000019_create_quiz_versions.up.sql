@@ -0,0 +1,27 @@
+ALTER TABLE quiz
+ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS quiz_versions(
+	quiz_id UUID NOT NULL REFERENCES quiz(quiz_id) ON DELETE CASCADE,
+	version INT NOT NULL,
+	content JSONB NOT NULL,
+	user_id UUID REFERENCES users(user_id) ON DELETE SET NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (quiz_id, version)
+);
+
+INSERT INTO quiz_versions(quiz_id, version, content, user_id, created_at)
+SELECT quiz_id, version, jsonb_build_object(
+	'question', question,
+	'answers', answers,
+	'correct_answer', correct_answer,
+	'info_link', info_link,
+	'answer_description', COALESCE(answer_description, ''),
+	'category', category,
+	'difficulty', difficulty,
+	'tags', tags
+), author_id, created_at
+FROM quiz
+ON CONFLICT DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS idx_quiz_author ON quiz(author_id, created_at);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_quiz_normalized_question;
+
+ALTER TABLE quiz DROP COLUMN IF EXISTS normalized_question;
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE quiz
+ADD COLUMN IF NOT EXISTS normalized_question TEXT NOT NULL DEFAULT '';
+
+UPDATE quiz
+SET normalized_question = rtrim(lower(regexp_replace(btrim(question), '\s+', ' ', 'g')), '?!.,;: ');
+
+CREATE INDEX IF NOT EXISTS idx_quiz_normalized_question ON quiz(normalized_question);
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_quiz_answers_quiz;
+
+ALTER TABLE quiz_answers DROP COLUMN IF EXISTS answer_time_ms;
+
+DROP TABLE IF EXISTS quiz_shows;
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS quiz_shows(
+	show_id BIGSERIAL PRIMARY KEY,
+	user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	quiz_id UUID NOT NULL REFERENCES quiz(quiz_id) ON DELETE CASCADE,
+	shown_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_shows_user ON quiz_shows(user_id, quiz_id, shown_at);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_shows_quiz ON quiz_shows(quiz_id);
+
+ALTER TABLE quiz_answers
+ADD COLUMN IF NOT EXISTS answer_time_ms BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_quiz_answers_quiz ON quiz_answers(quiz_id, answer);
\ No newline at end of file
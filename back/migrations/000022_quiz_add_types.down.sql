@@ -0,0 +1,9 @@
+ALTER TABLE quiz_answers
+DROP COLUMN IF EXISTS answer_number,
+DROP COLUMN IF EXISTS answer_options;
+
+ALTER TABLE quiz
+DROP COLUMN IF EXISTS numeric_tolerance,
+DROP COLUMN IF EXISTS numeric_answer,
+DROP COLUMN IF EXISTS correct_answers,
+DROP COLUMN IF EXISTS type;
\ No newline at end of file
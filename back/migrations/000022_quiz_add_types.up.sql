@@ -0,0 +1,9 @@
+ALTER TABLE quiz
+ADD COLUMN IF NOT EXISTS type VARCHAR(16) NOT NULL DEFAULT 'single_choice',
+ADD COLUMN IF NOT EXISTS correct_answers JSONB NOT NULL DEFAULT '[]',
+ADD COLUMN IF NOT EXISTS numeric_answer DOUBLE PRECISION NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS numeric_tolerance DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+ALTER TABLE quiz_answers
+ADD COLUMN IF NOT EXISTS answer_options JSONB,
+ADD COLUMN IF NOT EXISTS answer_number DOUBLE PRECISION;
\ No newline at end of file
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS line_game_hints_content_idx;
+
+DELETE FROM line_game_hints AS hints
+USING line_game_hints AS kept
+WHERE hints.user_id = kept.user_id
+	AND hints.group_code = kept.group_code
+	AND hints.level_num = kept.level_num
+	AND hints.passed_count = kept.passed_count
+	AND hints.hint_type = kept.hint_type
+	AND (hints.created_at, hints.ctid) > (kept.created_at, kept.ctid);
+
+ALTER TABLE line_game_hints
+ADD PRIMARY KEY (user_id, group_code, level_num, passed_count, hint_type);
\ No newline at end of file
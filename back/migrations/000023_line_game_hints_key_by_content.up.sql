@@ -0,0 +1,5 @@
+ALTER TABLE line_game_hints
+DROP CONSTRAINT IF EXISTS line_game_hints_pkey;
+
+CREATE UNIQUE INDEX IF NOT EXISTS line_game_hints_content_idx
+ON line_game_hints (user_id, group_code, level_num, passed_count, hint_type, kept_cells, md5(cells::TEXT));
\ No newline at end of file
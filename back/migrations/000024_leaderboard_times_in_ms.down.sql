@@ -0,0 +1,3 @@
+UPDATE leaderboard_scores
+SET score = score / 1000
+WHERE board LIKE 'level_time:%' OR board = 'daily_time';
\ No newline at end of file
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS line_game_level_attempts(
+	user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	passed_count INT NOT NULL,
+	started_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (user_id, passed_count)
+);
\ No newline at end of file
@@ -5,5 +5,10 @@ import (
 )
 
 func Error(message string, err error, attr ...slog.Attr) {
-	slog.Error(message, slog.String("err", err.Error()), attr)
+	args := make([]any, 0, len(attr)+1)
+	args = append(args, slog.String("err", err.Error()))
+	for _, a := range attr {
+		args = append(args, a)
+	}
+	slog.Error(message, args...)
 }
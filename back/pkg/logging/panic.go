@@ -11,8 +11,10 @@ func Panic(ctx context.Context, message string, panicValue any, attr ...slog.Att
 	stack := make([]byte, size)
 	stack = stack[:runtime.Stack(stack, false)]
 
-	slog.Log(
-		ctx, LevelPanic, message, slog.Any("panic", panicValue), slog.String("stack", string(stack)),
-		attr,
-	)
+	args := make([]any, 0, len(attr)+2)
+	args = append(args, slog.Any("panic", panicValue), slog.String("stack", string(stack)))
+	for _, a := range attr {
+		args = append(args, a)
+	}
+	slog.Log(ctx, LevelPanic, message, args...)
 }
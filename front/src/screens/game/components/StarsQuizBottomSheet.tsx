@@ -71,7 +71,6 @@ const StarsQuizBottomSheet = ({
         id: data.id,
         text: data.question,
         options: data.answer ?? [],
-        answerDescription: data.answer_description ?? undefined,
         infoLink: data.info_link ?? undefined,
       });
     } catch (error) {
@@ -118,10 +117,14 @@ const StarsQuizBottomSheet = ({
       setErrorMessage(null);
       setErrorOptionIndex(null);
       try {
-        const { soft_currency: rawReward } = await submitQuizAnswer({
-          id: question.id,
-          answer: optionIndex + 1,
-        });
+        const { soft_currency: rawReward, answer_description: answerDescription } =
+          await submitQuizAnswer({
+            id: question.id,
+            answer: optionIndex + 1,
+          });
+        setQuestion((current) =>
+          current ? { ...current, answerDescription: answerDescription ?? undefined } : current
+        );
         const reward = Math.max(rawReward ?? 0, 0);
         const isCorrect = reward > 0;
         setStatus(isCorrect ? 'correct' : 'wrong');
@@ -4,7 +4,6 @@ export type QuizQuestionResponse = {
   id: string;
   question: string;
   answer: string[];
-  answer_description?: string;
   info_link?: string;
 };
 
@@ -19,6 +18,7 @@ export type QuizAnswerRequest = {
 
 export type QuizAnswerResponse = {
   soft_currency: number;
+  answer_description?: string;
 };
 
 export const getQuizQuestion = async (): Promise<QuizQuestionResponse> => {